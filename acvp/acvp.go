@@ -0,0 +1,175 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package acvp runs ML-DSA test vectors of the NIST Automated Cryptographic
+// Validation Protocol ([ACVP]), as used for FIPS 140-3 validation.
+//
+// [Process] reads the JSON prompt of an ML-DSA vector set for the keyGen, sigGen
+// or sigVer mode of revision FIPS204, and returns the JSON response that an ACVP
+// proxy submits to the server. All the test groups of FIPS204 are supported: the
+// internal and external signature interfaces, pure ML-DSA and HashML-DSA, and
+// external mu.
+//
+// [ACVP]: https://pages.nist.gov/ACVP/draft-celi-acvp-ml-dsa.html
+package acvp
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/trailofbits/ml-dsa/internal/params"
+)
+
+// hexBytes is a byte string encoded in hex, in upper case as the ACVP server does.
+type hexBytes []byte
+
+func (h hexBytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.ToUpper(hex.EncodeToString(h)))
+}
+
+func (h *hexBytes) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return err
+	}
+	*h = b
+	return nil
+}
+
+// version is the first element of ACVP prompt and response files.
+type version struct {
+	ACVVersion string `json:"acvVersion"`
+}
+
+// vectorSet holds the fields common to the prompt and response of a vector set.
+// The test groups are decoded by the mode.
+type vectorSet struct {
+	VsID       int             `json:"vsId"`
+	Algorithm  string          `json:"algorithm"`
+	Mode       string          `json:"mode"`
+	Revision   string          `json:"revision"`
+	IsSample   bool            `json:"isSample"`
+	TestGroups json.RawMessage `json:"testGroups"`
+}
+
+// Process runs the tests of an ACVP prompt, and returns the response.
+//
+// The prompt is either a single vector set object, or an array made of the version
+// object followed by vector sets, as downloaded from the ACVP server. The response
+// has the same shape.
+func Process(prompt []byte) ([]byte, error) {
+	prompt = bytes.TrimSpace(prompt)
+	if len(prompt) == 0 || prompt[0] != '[' {
+		var vs vectorSet
+		if err := json.Unmarshal(prompt, &vs); err != nil {
+			return nil, fmt.Errorf("acvp: %w", err)
+		}
+		resp, err := processVectorSet(&vs)
+		if err != nil {
+			return nil, err
+		}
+		return json.MarshalIndent(resp, "", "  ")
+	}
+
+	var elems []json.RawMessage
+	if err := json.Unmarshal(prompt, &elems); err != nil {
+		return nil, fmt.Errorf("acvp: %w", err)
+	}
+	if len(elems) < 2 {
+		return nil, errors.New("acvp: expected the version object followed by vector sets")
+	}
+	var v version
+	if err := json.Unmarshal(elems[0], &v); err != nil || v.ACVVersion == "" {
+		return nil, errors.New("acvp: missing acvVersion")
+	}
+	resp := []any{v}
+	for _, elem := range elems[1:] {
+		var vs vectorSet
+		if err := json.Unmarshal(elem, &vs); err != nil {
+			return nil, fmt.Errorf("acvp: %w", err)
+		}
+		r, err := processVectorSet(&vs)
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, r)
+	}
+	return json.MarshalIndent(resp, "", "  ")
+}
+
+func processVectorSet(vs *vectorSet) (*vectorSet, error) {
+	if vs.Algorithm != "ML-DSA" {
+		return nil, fmt.Errorf("acvp: unsupported algorithm %q", vs.Algorithm)
+	}
+	if vs.Revision != "FIPS204" {
+		return nil, fmt.Errorf("acvp: unsupported revision %q", vs.Revision)
+	}
+
+	var groups any
+	var err error
+	switch vs.Mode {
+	case "keyGen":
+		groups, err = processGroups(vs.TestGroups, keyGen)
+	case "sigGen":
+		groups, err = processGroups(vs.TestGroups, sigGen)
+	case "sigVer":
+		groups, err = processGroups(vs.TestGroups, sigVer)
+	default:
+		return nil, fmt.Errorf("acvp: unsupported mode %q", vs.Mode)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("acvp: vector set %d: %w", vs.VsID, err)
+	}
+
+	resp := *vs
+	resp.TestGroups, err = json.Marshal(groups)
+	if err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// groupResponse is the response to a test group, made of test case responses of
+// type T.
+type groupResponse[T any] struct {
+	TgID  int `json:"tgId"`
+	Tests []T `json:"tests"`
+}
+
+// processGroups decodes test groups of type G, and runs each of them with f.
+func processGroups[G, T any](data json.RawMessage, f func(*G) (*groupResponse[T], error)) ([]*groupResponse[T], error) {
+	var groups []G
+	if err := json.Unmarshal(data, &groups); err != nil {
+		return nil, err
+	}
+	resp := make([]*groupResponse[T], 0, len(groups))
+	for i := range groups {
+		r, err := f(&groups[i])
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, r)
+	}
+	return resp, nil
+}
+
+func parameterSet(name string) (*params.Cfg, error) {
+	switch name {
+	case "ML-DSA-44":
+		return params.MLDSA44Cfg, nil
+	case "ML-DSA-65":
+		return params.MLDSA65Cfg, nil
+	case "ML-DSA-87":
+		return params.MLDSA87Cfg, nil
+	}
+	return nil, fmt.Errorf("unsupported parameter set %q", name)
+}
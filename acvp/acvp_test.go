@@ -0,0 +1,127 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package acvp
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/ml-dsa/internal"
+)
+
+func readTestdata(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile("testdata/" + name)
+	assert.NoError(t, err)
+	return data
+}
+
+// The keyGen and sigVer prompts and expected responses are taken from the vectors
+// in internal/test, followed by the vector sets of NIST ACVTS test session 667802
+// (see testdata/README.md). The sigGen prompt and expected response are those of
+// that test session.
+func TestKnownAnswers(t *testing.T) {
+	for _, mode := range []string{"keyGen", "sigGen", "sigVer"} {
+		t.Run(mode, func(t *testing.T) {
+			resp, err := Process(readTestdata(t, mode+"-prompt.json"))
+			assert.NoError(t, err)
+			assert.JSONEq(t, string(readTestdata(t, mode+"-expected.json")), string(resp))
+		})
+	}
+}
+
+// There are no known-answer sigGen vectors for HashML-DSA, so the signatures of
+// a sample vector set with a group for each interface, including preHash, are
+// checked by running them through sigVer.
+func TestSigGen(t *testing.T) {
+	prompt := readTestdata(t, "sigGen-sample-prompt.json")
+	resp, err := Process(prompt)
+	assert.NoError(t, err)
+
+	// Signing is deterministic given rnd
+	resp2, err := Process(prompt)
+	assert.NoError(t, err)
+	assert.Equal(t, resp, resp2)
+
+	var promptVS, respVS []map[string]any
+	assert.NoError(t, json.Unmarshal(prompt, &promptVS))
+	assert.NoError(t, json.Unmarshal(resp, &respVS))
+	sigVerVS := promptVS[1]
+	sigVerVS["mode"] = "sigVer"
+	for i, g := range sigVerVS["testGroups"].([]any) {
+		group := g.(map[string]any)
+		cfg, err := parameterSet(group["parameterSet"].(string))
+		assert.NoError(t, err)
+		respTests := respVS[1]["testGroups"].([]any)[i].(map[string]any)["tests"].([]any)
+		for j, tc := range group["tests"].([]any) {
+			test := tc.(map[string]any)
+			skBytes, err := hex.DecodeString(test["sk"].(string))
+			assert.NoError(t, err)
+			sk, err := internal.SkDecode(cfg, skBytes)
+			assert.NoError(t, err)
+			test["pk"] = hex.EncodeToString(sk.Public().Bytes())
+			test["signature"] = respTests[j].(map[string]any)["signature"]
+		}
+	}
+	sigVerPrompt, err := json.Marshal(promptVS)
+	assert.NoError(t, err)
+
+	var verified []struct {
+		TestGroups []groupResponse[sigVerResponse] `json:"testGroups"`
+	}
+	resp, err = Process(sigVerPrompt)
+	assert.NoError(t, err)
+	assert.NoError(t, json.Unmarshal(resp, &verified))
+	assert.Len(t, verified[1].TestGroups, 4)
+	for _, g := range verified[1].TestGroups {
+		for _, tc := range g.Tests {
+			assert.True(t, tc.TestPassed, "test case %d", tc.TcID)
+		}
+	}
+}
+
+func TestProcessVectorSet(t *testing.T) {
+	// A single vector set is processed without the version object
+	resp, err := Process([]byte(`{"vsId": 1, "algorithm": "ML-DSA", "mode": "keyGen", "revision": "FIPS204",
+		"testGroups": [{"tgId": 1, "testType": "AFT", "parameterSet": "ML-DSA-44", "tests": [
+		{"tcId": 1, "seed": "4BE7A01A99A5E5BCFE3C06785D8E4EC664082227D86704E9E44862623A05C8B3"}]}]}`))
+	assert.NoError(t, err)
+	var vs struct {
+		VsID       int                             `json:"vsId"`
+		TestGroups []groupResponse[keyGenResponse] `json:"testGroups"`
+	}
+	assert.NoError(t, json.Unmarshal(resp, &vs))
+	assert.Equal(t, 1, vs.VsID)
+	assert.Len(t, vs.TestGroups[0].Tests[0].PK, 1312)
+	assert.Len(t, vs.TestGroups[0].Tests[0].SK, 2560)
+}
+
+func TestProcessErrors(t *testing.T) {
+	for name, prompt := range map[string]string{
+		"empty":         ``,
+		"no vector set": `[{"acvVersion": "1.0"}]`,
+		"no version":    `[{}, {"algorithm": "ML-DSA", "mode": "keyGen", "revision": "FIPS204", "testGroups": []}]`,
+		"algorithm":     `{"algorithm": "ML-KEM", "mode": "keyGen", "revision": "FIPS203", "testGroups": []}`,
+		"revision":      `{"algorithm": "ML-DSA", "mode": "keyGen", "revision": "FIPS205", "testGroups": []}`,
+		"mode":          `{"algorithm": "ML-DSA", "mode": "encapDecap", "revision": "FIPS204", "testGroups": []}`,
+		"parameter set": `{"algorithm": "ML-DSA", "mode": "keyGen", "revision": "FIPS204",
+			"testGroups": [{"tgId": 1, "parameterSet": "ML-DSA-1", "tests": []}]}`,
+		"hex": `{"algorithm": "ML-DSA", "mode": "keyGen", "revision": "FIPS204",
+			"testGroups": [{"tgId": 1, "parameterSet": "ML-DSA-44", "tests": [{"tcId": 1, "seed": "XY"}]}]}`,
+		"seed": `{"algorithm": "ML-DSA", "mode": "keyGen", "revision": "FIPS204",
+			"testGroups": [{"tgId": 1, "parameterSet": "ML-DSA-44", "tests": [{"tcId": 1, "seed": "00"}]}]}`,
+		"hashAlg": `{"algorithm": "ML-DSA", "mode": "sigVer", "revision": "FIPS204",
+			"testGroups": [{"tgId": 1, "parameterSet": "ML-DSA-44", "signatureInterface": "external", "preHash": "preHash",
+			"tests": [{"tcId": 1, "hashAlg": "MD5"}]}]}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Process([]byte(prompt))
+			assert.Error(t, err)
+		})
+	}
+}
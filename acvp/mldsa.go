@@ -0,0 +1,227 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package acvp
+
+import (
+	"bytes"
+	"crypto"
+	"fmt"
+
+	"github.com/trailofbits/ml-dsa/internal"
+	options "github.com/trailofbits/ml-dsa/options"
+)
+
+// Pre-hash functions of the HashML-DSA test groups, by ACVP name.
+var hashes = map[string]options.Options{
+	"SHA2-224":     {Hash: crypto.SHA224},
+	"SHA2-256":     {Hash: crypto.SHA256},
+	"SHA2-384":     {Hash: crypto.SHA384},
+	"SHA2-512":     {Hash: crypto.SHA512},
+	"SHA2-512/224": {Hash: crypto.SHA512_224},
+	"SHA2-512/256": {Hash: crypto.SHA512_256},
+	"SHA3-224":     {Hash: crypto.SHA3_224},
+	"SHA3-256":     {Hash: crypto.SHA3_256},
+	"SHA3-384":     {Hash: crypto.SHA3_384},
+	"SHA3-512":     {Hash: crypto.SHA3_512},
+	"SHAKE-128":    {SHAKE: options.SHAKE128},
+	"SHAKE-256":    {SHAKE: options.SHAKE256},
+}
+
+// messageOptions returns the message to sign or verify with the external
+// interface, and its options: for HashML-DSA, the message is replaced by its digest.
+func messageOptions(preHash, hashAlg string, msg, context []byte) ([]byte, *options.Options, error) {
+	switch preHash {
+	case "pure":
+		return msg, &options.Options{Context: string(context)}, nil
+	case "preHash":
+	default:
+		return nil, nil, fmt.Errorf("unsupported preHash %q", preHash)
+	}
+
+	opts, ok := hashes[hashAlg]
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported hashAlg %q", hashAlg)
+	}
+	opts.Context = string(context)
+	digest, err := opts.PreHash(msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return digest, &opts, nil
+}
+
+type keyGenGroup struct {
+	TgID         int    `json:"tgId"`
+	ParameterSet string `json:"parameterSet"`
+	Tests        []struct {
+		TcID int      `json:"tcId"`
+		Seed hexBytes `json:"seed"`
+	} `json:"tests"`
+}
+
+type keyGenResponse struct {
+	TcID int      `json:"tcId"`
+	PK   hexBytes `json:"pk"`
+	SK   hexBytes `json:"sk"`
+}
+
+// keyGen runs ML-DSA.KeyGen_internal (Algorithm 6) on the seed of each test.
+func keyGen(g *keyGenGroup) (*groupResponse[keyGenResponse], error) {
+	cfg, err := parameterSet(g.ParameterSet)
+	if err != nil {
+		return nil, err
+	}
+	resp := &groupResponse[keyGenResponse]{TgID: g.TgID}
+	for _, tc := range g.Tests {
+		sk, err := internal.FromSeed(cfg, tc.Seed)
+		if err != nil {
+			return nil, fmt.Errorf("test case %d: %w", tc.TcID, err)
+		}
+		resp.Tests = append(resp.Tests, keyGenResponse{
+			TcID: tc.TcID,
+			PK:   sk.Public().Bytes(),
+			SK:   sk.EncodeExpanded(),
+		})
+		sk.Destroy()
+	}
+	return resp, nil
+}
+
+type sigGenGroup struct {
+	TgID               int    `json:"tgId"`
+	ParameterSet       string `json:"parameterSet"`
+	Deterministic      bool   `json:"deterministic"`
+	SignatureInterface string `json:"signatureInterface"`
+	PreHash            string `json:"preHash"`
+	ExternalMu         bool   `json:"externalMu"`
+	// SK is the private key of all the tests in older vector sets
+	SK    hexBytes `json:"sk"`
+	Tests []struct {
+		TcID    int      `json:"tcId"`
+		SK      hexBytes `json:"sk"`
+		Message hexBytes `json:"message"`
+		Mu      hexBytes `json:"mu"`
+		Context hexBytes `json:"context"`
+		Rnd     hexBytes `json:"rnd"`
+		HashAlg string   `json:"hashAlg"`
+	} `json:"tests"`
+}
+
+type sigGenResponse struct {
+	TcID      int      `json:"tcId"`
+	Signature hexBytes `json:"signature"`
+}
+
+// sigGen signs the message or mu of each test, with the interface of the group.
+// Vector sets without a signatureInterface predate the external interface, and
+// use the internal one.
+func sigGen(g *sigGenGroup) (*groupResponse[sigGenResponse], error) {
+	cfg, err := parameterSet(g.ParameterSet)
+	if err != nil {
+		return nil, err
+	}
+	resp := &groupResponse[sigGenResponse]{TgID: g.TgID}
+	for _, tc := range g.Tests {
+		encoded := tc.SK
+		if encoded == nil {
+			encoded = g.SK
+		}
+		sk, err := internal.SkDecode(cfg, encoded)
+		if err != nil {
+			return nil, fmt.Errorf("test case %d: %w", tc.TcID, err)
+		}
+
+		rnd := make([]byte, 32)
+		if !g.Deterministic {
+			if len(tc.Rnd) != 32 {
+				return nil, fmt.Errorf("test case %d: rnd must be 32 bytes long", tc.TcID)
+			}
+			rnd = tc.Rnd
+		}
+
+		var sig []byte
+		switch {
+		case g.ExternalMu:
+			sig, err = sk.SignMu(bytes.NewReader(rnd), tc.Mu)
+		case g.SignatureInterface == "internal" || g.SignatureInterface == "":
+			sig = sk.SignInternal(tc.Message, rnd)
+		case g.SignatureInterface == "external":
+			var msg []byte
+			var opts *options.Options
+			msg, opts, err = messageOptions(g.PreHash, tc.HashAlg, tc.Message, tc.Context)
+			if err == nil {
+				sig, err = sk.Sign(bytes.NewReader(rnd), msg, opts)
+			}
+		default:
+			err = fmt.Errorf("unsupported signatureInterface %q", g.SignatureInterface)
+		}
+		sk.Destroy()
+		if err != nil {
+			return nil, fmt.Errorf("test case %d: %w", tc.TcID, err)
+		}
+		resp.Tests = append(resp.Tests, sigGenResponse{TcID: tc.TcID, Signature: sig})
+	}
+	return resp, nil
+}
+
+type sigVerGroup struct {
+	TgID               int    `json:"tgId"`
+	ParameterSet       string `json:"parameterSet"`
+	SignatureInterface string `json:"signatureInterface"`
+	PreHash            string `json:"preHash"`
+	ExternalMu         bool   `json:"externalMu"`
+	// PK is the public key of all the tests in older vector sets
+	PK    hexBytes `json:"pk"`
+	Tests []struct {
+		TcID      int      `json:"tcId"`
+		PK        hexBytes `json:"pk"`
+		Message   hexBytes `json:"message"`
+		Mu        hexBytes `json:"mu"`
+		Context   hexBytes `json:"context"`
+		HashAlg   string   `json:"hashAlg"`
+		Signature hexBytes `json:"signature"`
+	} `json:"tests"`
+}
+
+type sigVerResponse struct {
+	TcID       int  `json:"tcId"`
+	TestPassed bool `json:"testPassed"`
+}
+
+// sigVer verifies the signature of each test, with the interface of the group.
+func sigVer(g *sigVerGroup) (*groupResponse[sigVerResponse], error) {
+	cfg, err := parameterSet(g.ParameterSet)
+	if err != nil {
+		return nil, err
+	}
+	resp := &groupResponse[sigVerResponse]{TgID: g.TgID}
+	for _, tc := range g.Tests {
+		encoded := tc.PK
+		if encoded == nil {
+			encoded = g.PK
+		}
+
+		// A public key that does not decode fails the test, after the other
+		// inputs have been checked
+		vk, keyErr := internal.PkDecode(cfg, encoded)
+		var ok bool
+		switch {
+		case g.ExternalMu:
+			ok = keyErr == nil && vk.VerifyMu(tc.Mu, tc.Signature)
+		case g.SignatureInterface == "internal" || g.SignatureInterface == "":
+			ok = keyErr == nil && vk.VerifyInternal(tc.Message, tc.Signature)
+		case g.SignatureInterface == "external":
+			msg, opts, err := messageOptions(g.PreHash, tc.HashAlg, tc.Message, tc.Context)
+			if err != nil {
+				return nil, fmt.Errorf("test case %d: %w", tc.TcID, err)
+			}
+			ok = keyErr == nil && vk.Verify(msg, tc.Signature, opts)
+		default:
+			return nil, fmt.Errorf("unsupported signatureInterface %q", g.SignatureInterface)
+		}
+		resp.Tests = append(resp.Tests, sigVerResponse{TcID: tc.TcID, TestPassed: ok})
+	}
+	return resp, nil
+}
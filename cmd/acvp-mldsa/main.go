@@ -0,0 +1,65 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Command acvp-mldsa runs the ML-DSA tests of an ACVP prompt, for use with an
+// ACVP proxy.
+//
+// Usage:
+//
+//	acvp-mldsa [-out response.json] [prompt.json]
+//
+// The prompt is read from the file, or standard input if it is omitted or "-".
+// The response is written to standard output, unless -out names a file.
+// See package github.com/trailofbits/ml-dsa/acvp for the supported vector sets.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/trailofbits/ml-dsa/acvp"
+)
+
+func main() {
+	out := flag.String("out", "", "response output `file` (default stdout)")
+	flag.Usage = func() {
+		fmt.Fprintln(flag.CommandLine.Output(), "usage: acvp-mldsa [-out response.json] [prompt.json]")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() > 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	if err := run(flag.Arg(0), *out); err != nil {
+		fmt.Fprintln(os.Stderr, "acvp-mldsa:", err)
+		os.Exit(1)
+	}
+}
+
+func run(in, out string) error {
+	var prompt []byte
+	var err error
+	if in == "" || in == "-" {
+		prompt, err = io.ReadAll(os.Stdin)
+	} else {
+		prompt, err = os.ReadFile(in)
+	}
+	if err != nil {
+		return err
+	}
+
+	resp, err := acvp.Process(prompt)
+	if err != nil {
+		return err
+	}
+	resp = append(resp, '\n')
+	if out == "" || out == "-" {
+		_, err = os.Stdout.Write(resp)
+		return err
+	}
+	return os.WriteFile(out, resp, 0o644)
+}
@@ -0,0 +1,286 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/trailofbits/ml-dsa/mldsa"
+	"github.com/trailofbits/ml-dsa/ssh"
+	"github.com/trailofbits/ml-dsa/ssh/sshsig"
+	xssh "golang.org/x/crypto/ssh"
+)
+
+// readPrivateKey reads an OpenSSH or PKCS #8 private key. If path holds a public
+// key and ends in ".pub", the private key is read from path without the suffix.
+func readPrivateKey(path string) (mldsa.PrivateKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	switch {
+	case block != nil && block.Type == "PRIVATE KEY":
+		return mldsa.ParsePrivateKeyPEM(data)
+	case block != nil:
+		priv, _, err := ssh.ParseRawPrivateKey(data)
+		return priv, err
+	}
+	if _, _, _, _, err := ssh.ParseAuthorizedKey(data); err == nil && strings.HasSuffix(path, ".pub") {
+		return readPrivateKey(strings.TrimSuffix(path, ".pub"))
+	}
+	return nil, fmt.Errorf("%s: not an ML-DSA private key", path)
+}
+
+// verifyTime returns the time of the verify-time option, or the current time.
+func verifyTime(f *flags) (time.Time, error) {
+	value, err := f.option("verify-time", "verify-time")
+	if err != nil || value == "" {
+		return time.Now(), err
+	}
+	return sshsig.ParseTime(value)
+}
+
+func readSignature(f *flags) (*sshsig.Signature, error) {
+	if f.sigFile == "" {
+		return nil, errors.New("missing signature file (-s)")
+	}
+	data, err := os.ReadFile(f.sigFile)
+	if err != nil {
+		return nil, err
+	}
+	return sshsig.ParseSignature(data)
+}
+
+func readAllowedSigners(f *flags) ([]*sshsig.AllowedSigner, error) {
+	if f.keyFile == "" {
+		return nil, errors.New("missing allowed signers file (-f)")
+	}
+	data, err := os.ReadFile(f.keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return sshsig.ParseAllowedSigners(data)
+}
+
+// keyDescription returns the parameter set and fingerprint of pub, as printed
+// by ssh-keygen.
+func keyDescription(pub *ssh.PublicKey) string {
+	return mldsa.SchemeOf(pub.CryptoPublicKey()).Name() + " key " + xssh.FingerprintSHA256(pub)
+}
+
+func sign(f *flags, stdin io.Reader, stdout, stderr io.Writer) error {
+	if f.keyFile == "" || f.namespace == "" {
+		return errors.New("sign: -f and -n are required")
+	}
+	hashAlgorithm, err := f.option("hashalg", "hashalg")
+	if err != nil {
+		return err
+	}
+	priv, err := readPrivateKey(f.keyFile)
+	if err != nil {
+		return err
+	}
+	defer priv.Destroy()
+
+	if len(f.files) == 0 || (len(f.files) == 1 && f.files[0] == "-") {
+		sig, err := sshsig.Sign(priv, stdin, f.namespace, hashAlgorithm)
+		if err != nil {
+			return err
+		}
+		_, err = stdout.Write(sig.Armor())
+		return err
+	}
+	for _, file := range f.files {
+		msg, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		sig, err := sshsig.Sign(priv, bytes.NewReader(msg), f.namespace, hashAlgorithm)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(stderr, "Signing file %s\n", file)
+		if err := os.WriteFile(file+".sig", sig.Armor(), 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintf(stderr, "Write signature to %s.sig\n", file)
+	}
+	return nil
+}
+
+func verify(f *flags, stdin io.Reader, stdout io.Writer) error {
+	if f.namespace == "" || f.principal == "" {
+		return errors.New("verify: -I and -n are required")
+	}
+	t, err := verifyTime(f)
+	if err != nil {
+		return err
+	}
+	signers, err := readAllowedSigners(f)
+	if err != nil {
+		return err
+	}
+	sig, err := readSignature(f)
+	if err != nil {
+		return err
+	}
+	if err := sig.Verify(stdin, f.namespace); err != nil {
+		return err
+	}
+	if f.revocation != "" {
+		if err := checkRevocation(f.revocation, sig.PublicKey); err != nil {
+			return err
+		}
+	}
+	for _, s := range signers {
+		if s.MatchPrincipal(f.principal) && s.Allows(sig.PublicKey, f.namespace, t) {
+			fmt.Fprintf(stdout, "Good %q signature for %s with %s\n", f.namespace, f.principal, keyDescription(sig.PublicKey))
+			return nil
+		}
+	}
+	return fmt.Errorf("%s is not an allowed signer for %s", keyDescription(sig.PublicKey), f.principal)
+}
+
+// krlMagic starts the binary key revocation lists of ssh-keygen -k.
+const krlMagic = "SSHKRL\n\x00"
+
+// checkRevocation returns an error if pub is listed in the revocation file, which
+// must hold public keys in authorized_keys format. Files that cannot be checked,
+// such as binary KRLs, are an error rather than treated as revoking nothing.
+func checkRevocation(path string, pub *ssh.PublicKey) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if bytes.HasPrefix(data, []byte(krlMagic)) {
+		return fmt.Errorf("%s: binary KRLs are not supported", path)
+	}
+	entries := 0
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		revoked, _, _, _, err := ssh.ParseAuthorizedKey(line)
+		if err != nil {
+			// Keys of other algorithms cannot revoke an ML-DSA key
+			if _, _, _, _, err := xssh.ParseAuthorizedKey(line); err != nil {
+				return fmt.Errorf("%s: line %d: not a public key", path, i+1)
+			}
+		} else if bytes.Equal(revoked.Marshal(), pub.Marshal()) {
+			return fmt.Errorf("%s is revoked", keyDescription(pub))
+		}
+		entries++
+	}
+	if entries == 0 {
+		return fmt.Errorf("%s: no revoked keys", path)
+	}
+	return nil
+}
+
+func findPrincipals(f *flags, stdout io.Writer) error {
+	t, err := verifyTime(f)
+	if err != nil {
+		return err
+	}
+	signers, err := readAllowedSigners(f)
+	if err != nil {
+		return err
+	}
+	sig, err := readSignature(f)
+	if err != nil {
+		return err
+	}
+	principals := sshsig.FindPrincipals(signers, sig.PublicKey, t)
+	if len(principals) == 0 {
+		return errors.New("no principal matched")
+	}
+	for _, p := range principals {
+		fmt.Fprintln(stdout, p)
+	}
+	return nil
+}
+
+func checkNoValidate(f *flags, stdin io.Reader, stdout io.Writer) error {
+	if f.namespace == "" {
+		return errors.New("check-novalidate: -n and -s are required")
+	}
+	if _, err := verifyTime(f); err != nil {
+		return err
+	}
+	sig, err := readSignature(f)
+	if err != nil {
+		return err
+	}
+	if err := sig.Verify(stdin, f.namespace); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Good %q signature with %s\n", f.namespace, keyDescription(sig.PublicKey))
+	return nil
+}
+
+func keygen(f *flags, stdout io.Writer) error {
+	keyType := f.keyType
+	if keyType == "" {
+		keyType = "ml-dsa-65"
+	}
+	s := mldsa.SchemeByName(keyType)
+	if s == nil {
+		return fmt.Errorf("unsupported key type %q: use ml-dsa-44, ml-dsa-65 or ml-dsa-87", keyType)
+	}
+	pub, priv, err := s.GenerateKey(nil)
+	if err != nil {
+		return err
+	}
+	defer priv.Destroy()
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		return err
+	}
+	block, err := ssh.MarshalPrivateKey(priv, f.comment)
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(f.keyFile, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if err := pem.Encode(out, block); err != nil {
+		out.Close() //nolint:errcheck
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	if err := os.WriteFile(f.keyFile+".pub", ssh.MarshalAuthorizedKey(sshPub, f.comment), 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Your identification has been saved in %s\n", f.keyFile)
+	fmt.Fprintf(stdout, "Your public key has been saved in %s.pub\n", f.keyFile)
+	fmt.Fprintf(stdout, "The key fingerprint is:\n%s %s\n", xssh.FingerprintSHA256(sshPub), f.comment)
+	return nil
+}
+
+func printPublicKey(f *flags, stdout io.Writer) error {
+	priv, err := readPrivateKey(f.keyFile)
+	if err != nil {
+		return err
+	}
+	defer priv.Destroy()
+	pub, err := ssh.NewPublicKey(priv.Public().(mldsa.PublicKey))
+	if err != nil {
+		return err
+	}
+	_, err = stdout.Write(ssh.MarshalAuthorizedKey(pub, f.comment))
+	return err
+}
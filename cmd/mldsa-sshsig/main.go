@@ -0,0 +1,182 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Command mldsa-sshsig signs and verifies files and git objects with ML-DSA SSH
+// keys, implementing the "ssh-keygen -Y" interface that git calls when
+// gpg.format is ssh:
+//
+//	git config gpg.format ssh
+//	git config gpg.ssh.program mldsa-sshsig
+//	git config gpg.ssh.allowedSignersFile ~/.config/git/allowed_signers
+//	git config user.signingKey ~/.ssh/id_mldsa65
+//
+// Usage:
+//
+//	mldsa-sshsig -Y sign -f key_file -n namespace [-O hashalg=sha512] [file ...]
+//	mldsa-sshsig -Y verify -f allowed_signers -I principal -n namespace -s signature_file [-r revocation_file] [-O verify-time=time]
+//	mldsa-sshsig -Y find-principals -f allowed_signers -s signature_file [-O verify-time=time]
+//	mldsa-sshsig -Y check-novalidate -n namespace -s signature_file
+//	mldsa-sshsig [-t ml-dsa-65] [-C comment] -f key_file
+//	mldsa-sshsig -y -f key_file
+//
+// sign writes the signature of each file to the file with the ".sig" suffix, or
+// signs standard input to standard output if no file is given. The key file is an
+// OpenSSH or PKCS #8 private key. If it is a public key whose name ends in ".pub",
+// the private key is read from the file without the suffix.
+//
+// verify, find-principals and check-novalidate read the signed data from standard
+// input. The revocation file lists revoked public keys in the authorized_keys
+// format; binary KRLs are not supported, and verification fails if the file has
+// lines that are not public keys, or no keys at all.
+//
+// Without -Y, mldsa-sshsig generates a private key in the OpenSSH format and
+// writes its public key to the file with the ".pub" suffix, and with -y it prints
+// the public key of a private key file.
+//
+// Errors, including invalid signatures, exit with status 255, like ssh-keygen.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+const usage = `usage: mldsa-sshsig -Y sign -f key_file -n namespace [-O hashalg=sha512] [file ...]
+       mldsa-sshsig -Y verify -f allowed_signers -I principal -n namespace -s signature_file
+                    [-r revocation_file] [-O verify-time=time]
+       mldsa-sshsig -Y find-principals -f allowed_signers -s signature_file [-O verify-time=time]
+       mldsa-sshsig -Y check-novalidate -n namespace -s signature_file
+       mldsa-sshsig [-t ml-dsa-65] [-C comment] -f key_file
+       mldsa-sshsig -y -f key_file
+`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, errUsage) {
+			fmt.Fprintln(os.Stderr, "mldsa-sshsig:", err)
+		}
+		os.Exit(255)
+	}
+}
+
+// errUsage is returned after the usage has been printed.
+var errUsage = errors.New("usage")
+
+// flags are the parsed command-line flags, in the style of ssh-keygen.
+type flags struct {
+	mode       string   // -Y
+	keyFile    string   // -f
+	namespace  string   // -n
+	sigFile    string   // -s
+	principal  string   // -I
+	revocation string   // -r
+	options    []string // -O
+	keyType    string   // -t
+	comment    string   // -C
+	printPub   bool     // -y
+	agent      bool     // -U
+	files      []string
+}
+
+// parseFlags parses args like getopt(3): flags with a value accept it in the same
+// argument ("-Overify-time=...") or in the next one, and flags end at "--" or at
+// the first argument that is not a flag.
+func parseFlags(args []string) (*flags, error) {
+	f := &flags{}
+	values := map[byte]*string{
+		'Y': &f.mode, 'f': &f.keyFile, 'n': &f.namespace, 's': &f.sigFile,
+		'I': &f.principal, 'r': &f.revocation, 't': &f.keyType, 'C': &f.comment,
+	}
+	for len(args) > 0 && len(args[0]) > 1 && args[0][0] == '-' {
+		arg := args[0]
+		args = args[1:]
+		if arg == "--" {
+			break
+		}
+		for i := 1; i < len(arg); i++ {
+			c := arg[i]
+			switch c {
+			case 'y':
+				f.printPub = true
+				continue
+			case 'U':
+				f.agent = true
+				continue
+			case 'q':
+				continue
+			}
+			value := arg[i+1:]
+			if value == "" {
+				if len(args) == 0 {
+					return nil, fmt.Errorf("option -%c requires an argument", c)
+				}
+				value, args = args[0], args[1:]
+			}
+			if c == 'O' {
+				f.options = append(f.options, value)
+			} else if p, ok := values[c]; ok {
+				*p = value
+			} else {
+				return nil, fmt.Errorf("unknown option -%c", c)
+			}
+			break
+		}
+	}
+	if len(args) > 0 {
+		f.files = args
+	}
+	return f, nil
+}
+
+// option returns the value of the -O option name, and an error for the options
+// that are not in allowed.
+func (f *flags) option(name string, allowed ...string) (string, error) {
+	var value string
+	for _, o := range f.options {
+		n, v, _ := strings.Cut(o, "=")
+		found := false
+		for _, a := range allowed {
+			found = found || n == a
+		}
+		if !found {
+			return "", fmt.Errorf("unsupported option %q", o)
+		}
+		if n == name {
+			value = v
+		}
+	}
+	return value, nil
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	f, err := parseFlags(args)
+	if err != nil {
+		fmt.Fprintf(stderr, "mldsa-sshsig: %v\n%s", err, usage)
+		return errUsage
+	}
+	if f.agent {
+		return errors.New("ssh-agent keys (-U) are not supported")
+	}
+	switch {
+	case f.mode == "sign":
+		return sign(f, stdin, stdout, stderr)
+	case f.mode == "verify":
+		return verify(f, stdin, stdout)
+	case f.mode == "find-principals":
+		return findPrincipals(f, stdout)
+	case f.mode == "check-novalidate":
+		return checkNoValidate(f, stdin, stdout)
+	case f.mode != "":
+		return fmt.Errorf("unsupported -Y mode %q", f.mode)
+	case f.printPub && f.keyFile != "":
+		return printPublicKey(f, stdout)
+	case f.keyFile != "" && len(f.files) == 0:
+		return keygen(f, stdout)
+	}
+	fmt.Fprint(stderr, usage)
+	return errUsage
+}
@@ -0,0 +1,207 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/ml-dsa/mldsa"
+	"github.com/trailofbits/ml-dsa/ssh"
+	xssh "golang.org/x/crypto/ssh"
+)
+
+// sshsigCmd runs the command with args, and returns its standard output.
+func sshsigCmd(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	err := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return stdout.String(), err
+}
+
+func TestParseFlags(t *testing.T) {
+	f, err := parseFlags([]string{"-Y", "verify", "-n", "git", "-f", "allowed", "-I", "a@example.com", "-s", "sig", "-Overify-time=20250101", "-r", "revoked"})
+	assert.NoError(t, err)
+	assert.Equal(t, &flags{
+		mode: "verify", namespace: "git", keyFile: "allowed", principal: "a@example.com",
+		sigFile: "sig", options: []string{"verify-time=20250101"}, revocation: "revoked",
+	}, f)
+
+	f, err = parseFlags([]string{"-Ysign", "-qUf", "key", "-n", "file", "--", "-msg"})
+	assert.NoError(t, err)
+	assert.Equal(t, &flags{mode: "sign", keyFile: "key", namespace: "file", agent: true, files: []string{"-msg"}}, f)
+
+	f, err = parseFlags([]string{"-y", "-f", "key", "-"})
+	assert.NoError(t, err)
+	assert.Equal(t, &flags{printPub: true, keyFile: "key", files: []string{"-"}}, f)
+
+	_, err = parseFlags([]string{"-Y"})
+	assert.Error(t, err)
+	_, err = parseFlags([]string{"-x"})
+	assert.Error(t, err)
+}
+
+func TestSignVerify(t *testing.T) {
+	dir := t.TempDir()
+	key := filepath.Join(dir, "id_mldsa")
+	msg := filepath.Join(dir, "msg")
+	allowed := filepath.Join(dir, "allowed_signers")
+	assert.NoError(t, os.WriteFile(msg, []byte("tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n"), 0o644))
+
+	out, err := sshsigCmd(t, "", "-t", "ml-dsa-44", "-C", "dev@example.com", "-f", key)
+	assert.NoError(t, err)
+	assert.Contains(t, out, "The key fingerprint is:\nSHA256:")
+	pubLine, err := os.ReadFile(key + ".pub")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(pubLine), "ssh-mldsa-44 "))
+	assert.True(t, strings.HasSuffix(string(pubLine), " dev@example.com\n"))
+	out, err = sshsigCmd(t, "", "-y", "-f", key)
+	assert.NoError(t, err)
+	assert.Equal(t, strings.TrimSuffix(string(pubLine), " dev@example.com\n")+"\n", out)
+	_, err = sshsigCmd(t, "", "-f", key)
+	assert.Error(t, err, "existing keys are not overwritten")
+
+	assert.NoError(t, os.WriteFile(allowed, []byte("dev@example.com namespaces=\"git\" "+string(pubLine)), 0o644))
+
+	// git signs a file with the public key as the key file, and reads the
+	// signature next to it
+	_, err = sshsigCmd(t, "", "-Y", "sign", "-n", "git", "-f", key+".pub", msg)
+	assert.NoError(t, err)
+	sig := msg + ".sig"
+	armored, err := os.ReadFile(sig)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(string(armored), "-----BEGIN SSH SIGNATURE-----\n"))
+	data, err := os.ReadFile(msg)
+	assert.NoError(t, err)
+
+	out, err = sshsigCmd(t, "", "-Y", "find-principals", "-f", allowed, "-s", sig)
+	assert.NoError(t, err)
+	assert.Equal(t, "dev@example.com\n", out)
+	out, err = sshsigCmd(t, string(data), "-Y", "verify", "-n", "git", "-f", allowed, "-I", "dev@example.com", "-s", sig)
+	assert.NoError(t, err)
+	assert.Regexp(t, `^Good "git" signature for dev@example.com with ML-DSA-44 key SHA256:[A-Za-z0-9+/]{43}\n$`, out)
+	out, err = sshsigCmd(t, string(data), "-Y", "check-novalidate", "-n", "git", "-s", sig)
+	assert.NoError(t, err)
+	assert.Regexp(t, `^Good "git" signature with ML-DSA-44 key SHA256:`, out)
+
+	_, err = sshsigCmd(t, "tampered", "-Y", "verify", "-n", "git", "-f", allowed, "-I", "dev@example.com", "-s", sig)
+	assert.Error(t, err)
+	_, err = sshsigCmd(t, string(data), "-Y", "verify", "-n", "file", "-f", allowed, "-I", "dev@example.com", "-s", sig)
+	assert.Error(t, err)
+	_, err = sshsigCmd(t, string(data), "-Y", "verify", "-n", "git", "-f", allowed, "-I", "eve@example.com", "-s", sig)
+	assert.Error(t, err)
+
+	// Signatures for another namespace verify, but the key is not allowed to
+	// sign for it
+	out, err = sshsigCmd(t, string(data), "-Y", "sign", "-n", "file", "-f", key, "-O", "hashalg=sha256")
+	assert.NoError(t, err)
+	fileSig := filepath.Join(dir, "file.sig")
+	assert.NoError(t, os.WriteFile(fileSig, []byte(out), 0o644))
+	_, err = sshsigCmd(t, string(data), "-Y", "check-novalidate", "-n", "file", "-s", fileSig)
+	assert.NoError(t, err)
+	_, err = sshsigCmd(t, string(data), "-Y", "verify", "-n", "file", "-f", allowed, "-I", "dev@example.com", "-s", fileSig)
+	assert.Error(t, err)
+
+	// Revoked keys and keys outside of their validity period are rejected
+	revoked := filepath.Join(dir, "revoked")
+	assert.NoError(t, os.WriteFile(revoked, pubLine, 0o644))
+	_, err = sshsigCmd(t, string(data), "-Y", "verify", "-n", "git", "-f", allowed, "-I", "dev@example.com", "-s", sig, "-r", revoked)
+	assert.ErrorContains(t, err, "revoked")
+	assert.NoError(t, os.WriteFile(allowed, []byte("dev@example.com valid-before=20250101 "+string(pubLine)), 0o644))
+	_, err = sshsigCmd(t, string(data), "-Y", "verify", "-n", "git", "-f", allowed, "-I", "dev@example.com", "-s", sig)
+	assert.Error(t, err)
+	_, err = sshsigCmd(t, "", "-Y", "find-principals", "-f", allowed, "-s", sig)
+	assert.Error(t, err)
+	_, err = sshsigCmd(t, string(data), "-Y", "verify", "-n", "git", "-f", allowed, "-I", "dev@example.com", "-s", sig, "-Overify-time=20241231")
+	assert.NoError(t, err)
+}
+
+func TestSignPKCS8(t *testing.T) {
+	dir := t.TempDir()
+	_, priv, err := mldsa.MLDSA87.GenerateKey(nil)
+	assert.NoError(t, err)
+	pemBytes, err := mldsa.MarshalPrivateKeyPEM(priv)
+	assert.NoError(t, err)
+	key := filepath.Join(dir, "key.pem")
+	assert.NoError(t, os.WriteFile(key, pemBytes, 0o600))
+
+	out, err := sshsigCmd(t, "msg", "-Y", "sign", "-n", "file", "-f", key)
+	assert.NoError(t, err)
+	sig := filepath.Join(dir, "msg.sig")
+	assert.NoError(t, os.WriteFile(sig, []byte(out), 0o644))
+	out, err = sshsigCmd(t, "msg", "-Y", "check-novalidate", "-n", "file", "-s", sig)
+	assert.NoError(t, err)
+	assert.Contains(t, out, "ML-DSA-87 key")
+}
+
+func TestErrors(t *testing.T) {
+	dir := t.TempDir()
+	key := filepath.Join(dir, "key")
+	_, err := sshsigCmd(t, "", "-f", key)
+	assert.NoError(t, err)
+
+	for name, args := range map[string][]string{
+		"no arguments":  {},
+		"unknown flag":  {"-x"},
+		"mode":          {"-Y", "revoke"},
+		"agent":         {"-Y", "sign", "-n", "git", "-f", key, "-U"},
+		"no namespace":  {"-Y", "sign", "-f", key},
+		"hash":          {"-Y", "sign", "-n", "git", "-f", key, "-O", "hashalg=sha1"},
+		"sign option":   {"-Y", "sign", "-n", "git", "-f", key, "-O", "no-touch-required"},
+		"not a key":     {"-Y", "sign", "-n", "git", "-f", key + ".pub.missing"},
+		"no principal":  {"-Y", "verify", "-n", "git", "-f", key, "-s", key},
+		"no signature":  {"-Y", "find-principals", "-f", key},
+		"verify time":   {"-Y", "find-principals", "-f", key, "-s", key, "-O", "verify-time=yesterday"},
+		"key type":      {"-t", "ed25519", "-f", filepath.Join(dir, "ed25519")},
+		"public key -y": {"-y", "-f", key + ".pub.missing"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := sshsigCmd(t, "", args...)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCheckRevocation(t *testing.T) {
+	_, priv, err := mldsa.MLDSA44.GenerateKey(nil)
+	assert.NoError(t, err)
+	pub, err := ssh.NewPublicKey(priv.Public().(mldsa.PublicKey))
+	assert.NoError(t, err)
+	_, priv, err = mldsa.MLDSA44.GenerateKey(nil)
+	assert.NoError(t, err)
+	other, err := ssh.NewPublicKey(priv.Public().(mldsa.PublicKey))
+	assert.NoError(t, err)
+	edPub, _, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+	edKey, err := xssh.NewPublicKey(edPub)
+	assert.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "revoked")
+	for name, tc := range map[string]struct {
+		file string
+		err  string
+	}{
+		"other keys": {"# revoked\n" + string(ssh.MarshalAuthorizedKey(other, "")) + string(xssh.MarshalAuthorizedKey(edKey)), ""},
+		"revoked":    {string(xssh.MarshalAuthorizedKey(edKey)) + string(ssh.MarshalAuthorizedKey(pub, "old")), "is revoked"},
+		"empty":      {"# nothing\n\n", "no revoked keys"},
+		"KRL":        {"SSHKRL\n\x00\x00\x00\x00\x01", "KRL"},
+		"garbage":    {string(ssh.MarshalAuthorizedKey(other, "")) + "not a key\n", "line 2"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			assert.NoError(t, os.WriteFile(path, []byte(tc.file), 0o644))
+			err := checkRevocation(path, pub)
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.err)
+			}
+		})
+	}
+}
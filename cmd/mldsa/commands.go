@@ -0,0 +1,378 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"crypto"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/trailofbits/ml-dsa/mldsa"
+	"github.com/trailofbits/ml-dsa/mldsa44"
+	"github.com/trailofbits/ml-dsa/mldsa65"
+	"github.com/trailofbits/ml-dsa/mldsa87"
+	options "github.com/trailofbits/ml-dsa/options"
+)
+
+// Pre-hash functions of HashML-DSA, by name.
+var hashes = map[string]options.Options{
+	"SHA-224":     {Hash: crypto.SHA224},
+	"SHA-256":     {Hash: crypto.SHA256},
+	"SHA-384":     {Hash: crypto.SHA384},
+	"SHA-512":     {Hash: crypto.SHA512},
+	"SHA-512/224": {Hash: crypto.SHA512_224},
+	"SHA-512/256": {Hash: crypto.SHA512_256},
+	"SHA3-224":    {Hash: crypto.SHA3_224},
+	"SHA3-256":    {Hash: crypto.SHA3_256},
+	"SHA3-384":    {Hash: crypto.SHA3_384},
+	"SHA3-512":    {Hash: crypto.SHA3_512},
+	"SHAKE-128":   {SHAKE: options.SHAKE128},
+	"SHAKE-256":   {SHAKE: options.SHAKE256},
+}
+
+func newFlagSet(name string, stderr io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet("mldsa "+name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	return fs
+}
+
+// parseFlags parses args, and returns the positional file argument, if any.
+func parseFlags(fs *flag.FlagSet, args []string) (string, error) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return "", errUsage
+		}
+		return "", err
+	}
+	switch fs.NArg() {
+	case 0:
+		return "", nil
+	case 1:
+		return fs.Arg(0), nil
+	}
+	return "", fmt.Errorf("%s: too many arguments", fs.Name())
+}
+
+// hashMessage returns the digest of msg with the pre-hash function named hash,
+// and the options to sign or verify it with HashML-DSA.
+func hashMessage(msg []byte, context, hash string) ([]byte, *options.Options, error) {
+	opts, ok := hashes[hash]
+	if !ok {
+		return nil, nil, fmt.Errorf("unsupported hash function %q", hash)
+	}
+	opts.Context = context
+	digest, err := opts.PreHash(msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return digest, &opts, nil
+}
+
+// streamSigner and streamVerifier are implemented by the StreamSigner and
+// StreamVerifier types of the mldsa44, mldsa65 and mldsa87 packages.
+type streamSigner interface {
+	io.Writer
+	Sign(rand io.Reader) ([]byte, error)
+}
+
+type streamVerifier interface {
+	io.Writer
+	Verify(sig []byte) bool
+}
+
+// signFile signs the file at path, or stdin, with pure ML-DSA, without reading
+// it into memory.
+func signFile(priv mldsa.PrivateKey, context, path string, stdin io.Reader) ([]byte, error) {
+	var s streamSigner
+	var err error
+	switch priv := priv.(type) {
+	case *mldsa44.PrivateKey:
+		s, err = priv.NewStreamSigner(context)
+	case *mldsa65.PrivateKey:
+		s, err = priv.NewStreamSigner(context)
+	case *mldsa87.PrivateKey:
+		s, err = priv.NewStreamSigner(context)
+	default:
+		return nil, errors.New("unsupported private key type")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := copyFile(s, path, stdin); err != nil {
+		return nil, err
+	}
+	return s.Sign(nil)
+}
+
+// verifyFile verifies a pure ML-DSA signature of the file at path, or stdin,
+// without reading it into memory.
+func verifyFile(pub mldsa.PublicKey, sig []byte, context, path string, stdin io.Reader) (bool, error) {
+	var v streamVerifier
+	var err error
+	switch pub := pub.(type) {
+	case *mldsa44.PublicKey:
+		v, err = pub.NewStreamVerifier(context)
+	case *mldsa65.PublicKey:
+		v, err = pub.NewStreamVerifier(context)
+	case *mldsa87.PublicKey:
+		v, err = pub.NewStreamVerifier(context)
+	default:
+		return false, errors.New("unsupported public key type")
+	}
+	if err != nil {
+		return false, err
+	}
+	if err := copyFile(v, path, stdin); err != nil {
+		return false, err
+	}
+	return v.Verify(sig), nil
+}
+
+func keygen(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("keygen", stderr)
+	alg := fs.String("alg", "ML-DSA-65", "parameter set: ML-DSA-44, ML-DSA-65 or ML-DSA-87")
+	out := fs.String("out", "", "private key output `file` (default stdout)")
+	outform := fs.String("outform", formPEM, "private key form: pem, raw, hex or base64")
+	pubout := fs.String("pubout", "", "also write the public key to `file`")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if fs.NArg() != 0 {
+		return errors.New("keygen: unexpected arguments")
+	}
+	if err := checkForm(*outform, false, true); err != nil {
+		return err
+	}
+	s, err := schemeByName(*alg)
+	if err != nil {
+		return err
+	}
+
+	pub, priv, err := s.GenerateKey(nil)
+	if err != nil {
+		return err
+	}
+	defer priv.Destroy()
+	privBytes, err := encodePrivateKey(priv, *outform)
+	if err != nil {
+		return err
+	}
+	if err := writeFile(*out, privBytes, 0o600, stdout); err != nil {
+		return err
+	}
+	if *pubout == "" {
+		return nil
+	}
+	pubBytes, err := encodePublicKey(pub, *outform)
+	if err != nil {
+		return err
+	}
+	return writeFile(*pubout, pubBytes, 0o644, stdout)
+}
+
+func pub(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("pub", stderr)
+	keyPath := fs.String("key", "", "private key `file` (default stdin)")
+	keyform := fs.String("keyform", formAuto, "private key form: auto, pem, raw, hex or base64")
+	alg := fs.String("alg", "", "parameter set of a raw seed")
+	out := fs.String("out", "", "public key output `file` (default stdout)")
+	outform := fs.String("outform", formPEM, "public key form: pem, raw, hex or base64")
+	if _, err := parseFlags(fs, args); err != nil {
+		return err
+	}
+	if err := checkForm(*outform, false, true); err != nil {
+		return err
+	}
+
+	priv, err := readPrivateKey(*keyPath, *keyform, *alg, stdin)
+	if err != nil {
+		return err
+	}
+	defer priv.Destroy()
+	pubBytes, err := encodePublicKey(priv.Public().(mldsa.PublicKey), *outform)
+	if err != nil {
+		return err
+	}
+	return writeFile(*out, pubBytes, 0o644, stdout)
+}
+
+func sign(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("sign", stderr)
+	keyPath := fs.String("key", "", "private key `file`")
+	keyform := fs.String("keyform", formAuto, "private key form: auto, pem, raw, hex or base64")
+	alg := fs.String("alg", "", "parameter set of a raw seed")
+	context := fs.String("context", "", "context string, at most 255 bytes")
+	hash := fs.String("hash", "", "sign the digest of the file with HashML-DSA, e.g. SHA-512")
+	out := fs.String("out", "", "signature output `file` (default stdout)")
+	sigform := fs.String("sigform", formRaw, "signature form: raw, hex or base64")
+	file, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if *keyPath == "" {
+		return errors.New("sign: -key is required")
+	}
+	if err := checkForm(*sigform, false, false); err != nil {
+		return err
+	}
+
+	priv, err := readPrivateKey(*keyPath, *keyform, *alg, stdin)
+	if err != nil {
+		return err
+	}
+	defer priv.Destroy()
+	var sig []byte
+	if *hash == "" {
+		sig, err = signFile(priv, *context, file, stdin)
+	} else {
+		var msg []byte
+		var opts *options.Options
+		if msg, err = readFile(file, stdin); err != nil {
+			return err
+		}
+		if msg, opts, err = hashMessage(msg, *context, *hash); err != nil {
+			return err
+		}
+		sig, err = priv.Sign(nil, msg, opts)
+	}
+	if err != nil {
+		return err
+	}
+	return writeFile(*out, encode(sig, *sigform), 0o644, stdout)
+}
+
+func verify(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("verify", stderr)
+	pubPath := fs.String("pub", "", "public key `file`")
+	pubform := fs.String("pubform", formAuto, "public key form: auto, pem, raw, hex or base64")
+	sigPath := fs.String("sig", "", "signature `file`")
+	sigform := fs.String("sigform", formAuto, "signature form: auto, raw, hex or base64")
+	context := fs.String("context", "", "context string, at most 255 bytes")
+	hash := fs.String("hash", "", "verify a HashML-DSA signature of the digest of the file, e.g. SHA-512")
+	quiet := fs.Bool("q", false, "do not print the result")
+	file, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if *pubPath == "" || *sigPath == "" {
+		return errors.New("verify: -pub and -sig are required")
+	}
+	if err := checkForm(*sigform, true, false); err != nil {
+		return err
+	}
+
+	pub, err := readPublicKey(*pubPath, *pubform, stdin)
+	if err != nil {
+		return err
+	}
+	sigData, err := readFile(*sigPath, stdin)
+	if err != nil {
+		return err
+	}
+	sig, _, err := decode(sigData, *sigform)
+	if err != nil {
+		return fmt.Errorf("signature: %w", err)
+	}
+	var ok bool
+	if *hash == "" {
+		ok, err = verifyFile(pub, sig, *context, file, stdin)
+	} else {
+		var msg []byte
+		var opts *options.Options
+		if msg, err = readFile(file, stdin); err != nil {
+			return err
+		}
+		if msg, opts, err = hashMessage(msg, *context, *hash); err != nil {
+			return err
+		}
+		ok = mldsa.SchemeOf(pub).Verify(pub, msg, sig, opts)
+	}
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errVerify
+	}
+	if !*quiet {
+		fmt.Fprintln(stdout, "Verified OK")
+	}
+	return nil
+}
+
+func inspect(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	fs := newFlagSet("inspect", stderr)
+	inform := fs.String("inform", formAuto, "input form: auto, pem, raw, hex or base64")
+	file, err := parseFlags(fs, args)
+	if err != nil {
+		return err
+	}
+	if err := checkForm(*inform, true, true); err != nil {
+		return err
+	}
+	data, err := readFile(file, stdin)
+	if err != nil {
+		return err
+	}
+	b, block, err := decode(data, *inform)
+	if err != nil {
+		return err
+	}
+
+	var kind string
+	var s mldsa.Scheme
+	var seed bool
+	switch {
+	case block != nil && block.Type == "PRIVATE KEY":
+		priv, err := mldsa.ParsePrivateKeyPEM(data)
+		if err != nil {
+			return err
+		}
+		defer priv.Destroy()
+		_, err = priv.Seed()
+		kind, s, seed = "private key", mldsa.SchemeOf(priv), err == nil
+	case block != nil && block.Type == "PUBLIC KEY":
+		pub, err := mldsa.ParsePublicKeyPEM(data)
+		if err != nil {
+			return err
+		}
+		kind, s = "public key", mldsa.SchemeOf(pub)
+	case block != nil:
+		return fmt.Errorf("unsupported PEM block type %q", block.Type)
+	case len(b) == mldsa.MLDSA44.SeedSize():
+		fmt.Fprintln(stdout, "type:             private key seed")
+		fmt.Fprintln(stdout, "parameter set:    unknown (a seed does not identify its parameter set)")
+		return nil
+	default:
+		for _, scheme := range mldsa.Schemes() {
+			switch len(b) {
+			case scheme.PublicKeySize():
+				kind, s = "public key", scheme
+			case scheme.PrivateKeySize():
+				kind, s = "expanded private key", scheme
+			case scheme.SignatureSize():
+				kind, s = "signature", scheme
+			}
+		}
+		if s == nil {
+			return fmt.Errorf("unrecognized input of %d bytes", len(b))
+		}
+	}
+
+	var w strings.Builder
+	fmt.Fprintf(&w, "type:             %s\n", kind)
+	fmt.Fprintf(&w, "parameter set:    %s\n", s.Name())
+	fmt.Fprintf(&w, "OID:              %s\n", s.OID())
+	if kind == "private key" {
+		fmt.Fprintf(&w, "seed:             %t\n", seed)
+	}
+	fmt.Fprintf(&w, "public key size:  %d bytes\n", s.PublicKeySize())
+	fmt.Fprintf(&w, "private key size: %d bytes (seed: %d bytes)\n", s.PrivateKeySize(), s.SeedSize())
+	fmt.Fprintf(&w, "signature size:   %d bytes\n", s.SignatureSize())
+	_, err = io.WriteString(stdout, w.String())
+	return err
+}
@@ -0,0 +1,217 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/trailofbits/ml-dsa/mldsa"
+)
+
+// Encodings of keys and signatures.
+const (
+	formAuto   = "auto" // input only
+	formPEM    = "pem"
+	formRaw    = "raw"
+	formHex    = "hex"
+	formBase64 = "base64"
+)
+
+func checkForm(form string, input, allowPEM bool) error {
+	switch form {
+	case formRaw, formHex, formBase64:
+		return nil
+	case formAuto:
+		if input {
+			return nil
+		}
+	case formPEM:
+		if allowPEM {
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported form %q", form)
+}
+
+// readFile reads the file at path, or stdin if path is empty or "-".
+func readFile(path string, stdin io.Reader) ([]byte, error) {
+	if path == "" || path == "-" {
+		return io.ReadAll(stdin)
+	}
+	return os.ReadFile(path)
+}
+
+// copyFile copies the file at path, or stdin if path is empty or "-", to w.
+func copyFile(w io.Writer, path string, stdin io.Reader) error {
+	if path == "" || path == "-" {
+		_, err := io.Copy(w, stdin)
+		return err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close() //nolint:errcheck
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// writeFile writes data to the file at path, or stdout if path is empty or "-".
+// Files are created with mode perm.
+func writeFile(path string, data []byte, perm os.FileMode, stdout io.Writer) error {
+	if path == "" || path == "-" {
+		_, err := stdout.Write(data)
+		return err
+	}
+	return os.WriteFile(path, data, perm)
+}
+
+// encode encodes raw bytes in form, which must not be PEM.
+// Text forms end with a newline.
+func encode(b []byte, form string) []byte {
+	switch form {
+	case formHex:
+		return []byte(hex.EncodeToString(b) + "\n")
+	case formBase64:
+		return []byte(base64.StdEncoding.EncodeToString(b) + "\n")
+	}
+	return b
+}
+
+// decode returns the bytes encoded in data. With form "auto", PEM blocks are
+// returned undecoded as pem, and text that is valid hex or base64 is decoded
+// as such. Any other data is raw.
+func decode(data []byte, form string) (b []byte, block *pem.Block, err error) {
+	text := strings.TrimSpace(string(data))
+	switch form {
+	case formPEM:
+		block, _ = pem.Decode(data)
+		if block == nil {
+			return nil, nil, errors.New("no PEM block found")
+		}
+		return nil, block, nil
+	case formHex:
+		b, err = hex.DecodeString(text)
+		return b, nil, err
+	case formBase64:
+		b, err = base64.StdEncoding.DecodeString(text)
+		return b, nil, err
+	case formRaw:
+		return data, nil, nil
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("-----BEGIN ")) {
+		return decode(data, formPEM)
+	}
+	if b, err := hex.DecodeString(text); err == nil && len(text) > 0 {
+		return b, nil, nil
+	}
+	if b, err := base64.StdEncoding.DecodeString(text); err == nil && len(text) > 0 {
+		return b, nil, nil
+	}
+	return data, nil, nil
+}
+
+// schemeByName is mldsa.SchemeByName, returning an error for unknown names.
+func schemeByName(name string) (mldsa.Scheme, error) {
+	s := mldsa.SchemeByName(name)
+	if s == nil {
+		return nil, fmt.Errorf("unknown parameter set %q, expected ML-DSA-44, ML-DSA-65 or ML-DSA-87", name)
+	}
+	return s, nil
+}
+
+// readPrivateKey reads a PKCS #8 PEM private key, or an encoded seed or expanded
+// private key. A seed requires the parameter set alg.
+func readPrivateKey(path, form, alg string, stdin io.Reader) (mldsa.PrivateKey, error) {
+	if err := checkForm(form, true, true); err != nil {
+		return nil, err
+	}
+	data, err := readFile(path, stdin)
+	if err != nil {
+		return nil, err
+	}
+	b, block, err := decode(data, form)
+	if err != nil {
+		return nil, fmt.Errorf("private key: %w", err)
+	}
+	if block != nil {
+		return mldsa.ParsePrivateKeyPEM(data)
+	}
+
+	if alg != "" {
+		s, err := schemeByName(alg)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) == s.SeedSize() {
+			return s.PrivateKeyFromSeed(b)
+		}
+		return s.PrivateKeyFromExpanded(b)
+	}
+	for _, s := range mldsa.Schemes() {
+		if len(b) == s.PrivateKeySize() {
+			return s.PrivateKeyFromExpanded(b)
+		}
+	}
+	if len(b) == mldsa.MLDSA44.SeedSize() {
+		return nil, errors.New("the private key is a seed: use -alg to select its parameter set")
+	}
+	return nil, fmt.Errorf("private key: unexpected size %d", len(b))
+}
+
+// readPublicKey reads a SubjectPublicKeyInfo PEM public key, or an encoded public key.
+func readPublicKey(path, form string, stdin io.Reader) (mldsa.PublicKey, error) {
+	if err := checkForm(form, true, true); err != nil {
+		return nil, err
+	}
+	data, err := readFile(path, stdin)
+	if err != nil {
+		return nil, err
+	}
+	b, block, err := decode(data, form)
+	if err != nil {
+		return nil, fmt.Errorf("public key: %w", err)
+	}
+	if block != nil {
+		return mldsa.ParsePublicKeyPEM(data)
+	}
+	for _, s := range mldsa.Schemes() {
+		if len(b) == s.PublicKeySize() {
+			return s.PublicKeyFromBytes(b)
+		}
+	}
+	return nil, fmt.Errorf("public key: unexpected size %d", len(b))
+}
+
+// encodePrivateKey encodes priv as PKCS #8 PEM, or its seed in another form.
+// Keys without a known seed are encoded in expanded form.
+func encodePrivateKey(priv mldsa.PrivateKey, form string) ([]byte, error) {
+	if form == formPEM {
+		return mldsa.MarshalPrivateKeyPEM(priv)
+	}
+	b, err := priv.Seed()
+	if err != nil {
+		b = priv.EncodeExpanded()
+	}
+	return encode(b, form), nil
+}
+
+// encodePublicKey encodes pub as SubjectPublicKeyInfo PEM, or as the FIPS 204
+// public key in another form.
+func encodePublicKey(pub mldsa.PublicKey, form string) ([]byte, error) {
+	if form == formPEM {
+		return mldsa.MarshalPublicKeyPEM(pub)
+	}
+	return encode(pub.Bytes(), form), nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Command mldsa generates ML-DSA keys, and signs and verifies files with them.
+//
+// Usage:
+//
+//	mldsa keygen [-alg ML-DSA-65] [-out key.pem] [-pubout pub.pem] [-outform pem]
+//	mldsa pub -key key.pem [-out pub.pem] [-outform pem]
+//	mldsa sign -key key.pem [-context ctx] [-hash SHA-512] [-out file.sig] [file]
+//	mldsa verify -pub pub.pem -sig file.sig [-context ctx] [-hash SHA-512] [file]
+//	mldsa inspect [file]
+//
+// Keys are read and written as PEM (PKCS #8 and SubjectPublicKeyInfo, as defined in
+// RFC 9881), or as the raw, hex or base64 encodings of the seed, expanded private key
+// or public key defined in FIPS 204. Signatures are raw, hex or base64 encoded.
+// On input, the "auto" form detects the encoding. A raw seed does not identify its
+// parameter set, which must then be given with -alg.
+//
+// Files default to standard input and output when omitted or given as "-".
+// verify exits with status 1 if the signature is invalid.
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+const usage = `usage: mldsa <command> [flags]
+
+Commands:
+  keygen   generate a private key
+  pub      derive the public key of a private key
+  sign     sign a file
+  verify   verify the signature of a file
+  inspect  print the parameter set and sizes of a key or signature
+
+Run "mldsa <command> -h" for the flags of a command.
+`
+
+// errVerify is returned by verify for an invalid signature.
+var errVerify = errors.New("signature verification failed")
+
+// command is a subcommand, which reads its input from stdin and writes its output
+// to stdout, unless its flags name files.
+type command func(args []string, stdin io.Reader, stdout, stderr io.Writer) error
+
+var commands = map[string]command{
+	"keygen":  keygen,
+	"pub":     pub,
+	"sign":    sign,
+	"verify":  verify,
+	"inspect": inspect,
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr); err != nil {
+		if !errors.Is(err, errUsage) {
+			fmt.Fprintln(os.Stderr, "mldsa:", err)
+		}
+		os.Exit(1)
+	}
+}
+
+// errUsage is returned after the usage has been printed.
+var errUsage = errors.New("usage")
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) error {
+	if len(args) == 0 {
+		fmt.Fprint(stderr, usage)
+		return errUsage
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		if args[0] == "help" || args[0] == "-h" || args[0] == "-help" {
+			fmt.Fprint(stdout, usage)
+			return nil
+		}
+		fmt.Fprintf(stderr, "mldsa: unknown command %q\n\n%s", args[0], usage)
+		return errUsage
+	}
+	return cmd(args[1:], stdin, stdout, stderr)
+}
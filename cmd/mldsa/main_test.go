@@ -0,0 +1,150 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/ml-dsa/mldsa"
+	options "github.com/trailofbits/ml-dsa/options"
+)
+
+// mldsaCmd runs the command with args, and returns its standard output.
+func mldsaCmd(t *testing.T, stdin string, args ...string) (string, error) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	err := run(args, strings.NewReader(stdin), &stdout, &stderr)
+	return stdout.String(), err
+}
+
+func TestSignVerify(t *testing.T) {
+	dir := t.TempDir()
+	msg := filepath.Join(dir, "msg")
+	assert.NoError(t, os.WriteFile(msg, []byte("Hello, world!"), 0o644))
+
+	for _, s := range mldsa.Schemes() {
+		for _, form := range []string{formPEM, formRaw, formHex, formBase64} {
+			t.Run(s.Name()+"/"+form, func(t *testing.T) {
+				key := filepath.Join(dir, "key")
+				pub := filepath.Join(dir, "pub")
+				sig := filepath.Join(dir, "sig")
+				_, err := mldsaCmd(t, "", "keygen", "-alg", s.Name(), "-outform", form, "-out", key, "-pubout", pub)
+				assert.NoError(t, err)
+
+				// Raw seeds do not identify the parameter set, and signatures have no PEM form
+				alg, sigform := []string{"-alg", s.Name()}, form
+				if form == formPEM {
+					alg, sigform = nil, formRaw
+				}
+				_, err = mldsaCmd(t, "", append(append([]string{"sign", "-key", key, "-context", "ctx", "-sigform", sigform, "-out", sig}, alg...), msg)...)
+				assert.NoError(t, err)
+				out, err := mldsaCmd(t, "", "verify", "-pub", pub, "-sig", sig, "-context", "ctx", msg)
+				assert.NoError(t, err)
+				assert.Equal(t, "Verified OK\n", out)
+
+				// Streamed signatures verify with the one-shot API
+				if form == formRaw {
+					pubBytes, err := os.ReadFile(pub)
+					assert.NoError(t, err)
+					pk, err := s.PublicKeyFromBytes(pubBytes)
+					assert.NoError(t, err)
+					sigBytes, err := os.ReadFile(sig)
+					assert.NoError(t, err)
+					assert.True(t, s.Verify(pk, []byte("Hello, world!"), sigBytes, &options.Options{Context: "ctx"}))
+				}
+
+				// The public key derived from the private key is the same
+				out, err = mldsaCmd(t, "", append([]string{"pub", "-key", key, "-outform", form}, alg...)...)
+				assert.NoError(t, err)
+				pubData, err := os.ReadFile(pub)
+				assert.NoError(t, err)
+				assert.Equal(t, string(pubData), out)
+
+				_, err = mldsaCmd(t, "", "verify", "-pub", pub, "-sig", sig, msg)
+				assert.ErrorIs(t, err, errVerify)
+				_, err = mldsaCmd(t, "tampered", "verify", "-pub", pub, "-sig", sig, "-context", "ctx")
+				assert.ErrorIs(t, err, errVerify)
+			})
+		}
+	}
+}
+
+func TestSignVerifyStdin(t *testing.T) {
+	dir := t.TempDir()
+	key := filepath.Join(dir, "key.pem")
+	pub := filepath.Join(dir, "pub.pem")
+	sig := filepath.Join(dir, "sig")
+	_, err := mldsaCmd(t, "", "keygen", "-out", key, "-pubout", pub)
+	assert.NoError(t, err)
+
+	for _, hash := range []string{"", "SHA-256", "SHA3-512", "SHAKE-128", "SHAKE-256"} {
+		out, err := mldsaCmd(t, "Hello, world!", "sign", "-key", key, "-hash", hash, "-sigform", "hex")
+		assert.NoError(t, err)
+		assert.NoError(t, os.WriteFile(sig, []byte(out), 0o644))
+		_, err = mldsaCmd(t, "Hello, world!", "verify", "-q", "-pub", pub, "-sig", sig, "-hash", hash, "-")
+		assert.NoError(t, err, hash)
+		if hash != "" {
+			// A HashML-DSA signature is not a pure ML-DSA signature
+			_, err = mldsaCmd(t, "Hello, world!", "verify", "-pub", pub, "-sig", sig)
+			assert.ErrorIs(t, err, errVerify)
+		}
+	}
+
+	_, err = mldsaCmd(t, "", "sign", "-key", key, "-hash", "MD5")
+	assert.Error(t, err)
+}
+
+func TestInspect(t *testing.T) {
+	dir := t.TempDir()
+	key := filepath.Join(dir, "key.pem")
+	_, err := mldsaCmd(t, "", "keygen", "-alg", "ML-DSA-87", "-out", key)
+	assert.NoError(t, err)
+
+	out, err := mldsaCmd(t, "", "inspect", key)
+	assert.NoError(t, err)
+	assert.Equal(t, `type:             private key
+parameter set:    ML-DSA-87
+OID:              2.16.840.1.101.3.4.3.19
+seed:             true
+public key size:  2592 bytes
+private key size: 4896 bytes (seed: 32 bytes)
+signature size:   4627 bytes
+`, out)
+
+	pubPEM, err := mldsaCmd(t, "", "pub", "-key", key)
+	assert.NoError(t, err)
+	out, err = mldsaCmd(t, pubPEM, "inspect")
+	assert.NoError(t, err)
+	assert.Contains(t, out, "type:             public key\nparameter set:    ML-DSA-87\n")
+
+	sig, err := mldsaCmd(t, "msg", "sign", "-key", key, "-sigform", "base64")
+	assert.NoError(t, err)
+	out, err = mldsaCmd(t, sig, "inspect")
+	assert.NoError(t, err)
+	assert.Contains(t, out, "type:             signature\nparameter set:    ML-DSA-87\n")
+
+	_, err = mldsaCmd(t, "not a key", "inspect")
+	assert.Error(t, err)
+}
+
+func TestUsage(t *testing.T) {
+	_, err := mldsaCmd(t, "")
+	assert.ErrorIs(t, err, errUsage)
+	_, err = mldsaCmd(t, "", "frobnicate")
+	assert.ErrorIs(t, err, errUsage)
+	_, err = mldsaCmd(t, "", "keygen", "-alg", "ML-DSA-1")
+	assert.Error(t, err)
+	_, err = mldsaCmd(t, "", "keygen", "-outform", "auto")
+	assert.Error(t, err)
+	_, err = mldsaCmd(t, "", "sign", "-key", "k", "-sigform", "pem")
+	assert.Error(t, err)
+	_, err = mldsaCmd(t, "", "verify", "-pub", "p")
+	assert.Error(t, err)
+}
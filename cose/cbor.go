@@ -0,0 +1,265 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cose
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// This file implements the subset of CBOR (RFC 8949) used by COSE structures.
+//
+// Values are represented by the Go types:
+//
+//	int64          unsigned and negative integers that fit in an int64
+//	[]byte         byte strings
+//	string         text strings
+//	[]any          arrays
+//	map[any]any    maps, with int64 or string keys
+//	tag            tagged values
+//	bool, nil      the simple values true, false and null
+//
+// Encoding is deterministic (RFC 8949, Section 4.2.1): integers and lengths use
+// their shortest form, and map keys are sorted by their encoding. Decoding rejects
+// indefinite lengths, floating-point numbers, duplicate map keys and trailing data.
+
+// CBOR major types.
+const (
+	majorUnsigned = 0
+	majorNegative = 1
+	majorBytes    = 2
+	majorText     = 3
+	majorArray    = 4
+	majorMap      = 5
+	majorTag      = 6
+)
+
+// Simple values, with their initial bytes.
+const (
+	simpleFalse = 0xf4
+	simpleTrue  = 0xf5
+	simpleNull  = 0xf6
+)
+
+// maxDepth bounds the nesting of decoded arrays, maps and tags.
+const maxDepth = 16
+
+// tag is a CBOR tagged value.
+type tag struct {
+	Number  uint64
+	Content any
+}
+
+// appendHead appends the initial byte and argument of a data item.
+func appendHead(b []byte, major byte, arg uint64) []byte {
+	m := major << 5
+	switch {
+	case arg < 24:
+		return append(b, m|byte(arg))
+	case arg <= math.MaxUint8:
+		return append(b, m|24, byte(arg))
+	case arg <= math.MaxUint16:
+		return append(b, m|25, byte(arg>>8), byte(arg))
+	case arg <= math.MaxUint32:
+		return append(b, m|26, byte(arg>>24), byte(arg>>16), byte(arg>>8), byte(arg))
+	}
+	return append(b, m|27, byte(arg>>56), byte(arg>>48), byte(arg>>40), byte(arg>>32),
+		byte(arg>>24), byte(arg>>16), byte(arg>>8), byte(arg))
+}
+
+// marshalCBOR returns the deterministic encoding of v.
+func marshalCBOR(v any) ([]byte, error) {
+	return appendCBOR(nil, v)
+}
+
+func appendCBOR(b []byte, v any) ([]byte, error) {
+	var err error
+	switch v := v.(type) {
+	case int:
+		return appendCBOR(b, int64(v))
+	case int64:
+		if v >= 0 {
+			return appendHead(b, majorUnsigned, uint64(v)), nil
+		}
+		return appendHead(b, majorNegative, uint64(-(v + 1))), nil
+	case []byte:
+		return append(appendHead(b, majorBytes, uint64(len(v))), v...), nil
+	case string:
+		return append(appendHead(b, majorText, uint64(len(v))), v...), nil
+	case []any:
+		b = appendHead(b, majorArray, uint64(len(v)))
+		for _, e := range v {
+			if b, err = appendCBOR(b, e); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	case map[any]any:
+		type entry struct{ key, value []byte }
+		entries := make([]entry, 0, len(v))
+		for key, value := range v {
+			var e entry
+			if e.key, err = marshalCBOR(key); err != nil {
+				return nil, err
+			}
+			if e.value, err = marshalCBOR(value); err != nil {
+				return nil, err
+			}
+			entries = append(entries, e)
+		}
+		slices.SortFunc(entries, func(x, y entry) int { return bytes.Compare(x.key, y.key) })
+		b = appendHead(b, majorMap, uint64(len(v)))
+		for _, e := range entries {
+			b = append(append(b, e.key...), e.value...)
+		}
+		return b, nil
+	case tag:
+		return appendCBOR(appendHead(b, majorTag, v.Number), v.Content)
+	case bool:
+		if v {
+			return append(b, simpleTrue), nil
+		}
+		return append(b, simpleFalse), nil
+	case nil:
+		return append(b, simpleNull), nil
+	}
+	return nil, fmt.Errorf("cbor: unsupported type %T", v)
+}
+
+var errMalformedCBOR = errors.New("cbor: malformed data")
+
+// unmarshalCBOR decodes a single data item, which must span all of data.
+func unmarshalCBOR(data []byte) (any, error) {
+	d := decoder{data: data}
+	v, err := d.value(0)
+	if err != nil {
+		return nil, err
+	}
+	if len(d.data) != 0 {
+		return nil, errors.New("cbor: trailing data")
+	}
+	return v, nil
+}
+
+type decoder struct {
+	data []byte
+}
+
+// head reads the initial byte and argument of a data item. Indefinite lengths and
+// reserved values are rejected.
+func (d *decoder) head() (major byte, arg uint64, err error) {
+	if len(d.data) == 0 {
+		return 0, 0, errMalformedCBOR
+	}
+	major, info := d.data[0]>>5, d.data[0]&0x1f
+	d.data = d.data[1:]
+	if info < 24 {
+		return major, uint64(info), nil
+	}
+	if info > 27 {
+		return 0, 0, errMalformedCBOR
+	}
+	n := 1 << (info - 24)
+	if len(d.data) < n {
+		return 0, 0, errMalformedCBOR
+	}
+	for _, c := range d.data[:n] {
+		arg = arg<<8 | uint64(c)
+	}
+	d.data = d.data[n:]
+	return major, arg, nil
+}
+
+func (d *decoder) value(depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, errors.New("cbor: nesting too deep")
+	}
+	initial := byte(0)
+	if len(d.data) > 0 {
+		initial = d.data[0]
+	}
+	major, arg, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case majorUnsigned:
+		if arg > math.MaxInt64 {
+			return nil, errors.New("cbor: integer overflow")
+		}
+		return int64(arg), nil
+	case majorNegative:
+		if arg > math.MaxInt64 {
+			return nil, errors.New("cbor: integer overflow")
+		}
+		return -1 - int64(arg), nil
+	case majorBytes, majorText:
+		if arg > uint64(len(d.data)) {
+			return nil, errMalformedCBOR
+		}
+		s := d.data[:arg:arg]
+		d.data = d.data[arg:]
+		if major == majorText {
+			return string(s), nil
+		}
+		return s, nil
+	case majorArray:
+		// Each element takes at least one byte
+		if arg > uint64(len(d.data)) {
+			return nil, errMalformedCBOR
+		}
+		a := make([]any, arg)
+		for i := range a {
+			if a[i], err = d.value(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return a, nil
+	case majorMap:
+		if arg > uint64(len(d.data))/2 {
+			return nil, errMalformedCBOR
+		}
+		m := make(map[any]any, arg)
+		for range arg {
+			key, err := d.value(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, errors.New("cbor: unsupported map key type")
+			}
+			if _, ok := m[key]; ok {
+				return nil, errors.New("cbor: duplicate map key")
+			}
+			if m[key], err = d.value(depth + 1); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+	case majorTag:
+		content, err := d.value(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		return tag{Number: arg, Content: content}, nil
+	}
+
+	// Major type 7
+	switch initial {
+	case simpleFalse:
+		return false, nil
+	case simpleTrue:
+		return true, nil
+	case simpleNull:
+		return nil, nil
+	}
+	return nil, errors.New("cbor: unsupported simple value or float")
+}
@@ -0,0 +1,107 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cose
+
+import (
+	"encoding/hex"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Examples from RFC 8949, Appendix A, in deterministic encoding.
+var cborExamples = []struct {
+	value   any
+	encoded string
+}{
+	{int64(0), "00"},
+	{int64(23), "17"},
+	{int64(24), "1818"},
+	{int64(100), "1864"},
+	{int64(1000), "1903e8"},
+	{int64(1000000), "1a000f4240"},
+	{int64(1000000000000), "1b000000e8d4a51000"},
+	{int64(math.MaxInt64), "1b7fffffffffffffff"},
+	{int64(-1), "20"},
+	{int64(-10), "29"},
+	{int64(-100), "3863"},
+	{int64(-1000), "3903e7"},
+	{int64(math.MinInt64), "3b7fffffffffffffff"},
+	{false, "f4"},
+	{true, "f5"},
+	{nil, "f6"},
+	{[]byte{}, "40"},
+	{[]byte{1, 2, 3, 4}, "4401020304"},
+	{"", "60"},
+	{"IETF", "6449455446"},
+	{"ü", "62c3bc"},
+	{[]any{}, "80"},
+	{[]any{int64(1), int64(2), int64(3)}, "83010203"},
+	{[]any{int64(1), []any{int64(2), int64(3)}, []any{int64(4), int64(5)}}, "8301820203820405"},
+	{map[any]any{}, "a0"},
+	{map[any]any{int64(1): int64(2), int64(3): int64(4)}, "a201020304"},
+	{map[any]any{"a": int64(1), "b": []any{int64(2), int64(3)}}, "a26161016162820203"},
+	{tag{Number: 1, Content: int64(1363896240)}, "c11a514b67b0"},
+	{tag{Number: 32, Content: "http://www.example.com"}, "d82076687474703a2f2f7777772e6578616d706c652e636f6d"},
+}
+
+func TestCBORExamples(t *testing.T) {
+	for _, tc := range cborExamples {
+		encoded, err := marshalCBOR(tc.value)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.encoded, hex.EncodeToString(encoded))
+
+		decoded, err := unmarshalCBOR(encoded)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.value, decoded, tc.encoded)
+	}
+}
+
+func TestCBORMapKeyOrder(t *testing.T) {
+	// Keys are sorted by their encoding: positive integers, then negative
+	// integers, then text strings
+	encoded, err := marshalCBOR(map[any]any{"a": int64(0), int64(-1): int64(0), int64(10): int64(0), int64(1): int64(0)})
+	assert.NoError(t, err)
+	assert.Equal(t, "a4"+"0100"+"0a00"+"2000"+"616100", hex.EncodeToString(encoded))
+}
+
+func TestCBORMalformed(t *testing.T) {
+	for name, encoded := range map[string]string{
+		"empty":              "",
+		"truncated argument": "19",
+		"truncated bytes":    "4401",
+		"truncated array":    "8301",
+		"truncated map":      "a101",
+		"indefinite bytes":   "5f4101ff",
+		"indefinite array":   "9f01ff",
+		"reserved":           "1c",
+		"float":              "f93c00",
+		"undefined":          "f7",
+		"overflow":           "1b8000000000000000",
+		"negative overflow":  "3b8000000000000000",
+		"duplicate key":      "a201020103",
+		"byte string key":    "a1400102",
+		"trailing data":      "0000",
+		"huge array":         "9bffffffffffffffff",
+		"huge map":           "bb7fffffffffffffff",
+		"deep nesting":       strings.Repeat("81", maxDepth+1) + "00",
+	} {
+		t.Run(name, func(t *testing.T) {
+			b, err := hex.DecodeString(encoded)
+			assert.NoError(t, err)
+			_, err = unmarshalCBOR(b)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestCBORUnsupportedType(t *testing.T) {
+	_, err := marshalCBOR(1.5)
+	assert.Error(t, err)
+	_, err = marshalCBOR([]any{uint8(1)})
+	assert.Error(t, err)
+}
@@ -0,0 +1,221 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cose
+
+import (
+	"crypto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trailofbits/ml-dsa/mldsa"
+)
+
+func TestAlgorithm(t *testing.T) {
+	assert.Equal(t, int64(-48), Algorithm(mldsa.MLDSA44))
+	assert.Equal(t, int64(-49), Algorithm(mldsa.MLDSA65))
+	assert.Equal(t, int64(-50), Algorithm(mldsa.MLDSA87))
+	for _, s := range mldsa.Schemes() {
+		assert.Equal(t, s, SchemeByAlgorithm(Algorithm(s)))
+	}
+	assert.Nil(t, SchemeByAlgorithm(-8))
+}
+
+func TestKeyRoundTrip(t *testing.T) {
+	for _, s := range mldsa.Schemes() {
+		t.Run(s.Name(), func(t *testing.T) {
+			pub, priv, err := s.GenerateKey(nil)
+			require.NoError(t, err)
+
+			data, err := MarshalPublicKey(pub, []byte("key-1"))
+			assert.NoError(t, err)
+			decoded, err := unmarshalCBOR(data)
+			assert.NoError(t, err)
+			assert.Equal(t, map[any]any{
+				int64(1):  int64(7),
+				int64(2):  []byte("key-1"),
+				int64(3):  Algorithm(s),
+				int64(-1): pub.Bytes(),
+			}, decoded)
+			parsedPub, kid, err := ParsePublicKey(data)
+			assert.NoError(t, err)
+			assert.Equal(t, pub, parsedPub)
+			assert.Equal(t, []byte("key-1"), kid)
+			_, _, err = ParsePrivateKey(data)
+			assert.Error(t, err)
+
+			data, err = MarshalPrivateKey(priv, nil)
+			assert.NoError(t, err)
+			decoded, err = unmarshalCBOR(data)
+			assert.NoError(t, err)
+			seed, err := priv.Seed()
+			assert.NoError(t, err)
+			assert.Equal(t, seed, decoded.(map[any]any)[int64(-2)])
+			assert.NotContains(t, decoded, int64(2))
+			parsedPriv, kid, err := ParsePrivateKey(data)
+			assert.NoError(t, err)
+			assert.Equal(t, priv.EncodeExpanded(), parsedPriv.EncodeExpanded())
+			assert.Nil(t, kid)
+			parsedPub, _, err = ParsePublicKey(data)
+			assert.NoError(t, err)
+			assert.Equal(t, pub, parsedPub)
+		})
+	}
+}
+
+func TestMarshalPrivateKeyWithoutSeed(t *testing.T) {
+	_, priv, err := mldsa.MLDSA44.GenerateKey(nil)
+	require.NoError(t, err)
+	expanded, err := mldsa.MLDSA44.PrivateKeyFromExpanded(priv.EncodeExpanded())
+	assert.NoError(t, err)
+	_, err = MarshalPrivateKey(expanded, nil)
+	assert.Error(t, err)
+}
+
+func TestParseKeyMalformed(t *testing.T) {
+	pub, _, err := mldsa.MLDSA44.GenerateKey(nil)
+	require.NoError(t, err)
+	_, other, err := mldsa.MLDSA44.GenerateKey(nil)
+	require.NoError(t, err)
+	otherSeed, err := other.Seed()
+	assert.NoError(t, err)
+	valid := func() map[any]any {
+		return map[any]any{int64(1): int64(7), int64(3): int64(-48), int64(-1): pub.Bytes()}
+	}
+
+	for name, modify := range map[string]func(map[any]any){
+		"key type":     func(k map[any]any) { k[int64(1)] = int64(1) },
+		"algorithm":    func(k map[any]any) { k[int64(3)] = int64(-8) },
+		"text alg":     func(k map[any]any) { k[int64(3)] = "ML-DSA-44" },
+		"wrong size":   func(k map[any]any) { k[int64(3)] = int64(-49) },
+		"missing pub":  func(k map[any]any) { delete(k, int64(-1)) },
+		"text pub":     func(k map[any]any) { k[int64(-1)] = string(pub.Bytes()) },
+		"kid":          func(k map[any]any) { k[int64(2)] = "key-1" },
+		"missing priv": func(k map[any]any) {},
+		"seed size":    func(k map[any]any) { k[int64(-2)] = make([]byte, 31) },
+		"mismatched":   func(k map[any]any) { k[int64(-2)] = otherSeed },
+	} {
+		t.Run(name, func(t *testing.T) {
+			key := valid()
+			modify(key)
+			data, err := marshalCBOR(key)
+			assert.NoError(t, err)
+			_, _, err = ParsePrivateKey(data)
+			assert.Error(t, err)
+		})
+	}
+
+	_, _, err = ParsePublicKey([]byte{0x80})
+	assert.Error(t, err)
+}
+
+func TestSign1(t *testing.T) {
+	payload := []byte("firmware manifest")
+	aad := []byte("device class")
+	for _, s := range mldsa.Schemes() {
+		t.Run(s.Name(), func(t *testing.T) {
+			pub, priv, err := s.GenerateKey(nil)
+			require.NoError(t, err)
+			msg, err := Sign1(priv, payload, aad, &Header{KeyID: []byte("key-1"), ContentType: "application/cbor"})
+			assert.NoError(t, err)
+
+			// COSE_Sign1 = #6.18([protected, unprotected, payload, signature])
+			decoded, err := unmarshalCBOR(msg)
+			assert.NoError(t, err)
+			tagged, ok := decoded.(tag)
+			assert.True(t, ok)
+			assert.Equal(t, uint64(18), tagged.Number)
+			fields := tagged.Content.([]any)
+			assert.Len(t, fields, 4)
+			protected, err := unmarshalCBOR(fields[0].([]byte))
+			assert.NoError(t, err)
+			assert.Equal(t, map[any]any{int64(1): Algorithm(s), int64(3): "application/cbor"}, protected)
+			assert.Equal(t, map[any]any{int64(4): []byte("key-1")}, fields[1])
+			assert.Equal(t, payload, fields[2])
+			sig := fields[3].([]byte)
+			assert.Len(t, sig, s.SignatureSize())
+
+			// The signature is a pure ML-DSA signature of the Sig_structure
+			toBeSigned, err := marshalCBOR([]any{"Signature1", fields[0], aad, payload})
+			assert.NoError(t, err)
+			assert.True(t, pub.Verify(toBeSigned, sig))
+
+			verified, h, err := VerifySign1(pub, msg, aad)
+			assert.NoError(t, err)
+			assert.Equal(t, payload, verified)
+			assert.Equal(t, &Header{Algorithm: Algorithm(s), KeyID: []byte("key-1"), ContentType: "application/cbor"}, h)
+
+			h, err = ParseSign1Header(msg)
+			assert.NoError(t, err)
+			assert.Equal(t, []byte("key-1"), h.KeyID)
+
+			_, _, err = VerifySign1(pub, msg, nil)
+			assert.Error(t, err)
+			other, _, err := s.GenerateKey(nil)
+			require.NoError(t, err)
+			_, _, err = VerifySign1(other, msg, aad)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSign1Untagged(t *testing.T) {
+	pub, priv, err := mldsa.MLDSA44.GenerateKey(nil)
+	require.NoError(t, err)
+	msg, err := Sign1(priv, nil, nil, nil)
+	assert.NoError(t, err)
+	decoded, err := unmarshalCBOR(msg)
+	assert.NoError(t, err)
+	fields := decoded.(tag).Content.([]any)
+	assert.Equal(t, map[any]any{}, fields[1])
+
+	untagged, err := marshalCBOR(fields)
+	assert.NoError(t, err)
+	payload, _, err := VerifySign1(pub, untagged, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, payload)
+}
+
+func TestVerifySign1Malformed(t *testing.T) {
+	pub, priv, err := mldsa.MLDSA44.GenerateKey(nil)
+	require.NoError(t, err)
+	// sign1 returns a COSE_Sign1 message with the given headers, correctly signed
+	sign1 := func(protected map[any]any, unprotected map[any]any, payload any) []byte {
+		t.Helper()
+		encodedProtected, err := marshalCBOR(protected)
+		assert.NoError(t, err)
+		p, _ := payload.([]byte)
+		toBeSigned, err := sigStructure(encodedProtected, nil, p)
+		assert.NoError(t, err)
+		sig, err := priv.Sign(nil, toBeSigned, crypto.Hash(0))
+		assert.NoError(t, err)
+		msg, err := marshalCBOR(tag{Number: 18, Content: []any{encodedProtected, unprotected, payload, sig}})
+		assert.NoError(t, err)
+		return msg
+	}
+	alg := map[any]any{int64(1): int64(-48)}
+	_, _, err = VerifySign1(pub, sign1(alg, map[any]any{}, []byte("payload")), nil)
+	assert.NoError(t, err)
+
+	for name, msg := range map[string][]byte{
+		"not CBOR":        {0xff},
+		"tag":             {0xd1, 0x80},
+		"not an array":    {0xd2, 0xa0},
+		"array length":    {0xd2, 0x83, 0x40, 0xa0, 0x40},
+		"algorithm":       sign1(map[any]any{int64(1): int64(-49)}, map[any]any{}, []byte("payload")),
+		"unprotected alg": sign1(map[any]any{}, alg, []byte("payload")),
+		"duplicate label": sign1(alg, map[any]any{int64(1): int64(-48)}, []byte("payload")),
+		"crit":            sign1(map[any]any{int64(1): int64(-48), int64(2): []any{int64(-70000)}}, map[any]any{}, []byte("payload")),
+		"content type":    sign1(map[any]any{int64(1): int64(-48), int64(3): int64(60)}, map[any]any{}, []byte("payload")),
+		"kid":             sign1(alg, map[any]any{int64(4): "key-1"}, []byte("payload")),
+		"detached":        sign1(alg, map[any]any{}, nil),
+		"text payload":    sign1(alg, map[any]any{}, "payload"),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := VerifySign1(pub, msg, nil)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,39 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cose_test
+
+import (
+	"fmt"
+
+	"github.com/trailofbits/ml-dsa/cose"
+	"github.com/trailofbits/ml-dsa/mldsa"
+)
+
+func ExampleSign1() {
+	pub, priv, err := mldsa.MLDSA87.GenerateKey(nil)
+	if err != nil {
+		panic(err)
+	}
+	key, err := cose.MarshalPublicKey(pub, []byte("firmware-2025"))
+	if err != nil {
+		panic(err)
+	}
+
+	msg, err := cose.Sign1(priv, []byte("manifest"), nil, &cose.Header{KeyID: []byte("firmware-2025")})
+	if err != nil {
+		panic(err)
+	}
+
+	verifier, _, err := cose.ParsePublicKey(key)
+	if err != nil {
+		panic(err)
+	}
+	payload, header, err := cose.VerifySign1(verifier, msg, nil)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(header.Algorithm, string(header.KeyID), string(payload))
+	// Output: -50 firmware-2025 manifest
+}
@@ -0,0 +1,187 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package cose encodes ML-DSA keys as COSE_Key structures, and creates and verifies
+// COSE_Sign1 messages ([RFC 9052]), as specified in [draft-ietf-cose-dilithium].
+//
+// Keys use the AKP (Algorithm Key Pair) key type, with the parameter set as the
+// algorithm. The public key defined in FIPS 204 is the "pub" parameter, and private
+// keys hold their 32-byte seed in the "priv" parameter. Private keys without a
+// known seed cannot be encoded.
+//
+// Signatures use pure ML-DSA and the empty context string.
+//
+// The package includes its own encoder and decoder for the subset of CBOR that
+// these structures use, and has no dependencies beyond the standard library.
+//
+// [RFC 9052]: https://www.rfc-editor.org/rfc/rfc9052
+// [draft-ietf-cose-dilithium]: https://datatracker.ietf.org/doc/draft-ietf-cose-dilithium/
+package cose
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/trailofbits/ml-dsa/mldsa"
+)
+
+// COSE algorithm identifiers of the ML-DSA parameter sets.
+const (
+	AlgorithmMLDSA44 int64 = -48
+	AlgorithmMLDSA65 int64 = -49
+	AlgorithmMLDSA87 int64 = -50
+)
+
+// KeyTypeAKP is the COSE key type of ML-DSA keys.
+const KeyTypeAKP int64 = 7
+
+// COSE_Key parameter labels.
+const (
+	keyLabelKty  int64 = 1
+	keyLabelKid  int64 = 2
+	keyLabelAlg  int64 = 3
+	keyLabelPub  int64 = -1
+	keyLabelPriv int64 = -2
+)
+
+// Algorithm returns the COSE algorithm identifier of the parameter set s.
+func Algorithm(s mldsa.Scheme) int64 {
+	switch s {
+	case mldsa.MLDSA44:
+		return AlgorithmMLDSA44
+	case mldsa.MLDSA65:
+		return AlgorithmMLDSA65
+	case mldsa.MLDSA87:
+		return AlgorithmMLDSA87
+	}
+	return 0
+}
+
+// SchemeByAlgorithm returns the parameter set with the COSE algorithm identifier
+// alg, or nil if there is none.
+func SchemeByAlgorithm(alg int64) mldsa.Scheme {
+	for _, s := range mldsa.Schemes() {
+		if Algorithm(s) == alg {
+			return s
+		}
+	}
+	return nil
+}
+
+// MarshalPublicKey encodes pub as a COSE_Key. kid is the optional key identifier.
+func MarshalPublicKey(pub mldsa.PublicKey, kid []byte) ([]byte, error) {
+	scheme := mldsa.SchemeOf(pub)
+	if scheme == nil {
+		return nil, errors.New("cose: unsupported public key type")
+	}
+	key := map[any]any{
+		keyLabelKty: KeyTypeAKP,
+		keyLabelAlg: Algorithm(scheme),
+		keyLabelPub: pub.Bytes(),
+	}
+	if kid != nil {
+		key[keyLabelKid] = kid
+	}
+	return marshalCBOR(key)
+}
+
+// MarshalPrivateKey encodes priv as a COSE_Key, holding both its seed and its
+// public key. It returns an error if the seed of priv is not known.
+func MarshalPrivateKey(priv mldsa.PrivateKey, kid []byte) ([]byte, error) {
+	scheme := mldsa.SchemeOf(priv)
+	if scheme == nil {
+		return nil, errors.New("cose: unsupported private key type")
+	}
+	seed, err := priv.Seed()
+	if err != nil {
+		return nil, fmt.Errorf("cose: %w", err)
+	}
+	defer clear(seed)
+	key := map[any]any{
+		keyLabelKty:  KeyTypeAKP,
+		keyLabelAlg:  Algorithm(scheme),
+		keyLabelPub:  priv.Public().(mldsa.PublicKey).Bytes(),
+		keyLabelPriv: seed,
+	}
+	if kid != nil {
+		key[keyLabelKid] = kid
+	}
+	return marshalCBOR(key)
+}
+
+// parseKey decodes the common parameters of a COSE_Key, and returns the key
+// map, its parameter set and its public key.
+func parseKey(data []byte) (map[any]any, mldsa.Scheme, mldsa.PublicKey, error) {
+	v, err := unmarshalCBOR(data)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("cose: malformed COSE_Key: %w", err)
+	}
+	key, ok := v.(map[any]any)
+	if !ok {
+		return nil, nil, nil, errors.New("cose: malformed COSE_Key")
+	}
+	if kty, _ := key[keyLabelKty].(int64); kty != KeyTypeAKP {
+		return nil, nil, nil, fmt.Errorf("cose: unsupported COSE_Key key type %v", key[keyLabelKty])
+	}
+	alg, _ := key[keyLabelAlg].(int64)
+	scheme := SchemeByAlgorithm(alg)
+	if scheme == nil {
+		return nil, nil, nil, fmt.Errorf("cose: unsupported COSE_Key algorithm %v", key[keyLabelAlg])
+	}
+	if kid, ok := key[keyLabelKid]; ok {
+		if _, ok := kid.([]byte); !ok {
+			return nil, nil, nil, errors.New("cose: malformed COSE_Key key identifier")
+		}
+	}
+	b, ok := key[keyLabelPub].([]byte)
+	if !ok || len(b) != scheme.PublicKeySize() {
+		return nil, nil, nil, errors.New("cose: malformed COSE_Key public key")
+	}
+	pub, err := scheme.PublicKeyFromBytes(b)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("cose: %w", err)
+	}
+	return key, scheme, pub, nil
+}
+
+// ParsePublicKey decodes an ML-DSA COSE_Key, and returns its public key and key
+// identifier. The seed of a private COSE_Key is ignored.
+func ParsePublicKey(data []byte) (mldsa.PublicKey, []byte, error) {
+	key, _, pub, err := parseKey(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	kid, _ := key[keyLabelKid].([]byte)
+	return pub, kid, nil
+}
+
+// ParsePrivateKey decodes a private ML-DSA COSE_Key, and returns its private key
+// and key identifier. The public key in the COSE_Key must be the one derived from
+// the seed.
+func ParsePrivateKey(data []byte) (mldsa.PrivateKey, []byte, error) {
+	key, scheme, pub, err := parseKey(data)
+	if err != nil {
+		return nil, nil, err
+	}
+	encoded, ok := key[keyLabelPriv]
+	if !ok {
+		return nil, nil, errors.New("cose: COSE_Key is not a private key")
+	}
+	seed, ok := encoded.([]byte)
+	defer clear(seed)
+	if !ok || len(seed) != scheme.SeedSize() {
+		return nil, nil, errors.New("cose: malformed COSE_Key private key")
+	}
+	priv, err := scheme.PrivateKeyFromSeed(seed)
+	if err != nil {
+		return nil, nil, fmt.Errorf("cose: %w", err)
+	}
+	if !bytes.Equal(priv.Public().(mldsa.PublicKey).Bytes(), pub.Bytes()) {
+		priv.Destroy()
+		return nil, nil, errors.New("cose: COSE_Key public key does not match the private key")
+	}
+	kid, _ := key[keyLabelKid].([]byte)
+	return priv, kid, nil
+}
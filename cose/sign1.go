@@ -0,0 +1,192 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package cose
+
+import (
+	"crypto"
+	"errors"
+	"fmt"
+
+	"github.com/trailofbits/ml-dsa/mldsa"
+)
+
+// tagSign1 is the CBOR tag of a COSE_Sign1 message.
+const tagSign1 = 18
+
+// Common COSE header parameter labels.
+const (
+	headerLabelAlg         int64 = 1
+	headerLabelCrit        int64 = 2
+	headerLabelContentType int64 = 3
+	headerLabelKid         int64 = 4
+)
+
+// Header holds the COSE header parameters of a COSE_Sign1 message that this
+// package understands.
+type Header struct {
+	// Algorithm is the algorithm identifier of the signing key, in the protected
+	// header. It is set by Sign1.
+	Algorithm int64
+	// KeyID is the optional key identifier, in the unprotected header.
+	KeyID []byte
+	// ContentType is the optional media type of the payload, in the protected header.
+	ContentType string
+}
+
+// Sign1 signs payload with priv, and returns the tagged COSE_Sign1 message holding
+// it. externalAAD is additional data that is signed but not included in the
+// message, and may be nil. The algorithm is set to the parameter set of priv,
+// and h provides the other header parameters. h may be nil.
+func Sign1(priv mldsa.PrivateKey, payload, externalAAD []byte, h *Header) ([]byte, error) {
+	scheme := mldsa.SchemeOf(priv)
+	if scheme == nil {
+		return nil, errors.New("cose: unsupported private key type")
+	}
+	protected := map[any]any{headerLabelAlg: Algorithm(scheme)}
+	unprotected := map[any]any{}
+	if h != nil && h.ContentType != "" {
+		protected[headerLabelContentType] = h.ContentType
+	}
+	if h != nil && h.KeyID != nil {
+		unprotected[headerLabelKid] = h.KeyID
+	}
+	encodedProtected, err := marshalCBOR(protected)
+	if err != nil {
+		return nil, err
+	}
+
+	toBeSigned, err := sigStructure(encodedProtected, externalAAD, payload)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := priv.Sign(nil, toBeSigned, crypto.Hash(0))
+	if err != nil {
+		return nil, fmt.Errorf("cose: %w", err)
+	}
+	return marshalCBOR(tag{Number: tagSign1, Content: []any{encodedProtected, unprotected, payload, sig}})
+}
+
+// sigStructure returns the encoded Sig_structure of a COSE_Sign1 message
+// (RFC 9052, Section 4.4).
+func sigStructure(protected, externalAAD, payload []byte) ([]byte, error) {
+	if externalAAD == nil {
+		externalAAD = []byte{}
+	}
+	return marshalCBOR([]any{"Signature1", protected, externalAAD, payload})
+}
+
+// sign1 is a decoded COSE_Sign1 message.
+type sign1 struct {
+	protected []byte
+	header    Header
+	payload   []byte
+	signature []byte
+}
+
+// parseSign1 decodes a tagged or untagged COSE_Sign1 message.
+func parseSign1(msg []byte) (*sign1, error) {
+	v, err := unmarshalCBOR(msg)
+	if err != nil {
+		return nil, fmt.Errorf("cose: malformed COSE_Sign1: %w", err)
+	}
+	if t, ok := v.(tag); ok {
+		if t.Number != tagSign1 {
+			return nil, fmt.Errorf("cose: unexpected CBOR tag %d", t.Number)
+		}
+		v = t.Content
+	}
+	a, ok := v.([]any)
+	if !ok || len(a) != 4 {
+		return nil, errors.New("cose: malformed COSE_Sign1")
+	}
+	var s sign1
+	var unprotected map[any]any
+	var ok1, ok2, ok3 bool
+	s.protected, ok1 = a[0].([]byte)
+	unprotected, ok2 = a[1].(map[any]any)
+	s.signature, ok3 = a[3].([]byte)
+	if !ok1 || !ok2 || !ok3 {
+		return nil, errors.New("cose: malformed COSE_Sign1")
+	}
+	if a[2] == nil {
+		return nil, errors.New("cose: detached COSE_Sign1 payloads are not supported")
+	}
+	if s.payload, ok = a[2].([]byte); !ok {
+		return nil, errors.New("cose: malformed COSE_Sign1")
+	}
+
+	// An empty protected header may be encoded as a zero-length byte string
+	protected := map[any]any{}
+	if len(s.protected) != 0 {
+		v, err := unmarshalCBOR(s.protected)
+		if err != nil {
+			return nil, fmt.Errorf("cose: malformed COSE_Sign1 protected header: %w", err)
+		}
+		if protected, ok = v.(map[any]any); !ok {
+			return nil, errors.New("cose: malformed COSE_Sign1 protected header")
+		}
+	}
+	for label := range unprotected {
+		if _, ok := protected[label]; ok {
+			return nil, errors.New("cose: duplicate COSE_Sign1 header parameter")
+		}
+	}
+	// No extensions are supported, so critical ones must be rejected
+	if _, ok := protected[headerLabelCrit]; ok {
+		return nil, errors.New("cose: unsupported critical COSE_Sign1 header parameters")
+	}
+	// The algorithm must be integrity protected
+	if s.header.Algorithm, ok = protected[headerLabelAlg].(int64); !ok {
+		return nil, errors.New("cose: COSE_Sign1 algorithm missing from the protected header")
+	}
+	if cty, ok := protected[headerLabelContentType]; ok {
+		if s.header.ContentType, ok = cty.(string); !ok {
+			return nil, errors.New("cose: unsupported COSE_Sign1 content type")
+		}
+	}
+	for _, header := range []map[any]any{protected, unprotected} {
+		if kid, ok := header[headerLabelKid]; ok {
+			if s.header.KeyID, ok = kid.([]byte); !ok {
+				return nil, errors.New("cose: malformed COSE_Sign1 key identifier")
+			}
+		}
+	}
+	return &s, nil
+}
+
+// ParseSign1Header returns the header parameters of a COSE_Sign1 message, without
+// verifying it, for instance to select the verification key by its identifier.
+func ParseSign1Header(msg []byte) (*Header, error) {
+	s, err := parseSign1(msg)
+	if err != nil {
+		return nil, err
+	}
+	return &s.header, nil
+}
+
+// VerifySign1 verifies a tagged or untagged COSE_Sign1 message with pub and
+// externalAAD, and returns its payload and header parameters. The algorithm must
+// be the one of pub. Messages with a detached payload are not supported.
+func VerifySign1(pub mldsa.PublicKey, msg, externalAAD []byte) ([]byte, *Header, error) {
+	scheme := mldsa.SchemeOf(pub)
+	if scheme == nil {
+		return nil, nil, errors.New("cose: unsupported public key type")
+	}
+	s, err := parseSign1(msg)
+	if err != nil {
+		return nil, nil, err
+	}
+	if s.header.Algorithm != Algorithm(scheme) {
+		return nil, nil, fmt.Errorf("cose: COSE_Sign1 algorithm %d does not match the %s key", s.header.Algorithm, scheme.Name())
+	}
+	toBeSigned, err := sigStructure(s.protected, externalAAD, s.payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !pub.Verify(toBeSigned, s.signature) {
+		return nil, nil, errors.New("cose: invalid COSE_Sign1 signature")
+	}
+	return s.payload, &s.header, nil
+}
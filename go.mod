@@ -3,13 +3,14 @@ module github.com/trailofbits/ml-dsa
 go 1.24.0
 
 require (
+	github.com/cloudflare/circl v1.6.1
 	github.com/stretchr/testify v1.10.0
 	golang.org/x/crypto v0.45.0
+	golang.org/x/sys v0.38.0
 )
 
 require (
 	github.com/davecgh/go-spew v1.1.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
-	golang.org/x/sys v0.38.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
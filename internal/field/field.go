@@ -13,20 +13,32 @@ import (
 )
 
 // T is the type of field elements F_q, where q = 8380417.
+//
+// Elements are kept in Montgomery form: the element a is stored as a * 2³² mod q,
+// fully reduced. Addition and subtraction are unchanged by this representation,
+// and multiplication only needs a single Montgomery reduction.
 type T struct {
-	reduced uint32 // TODO: implement Montgomery representation
+	mont uint32 // a * 2³² mod q, in [0, q)
 }
 
 const (
 	q                 = params.Q
 	d                 = params.D
-	barrettMultiplier = 8396807 // 2²³ * 2²³ / q
-	barrettShift      = 46      // log₂(2²³ * 2²³)
+	qNegInv           = 4236238847    // -q⁻¹ mod 2³²
+	rSquared          = 2365951       // 2⁶⁴ mod q
+	barrettMultiplier = 2201172575745 // floor(2⁶⁴ / q)
 )
 
+// montgomeryReduce returns x * 2⁻³² mod q, in [0, 2q), for x < q * 2³².
+func montgomeryReduce(x uint64) uint32 {
+	m := uint32(x) * qNegInv
+	// x + m*q is divisible by 2³², and less than 2q * 2³²
+	return uint32((x + uint64(m)*q) >> 32)
+}
+
 // NewFromReduced creates a new field element from a reduced non-negative value.
 func NewFromReduced(reduced uint32) T {
-	return T{reduced: reduced}
+	return T{reduceOnce(montgomeryReduce(uint64(reduced) * rSquared))}
 }
 
 // NewFromSymmetric creates a new field element from a reduced signed value.
@@ -35,54 +47,57 @@ func NewFromSymmetric(x int32) T {
 	return NewFromReduced(y)
 }
 
-// Reduce a field element once, mod q.
-func reduceOnce(a uint32) T {
+// Reduce a value in [0, 2q) once, mod q.
+func reduceOnce(a uint32) uint32 {
 	x := uint32(a - q)
 	x += (x >> 31) * q
-	return NewFromReduced(x)
+	return x
 }
 
 // Add two field elements, mod q.
 func (a T) Add(b T) T {
-	return reduceOnce(a.reduced + b.reduced)
+	return T{reduceOnce(a.mont + b.mont)}
 }
 
 // Subtract two field elements, mod q.
 func (a T) Sub(b T) T {
-	return reduceOnce(a.reduced - b.reduced + q)
+	return T{reduceOnce(a.mont - b.mont + q)}
 }
 
 // Negate a field element, mod q.
 func (a T) Neg() T {
-	return reduceOnce(q - a.reduced)
+	return T{reduceOnce(q - a.mont)}
 }
 
 // Multiply two field elements, mod q.
 func (a T) Mul(b T) T {
-	// Constant-time Barrett reduction for 64-bit product modulo q
-	// q fits in 32 bits. Let mu = floor(2^64 / q).
-	// For x < q^2, r = x - floor((x * mu) / 2^64) * q gives r < 2q.
-	// Apply two branchless correction steps to ensure r < q.
-	const q64 = uint64(q)
-	const mu uint64 = 2201172575745 // floor(2^64 / 8380417)
-
-	x := uint64(a.reduced) * uint64(b.reduced)
-	hi, _ := bits.Mul64(x, mu)
-	r := x - hi*q64
-
-	// First correction: if r >= q then r -= q (branchless)
-	r1, borrow := bits.Sub64(r, q64, 0)
-	correction := borrow ^ 1 // 1 if r >= q, 0 otherwise
-	mask := uint64(0) - uint64(correction)
-	r ^= mask & (r ^ r1)
-
-	// Second correction (safe even if not needed)
-	r2, borrow2 := bits.Sub64(r, q64, 0)
-	correction2 := borrow2 ^ 1
-	mask2 := uint64(0) - uint64(correction2)
-	r ^= mask2 & (r ^ r2)
-
-	return T{uint32(r)}
+	// (a * 2³²) * (b * 2³²) * 2⁻³² = (a * b) * 2³²
+	return T{reduceOnce(montgomeryReduce(uint64(a.mont) * uint64(b.mont)))}
+}
+
+// Lazy is a field element in Montgomery form that is not necessarily reduced:
+// any uint32 x represents the field element x * 2⁻³² mod q. It lets sequences of
+// additions, such as the layers of the NTT, skip the reductions of T.Add and
+// T.Sub. Lazy values are added and subtracted with the usual integer operators,
+// and callers are responsible for keeping them in [0, 2³²).
+type Lazy uint32
+
+// Lazy returns a as a Lazy value, in [0, q).
+func (a T) Lazy() Lazy {
+	return Lazy(a.mont)
+}
+
+// Mul multiplies a by b, mod q. The result is in [0, 2q).
+func (a Lazy) Mul(b T) Lazy {
+	// a < 2³² and b < q, so the product is in range of montgomeryReduce
+	return Lazy(montgomeryReduce(uint64(a) * uint64(b.mont)))
+}
+
+// Reduce fully reduces a to a field element.
+func (a Lazy) Reduce() T {
+	// Barrett reduction: for x < 2³², r = x - floor((x * mu) / 2⁶⁴) * q gives r < 2q.
+	hi, _ := bits.Mul64(uint64(a), barrettMultiplier)
+	return T{reduceOnce(uint32(a) - uint32(hi)*q)}
 }
 
 // Power2Round (Algorithm 35) decomposes a field element x into two components:
@@ -90,7 +105,8 @@ func (a T) Mul(b T) T {
 // and r0 \in (-2^(d-1), 2^(d-1)].
 // r1 is then in the range [0, q/2^d), which is 10 bits
 func (a T) Power2Round() (int32, int32) {
-	r0 := int32(a.reduced) & ((1 << d) - 1)
+	reduced := int32(a.Reduced())
+	r0 := reduced & ((1 << d) - 1)
 
 	// Constant-time conditional subtraction to avoid timing side-channels
 	// Check if r0 > 2^(d-1) without branching
@@ -107,7 +123,7 @@ func (a T) Power2Round() (int32, int32) {
 	adjustment := mask & (1 << d)
 	r0 -= adjustment
 
-	r1 := (int32(a.reduced) - r0) >> d
+	r1 := (reduced - r0) >> d
 	return r1, r0
 }
 
@@ -168,51 +184,49 @@ func (a T) HighBits(gamma2 uint32) int32 {
 // InfinityNorm computes the absolute value of the
 // signed symmetric representation of a field element.
 func (a T) InfinityNorm() uint32 {
-	return min(a.reduced, q-a.reduced)
+	reduced := a.Reduced()
+	return min(reduced, q-reduced)
 }
 
 // Reduced returns the [0, q-1] representative
 func (a T) Reduced() uint32 {
-	return a.reduced
+	// a * 2³² * 2⁻³², where montgomeryReduce returns a value in [0, q] for inputs below 2³²
+	return reduceOnce(montgomeryReduce(uint64(a.mont)))
 }
 
 // Symmetric returns the [-q/2, q/2]
 func (a T) Symmetric() int32 {
-	mask := subtle.ConstantTimeLessOrEq(q>>1+1, int(a.reduced))
-	return int32(a.reduced) - (int32(mask) * int32(q))
+	reduced := a.Reduced()
+	mask := subtle.ConstantTimeLessOrEq(q>>1+1, int(reduced))
+	return int32(reduced) - (int32(mask) * int32(q))
 }
 
 // Algorithm 15
 // Input: eta, byte
-// Output: integer between -eta and eta or nil for rejection
-func FromHalfByte(eta int, b byte) *T {
-	var r T
+// Output: integer between -eta and eta, and false for rejection
+func FromHalfByte(eta int, b byte) (T, bool) {
 	if eta == 2 && b < 15 {
-		r = NewFromSymmetric(2 - (int32(b) % 5))
-		return &r
+		return NewFromSymmetric(2 - (int32(b) % 5)), true
 	}
 	if eta == 4 && b < 9 {
-		r = NewFromSymmetric(4 - int32(b))
-		return &r
+		return NewFromSymmetric(4 - int32(b)), true
 	}
-	return nil
+	return T{}, false
 }
 
 // Algorithm 14
 // Inputs: 3 bytes
-// Output: integer mod q or nil for rejection
-func FromThreeBytes(b0, b1, b2 byte) *T {
-	var r T
+// Output: integer mod q, and false for rejection
+func FromThreeBytes(b0, b1, b2 byte) (T, bool) {
 	bp2 := uint32(b2 & 0x7f)
 	z := (bp2 << 16) | uint32(b1)<<8 | uint32(b0)
 
 	// if q >= z, return an error
 	invalid := (q - (z + 1)) >> 31
 	if invalid != 0 {
-		return nil
+		return T{}, false
 	}
-	r = reduceOnce(z)
-	return &r
+	return NewFromReduced(z), true
 }
 
 // Alternate to integer division by using Barrett Reduction
@@ -1,6 +1,7 @@
 package field_test
 
 import (
+	"math/bits"
 	"math/rand"
 	"testing"
 
@@ -230,3 +231,59 @@ func TestDivConstTime32(t *testing.T) {
 		}
 	}
 }
+
+func TestLazy(t *testing.T) {
+	// Lazy values represent x * 2⁻³² mod q, for any uint32 x
+	for i := 0; i < 1000; i++ {
+		a := uint32(rand.Intn(int(q)))
+		b := uint32(rand.Intn(int(q)))
+		aF := field.NewFromReduced(a)
+		bF := field.NewFromReduced(b)
+		assert.Equal(t, aF, aF.Lazy().Reduce())
+
+		// Adding multiples of q must not change the element
+		k := field.Lazy(rand.Intn((1<<32-1)/q - 1))
+		assert.Equal(t, aF, (aF.Lazy() + k*q).Reduce())
+
+		product := (uint64(a) * uint64(b)) % uint64(q)
+		lazy := (aF.Lazy() + k*q).Mul(bF)
+		assert.Less(t, uint32(lazy), uint32(2*q))
+		assert.Equal(t, uint32(product), lazy.Reduce().Reduced())
+	}
+
+	// The largest lazy value
+	aF := field.NewFromReduced(q - 1)
+	top := field.Lazy(1<<32 - 1)
+	assert.Equal(t, (top % q).Mul(aF).Reduce(), top.Mul(aF).Reduce())
+	assert.Equal(t, (top % q).Reduce(), top.Reduce())
+}
+
+// barrettMul is the previous implementation of T.Mul, on values in standard form.
+func barrettMul(a, b uint32) uint32 {
+	const mu uint64 = 2201172575745 // floor(2^64 / q)
+	x := uint64(a) * uint64(b)
+	hi, _ := bits.Mul64(x, mu)
+	r := x - hi*q
+	for range 2 {
+		r1, borrow := bits.Sub64(r, q, 0)
+		mask := uint64(0) - uint64(borrow^1)
+		r ^= mask & (r ^ r1)
+	}
+	return uint32(r)
+}
+
+func BenchmarkMul(b *testing.B) {
+	a := uint32(rand.Intn(int(q)))
+	c := uint32(rand.Intn(int(q)))
+	b.Run("montgomery", func(b *testing.B) {
+		aF, cF := field.NewFromReduced(a), field.NewFromReduced(c)
+		for b.Loop() {
+			aF = aF.Mul(cF)
+		}
+	})
+	b.Run("barrett", func(b *testing.B) {
+		for b.Loop() {
+			a = barrettMul(a, c)
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package internal
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/ml-dsa/internal/params"
+	options "github.com/trailofbits/ml-dsa/options"
+)
+
+// The seed corpora in testdata/fuzz hold valid keys and messages for each
+// parameter set.
+
+// fuzzCfg selects a parameter set from a fuzzed byte.
+func fuzzCfg(p uint8) *params.Cfg {
+	return ps[int(p)%len(ps)]
+}
+
+func FuzzPkDecode(f *testing.F) {
+	f.Fuzz(func(t *testing.T, p uint8, pk []byte) {
+		vk, err := PkDecode(fuzzCfg(p), pk)
+		if err != nil {
+			return
+		}
+		assert.Equal(t, pk, vk.Bytes())
+	})
+}
+
+func FuzzSkDecode(f *testing.F) {
+	f.Fuzz(func(t *testing.T, p uint8, sk []byte) {
+		key, err := SkDecode(fuzzCfg(p), sk)
+		if err != nil {
+			return
+		}
+		assert.Equal(t, sk, key.EncodeExpanded())
+	})
+}
+
+// FuzzSignVerify checks that signatures verify, and that flipping any single bit
+// of a signature makes it invalid.
+func FuzzSignVerify(f *testing.F) {
+	f.Fuzz(func(t *testing.T, p uint8, seed, msg []byte, context string, bit uint) {
+		var s [32]byte
+		copy(s[:], seed)
+		sk, err := FromSeed(fuzzCfg(p), s[:])
+		assert.NoError(t, err)
+
+		opts := &options.Options{Context: context}
+		sig, err := sk.Sign(zeroReader{}, msg, opts)
+		if len(context) > 255 {
+			assert.Error(t, err)
+			return
+		}
+		assert.NoError(t, err)
+		vk := sk.Public()
+		assert.True(t, vk.Verify(msg, sig, opts))
+
+		bit %= uint(8 * len(sig))
+		sig[bit/8] ^= 1 << (bit % 8)
+		assert.False(t, vk.Verify(msg, sig, opts), "bit %d", bit)
+	})
+}
@@ -2,6 +2,7 @@ package internal
 
 import (
 	"crypto/rand"
+	"crypto/sha3"
 	"crypto/subtle"
 	"errors"
 	"fmt"
@@ -11,13 +12,39 @@ import (
 	"github.com/trailofbits/ml-dsa/internal/params"
 	"github.com/trailofbits/ml-dsa/internal/ring"
 	"github.com/trailofbits/ml-dsa/internal/util"
-	"golang.org/x/crypto/sha3"
 )
 
 type VerifyingKey struct {
 	cfg *params.Cfg
 	rho [32]byte  // Rho is the public seed
 	t1  []ring.Rq // Length cfg.K
+
+	precomputed *precomputedVerifyingKey // nil unless Precompute was called
+}
+
+// precomputedVerifyingKey holds the values that verification derives from the key,
+// independently of the signature.
+type precomputedVerifyingKey struct {
+	tr        [64]byte
+	Ahat      [][]ring.Tq // cfg.K x cfg.L
+	t1_2d_hat []ring.Tq   // NTT(t1 * 2^d), length cfg.K
+}
+
+// Precompute caches tr, ExpandA(rho) and NTT(t1 * 2^d) in vk, so that verification
+// only does the work that depends on the signature. Each of the matrix and vector
+// elements is a 1 KiB NTT ring element, so the cache takes (K*L + K) KiB of memory.
+//
+// Precompute must not be called concurrently with other methods of vk.
+func (vk *VerifyingKey) Precompute() {
+	if vk.precomputed != nil {
+		return
+	}
+	pre := &precomputedVerifyingKey{
+		Ahat:      util.ExpandA(vk.cfg, vk.rho[:]),
+		t1_2d_hat: vk.computeT1Hat(),
+	}
+	util.H(pre.tr[:], vk.Bytes())
+	vk.precomputed = pre
 }
 
 type SigningKey struct {
@@ -30,6 +57,81 @@ type SigningKey struct {
 	s2   []ring.Rq // Length cfg.K
 	t0   []ring.Rq // Length cfg.K
 	t1   []ring.Rq // Component of verifying key - cached for efficiency
+
+	precomputed *precomputedSigningKey // nil unless Precompute was called
+	destroyed   bool                   // set by Destroy
+}
+
+var errDestroyed = errors.New("signing key has been destroyed")
+
+// precomputedSigningKey holds the values that signing derives from the key,
+// before the rejection sampling loop.
+type precomputedSigningKey struct {
+	s1hat []ring.Tq   // Length cfg.L
+	s2hat []ring.Tq   // Length cfg.K
+	t0hat []ring.Tq   // Length cfg.K
+	Ahat  [][]ring.Tq // cfg.K x cfg.L
+}
+
+// Precompute caches NTT(s1), NTT(s2), NTT(t0) and ExpandA(rho) in sk, so that
+// signing does not recompute them for every signature. Each of them is made of
+// 1 KiB NTT ring elements, so the cache takes (K*L + L + 2*K) KiB of memory.
+//
+// Precompute must not be called concurrently with other methods of sk.
+func (sk *SigningKey) Precompute() {
+	if sk.precomputed == nil && !sk.destroyed {
+		sk.precomputed = sk.precompute()
+	}
+}
+
+// precomputeTo computes the values of precompute in the scratch space of sc.
+func (sk *SigningKey) precomputeTo(sc *signScratch) *precomputedSigningKey {
+	k, l := sk.cfg.K, sk.cfg.L
+	pre := &sc.pre
+	pre.s1hat, pre.s2hat, pre.t0hat = sc.s1hat[:l], sc.s2hat[:k], sc.t0hat[:k]
+	for i := range pre.s1hat {
+		pre.s1hat[i] = util.NTT(sk.s1[i])
+	}
+	for i := range pre.s2hat {
+		pre.s2hat[i] = util.NTT(sk.s2[i])
+		pre.t0hat[i] = util.NTT(sk.t0[i])
+	}
+	pre.Ahat = matrix(&sc.Ahat, &sc.AhatRows, k, l)
+	util.ExpandATo(sk.cfg, pre.Ahat, sk.rho[:])
+	return pre
+}
+
+// Destroy overwrites the secret values of sk with zeros: the seed, K, s1, s2, t0 and
+// their precomputed NTT forms. Afterwards, signing with sk and encoding sk return an
+// error. The public values rho, tr and t1 are kept, so that Public still works.
+//
+// Zeroization is best-effort: the Go runtime may have left copies of secret values
+// in memory that is not reachable from sk, for instance when growing a stack.
+//
+// Destroy must not be called concurrently with other methods of sk.
+func (sk *SigningKey) Destroy() {
+	clear(sk.seed)
+	sk.seed = nil
+	clear(sk.K[:])
+	clear(sk.s1)
+	clear(sk.s2)
+	clear(sk.t0)
+	if pre := sk.precomputed; pre != nil {
+		clear(pre.s1hat)
+		clear(pre.s2hat)
+		clear(pre.t0hat)
+		sk.precomputed = nil
+	}
+	sk.destroyed = true
+}
+
+func (sk *SigningKey) precompute() *precomputedSigningKey {
+	return &precomputedSigningKey{
+		s1hat: util.NttVec(sk.s1),
+		s2hat: util.NttVec(sk.s2),
+		t0hat: util.NttVec(sk.t0),
+		Ahat:  util.ExpandA(sk.cfg, sk.rho[:]),
+	}
 }
 
 // Serialize a public verifying key to bytes.
@@ -38,11 +140,33 @@ func (vk *VerifyingKey) Bytes() []byte {
 	pk := make([]byte, 0, vk.cfg.PkSize)
 	pk = append(pk, vk.rho[:]...)
 	for i := range vk.cfg.K {
-		pk = append(pk, util.SimpleBitPack(vk.t1[i].Symmetric(), 10)...)
+		pk = util.AppendSimpleBitPack(pk, vk.t1[i].Symmetric(), 10)
 	}
 	return pk
 }
 
+// tr <- H(pk, 64)
+func (vk *VerifyingKey) computeTr() []byte {
+	tr := new([64]byte)
+	vk.computeTrTo(tr)
+	return tr[:]
+}
+
+func (vk *VerifyingKey) computeTrTo(tr *[64]byte) {
+	if vk.precomputed != nil {
+		*tr = vk.precomputed.tr
+		return
+	}
+	// pk is hashed one packed polynomial at a time, rather than encoded first
+	var packed [320]byte
+	h := sha3.NewSHAKE256()
+	h.Write(vk.rho[:]) //nolint:errcheck
+	for i := range vk.cfg.K {
+		h.Write(util.AppendSimpleBitPack(packed[:0], vk.t1[i].Symmetric(), 10)) //nolint:errcheck
+	}
+	h.Read(tr[:]) //nolint:errcheck
+}
+
 func PkDecode(cfg *params.Cfg, pk []byte) (*VerifyingKey, error) {
 	if len(pk) != int(cfg.PkSize) {
 		return nil, errors.New("invalid public key size")
@@ -85,6 +209,8 @@ func SkDecode(cfg *params.Cfg, sk []byte) (*SigningKey, error) {
 
 	s1 := make([]ring.Rz, l)
 	s2 := make([]ring.Rz, k)
+	defer clear(s1)
+	defer clear(s2)
 
 	// We don't need to parse t0, since we can compute it from s1 and s2.
 	// t0 := make([]ring.Rz, k)
@@ -132,6 +258,7 @@ func SkDecode(cfg *params.Cfg, sk []byte) (*SigningKey, error) {
 	// We do guarantee that `t0` and `tr` in the serialized key is correct, by
 	// checking the round-trip serialization.
 	enc := res.EncodeExpanded()
+	defer clear(enc)
 	if subtle.ConstantTimeCompare(enc, expected) != 1 {
 		return nil, errors.New("invalid secret key")
 	}
@@ -140,8 +267,14 @@ func SkDecode(cfg *params.Cfg, sk []byte) (*SigningKey, error) {
 }
 
 // We do not recommend actually ever using this. Store the seed instead.
-func (sk SigningKey) EncodeExpanded() []byte {
-	encoded := append(sk.rho[:], sk.K[:]...)
+// Returns nil if sk was destroyed.
+func (sk *SigningKey) EncodeExpanded() []byte {
+	if sk.destroyed {
+		return nil
+	}
+	encoded := make([]byte, 0, sk.cfg.SkSize)
+	encoded = append(encoded, sk.rho[:]...)
+	encoded = append(encoded, sk.K[:]...)
 	encoded = append(encoded, sk.tr[:]...)
 
 	for i := range sk.cfg.L {
@@ -171,9 +304,11 @@ func FromSeed(cfg *params.Cfg, seed []byte) (*SigningKey, error) {
 	sk.seed = make([]byte, 32)
 	copy(sk.seed, seed)
 
-	rhoPrime := make([]byte, 64)
+	var rhoPrime [64]byte
+	defer clear(rhoPrime[:])
 
-	h := sha3.NewShake256()
+	h := sha3.NewSHAKE256()
+	defer h.Reset()
 	h.Write(seed[:])
 	h.Write([]byte{cfg.K, cfg.L})
 
@@ -228,6 +363,9 @@ func GenerateKeyPair(cfg *params.Cfg, rng io.Reader) (*SigningKey, *VerifyingKey
 }
 
 func (sk *SigningKey) Bytes() ([]byte, error) {
+	if sk.destroyed {
+		return nil, errDestroyed
+	}
 	if sk.seed == nil {
 		return nil, errors.New("key was not generated from a seed; use EncodeExpanded instead")
 	}
@@ -246,13 +384,22 @@ func (sk *SigningKey) Public() *VerifyingKey {
 func (sk *SigningKey) computeT() error {
 	ahat := util.ExpandA(sk.cfg, sk.rho[:])
 	s1hat := util.NttVec(sk.s1)
-	tmp := util.InvNttVec(util.MatrixVectorNTT(ahat, s1hat))
-	t1, t0 := util.Power2RoundVec(util.AddVector(tmp, sk.s2))
+	As1hat := util.MatrixVectorNTT(ahat, s1hat)
+	tmp := util.InvNttVec(As1hat)
+	t := util.AddVector(tmp, sk.s2)
+	t1, t0 := util.Power2RoundVec(t)
 
 	sk.t0 = ring.FromSymmetricVec(t0)
 	sk.t1 = ring.FromSymmetricVec(t1)
 
-	h := sha3.NewShake256()
+	// t0 and everything derived from s1 and s2 are secret
+	clear(s1hat)
+	clear(As1hat)
+	clear(tmp)
+	clear(t)
+	clear(t0)
+
+	h := sha3.NewSHAKE256()
 	h.Write(sk.Public().Bytes())
 	_, err := h.Read(sk.tr[:])
 	if err != nil {
@@ -1,11 +1,14 @@
 package internal
 
 import (
+	"crypto/rand"
 	"encoding/hex"
+	"reflect"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/trailofbits/ml-dsa/internal/params"
+	"github.com/trailofbits/ml-dsa/internal/ring"
 )
 
 func TestSkDecodeEncode(t *testing.T) {
@@ -48,3 +51,75 @@ func TestKeyGen(t *testing.T) {
 	assert.Equal(t, expect_pk, pk.Bytes())
 	assert.Equal(t, expect_sk, sk.EncodeExpanded())
 }
+
+func TestDestroy(t *testing.T) {
+	for _, p := range ps {
+		t.Run(p.Name, func(t *testing.T) {
+			sk, pk, err := GenerateKeyPair(p, rand.Reader)
+			assert.NoError(t, err)
+			sk.Precompute()
+			seed, s1, s2, t0, pre := sk.seed, sk.s1, sk.s2, sk.t0, sk.precomputed
+			assert.NotZero(t, s1[0])
+
+			sk.Destroy()
+			assert.Nil(t, sk.seed)
+			assert.Zero(t, sk.K)
+			assert.Nil(t, sk.precomputed)
+			assert.Equal(t, make([]byte, 32), seed)
+			for _, v := range [][]ring.Rq{s1, s2, t0} {
+				for i := range v {
+					assert.Zero(t, v[i])
+				}
+			}
+			for _, v := range [][]ring.Tq{pre.s1hat, pre.s2hat, pre.t0hat} {
+				for i := range v {
+					assert.Zero(t, v[i])
+				}
+			}
+
+			// The key is unusable, except for its public part
+			_, err = sk.Sign(rand.Reader, []byte("Hello, world!"), nil)
+			assert.Error(t, err)
+			_, err = sk.SignTo(nil, rand.Reader, []byte("Hello, world!"), nil)
+			assert.Error(t, err)
+			_, err = sk.SignMu(rand.Reader, make([]byte, 64))
+			assert.Error(t, err)
+			assert.Nil(t, sk.SignInternal([]byte("Hello, world!"), make([]byte, 32)))
+			_, err = sk.Bytes()
+			assert.Error(t, err)
+			assert.Nil(t, sk.EncodeExpanded())
+			sk.Precompute()
+			assert.Nil(t, sk.precomputed)
+			assert.Equal(t, pk, sk.Public())
+		})
+	}
+}
+
+// Every field of the signing scratch space but the public matrix is cleared
+// before it is returned to the pool.
+func TestSignScratchClear(t *testing.T) {
+	for _, p := range ps {
+		t.Run(p.Name, func(t *testing.T) {
+			sk, _, err := GenerateKeyPair(p, rand.Reader)
+			assert.NoError(t, err)
+			sc := new(signScratch)
+			assert.NoError(t, readRandomness(rand.Reader, &sc.rnd))
+			sig := sk.signMu(nil, sc, make([]byte, 64), sc.rnd[:])
+			assert.Len(t, sig, int(p.SigSize))
+			assert.True(t, sk.recheck(sc, make([]byte, 64), sc.rnd[:]))
+
+			v := reflect.ValueOf(sc).Elem()
+			for i := range v.NumField() {
+				assert.False(t, v.Field(i).IsZero(), v.Type().Field(i).Name)
+			}
+			sc.clear()
+			for i := range v.NumField() {
+				name := v.Type().Field(i).Name
+				if name == "Ahat" || name == "AhatRows" {
+					continue
+				}
+				assert.True(t, v.Field(i).IsZero(), name)
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package internal
+
+import (
+	"crypto"
+	"crypto/sha3"
+	"errors"
+
+	options "github.com/trailofbits/ml-dsa/options"
+)
+
+// Last bytes of the DER encodings of the object identifiers of the pre-hash
+// functions allowed for HashML-DSA (FIPS 204, Section 5.4). These are all of the
+// form 2.16.840.1.101.3.4.2.x, so only the last byte differs.
+var preHashOIDs = map[crypto.Hash]byte{
+	crypto.SHA256:     0x01,
+	crypto.SHA384:     0x02,
+	crypto.SHA512:     0x03,
+	crypto.SHA224:     0x04,
+	crypto.SHA512_224: 0x05,
+	crypto.SHA512_256: 0x06,
+	crypto.SHA3_224:   0x07,
+	crypto.SHA3_256:   0x08,
+	crypto.SHA3_384:   0x09,
+	crypto.SHA3_512:   0x0a,
+}
+
+var shakeOIDs = map[options.SHAKE]byte{
+	options.SHAKE128: 0x0b,
+	options.SHAKE256: 0x0c,
+}
+
+// Returns the DER-encoded OID of the pre-hash function selected by h or x, of
+// which at most one may be non-zero, and the expected digest size.
+func preHashOID(h crypto.Hash, x options.SHAKE) ([11]byte, int, error) {
+	var last byte
+	var size int
+	var ok bool
+	switch {
+	case h != 0 && x != 0:
+		return [11]byte{}, 0, errors.New("both a hash and a SHAKE pre-hash function are selected")
+	case x != 0:
+		last, ok = shakeOIDs[x]
+		size = 32 * int(x)
+	default:
+		last, ok = preHashOIDs[h]
+		if ok {
+			size = h.Size()
+		}
+	}
+	if !ok {
+		return [11]byte{}, 0, errors.New("unsupported pre-hash function")
+	}
+	return [11]byte{0x06, 0x09, 0x60, 0x86, 0x48, 0x01, 0x65, 0x03, 0x04, 0x02, last}, size, nil
+}
+
+// Writes the message representative M' from Algorithms 2-5 to s.
+//
+// If h and x are zero, this is the pure ML-DSA encoding:
+// M' <- IntegerToBytes(0, 1) || IntegerToBytes(|ctx|, 1) || ctx || M
+//
+// Otherwise, msg must be the digest PH(M) and this is the HashML-DSA encoding:
+// M' <- IntegerToBytes(1, 1) || IntegerToBytes(|ctx|, 1) || ctx || OID || PH(M)
+func writeMessage(s *sha3.SHAKE, h crypto.Hash, x options.SHAKE, ctx string, msg []byte) error {
+	if len(ctx) > 255 {
+		return errors.New("context must be less than 256 bytes long")
+	}
+
+	// Everything but M or PH(M) is collected in prefix
+	var prefix [2 + 255 + 11]byte
+	prefix[1] = byte(len(ctx))
+	n := 2 + copy(prefix[2:], ctx)
+
+	if h != 0 || x != 0 {
+		oid, size, err := preHashOID(h, x)
+		if err != nil {
+			return err
+		}
+		if len(msg) != size {
+			return errors.New("digest length does not match the pre-hash function")
+		}
+		prefix[0] = 1
+		n += copy(prefix[n:], oid[:])
+	}
+
+	s.Write(prefix[:n]) //nolint:errcheck
+	s.Write(msg)        //nolint:errcheck
+	return nil
+}
@@ -0,0 +1,8 @@
+//go:build race
+
+package internal
+
+func init() {
+	// sync.Pool randomly drops items under the race detector
+	raceEnabled = true
+}
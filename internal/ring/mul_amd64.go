@@ -0,0 +1,22 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build amd64 && !purego
+
+package ring
+
+import "golang.org/x/sys/cpu"
+
+var useAVX2 = cpu.X86.HasAVX2
+
+//go:noescape
+func mulAVX2(s, a, b *Tq)
+
+func mul(s, a, b *Tq) {
+	if useAVX2 {
+		mulAVX2(s, a, b)
+	} else {
+		mulGeneric(s, a, b)
+	}
+}
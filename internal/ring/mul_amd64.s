@@ -0,0 +1,53 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build amd64 && !purego
+
+#include "textflag.h"
+
+DATA q<>+0(SB)/4, $8380417
+GLOBL q<>(SB), RODATA|NOPTR, $4
+
+DATA qNegInv<>+0(SB)/4, $4236238847
+GLOBL qNegInv<>(SB), RODATA|NOPTR, $4
+
+// func mulAVX2(s, a, b *Tq)
+TEXT ·mulAVX2(SB), NOSPLIT, $0-24
+	MOVQ s+0(FP), DI
+	MOVQ a+8(FP), SI
+	MOVQ b+16(FP), DX
+	VPBROADCASTD q<>(SB), Y15
+	VPBROADCASTD qNegInv<>(SB), Y14
+	MOVQ $32, CX
+
+loop:
+	// Montgomery multiplication of the even and odd lanes, as in field.T.Mul
+	VMOVDQU  (SI), Y0
+	VMOVDQU  (DX), Y1
+	VPMULUDQ Y1, Y0, Y2
+	VPSRLQ   $32, Y0, Y0
+	VPSRLQ   $32, Y1, Y1
+	VPMULUDQ Y1, Y0, Y3
+	VPMULUDQ Y14, Y2, Y4
+	VPMULUDQ Y15, Y4, Y4
+	VPADDQ   Y4, Y2, Y2
+	VPMULUDQ Y14, Y3, Y4
+	VPMULUDQ Y15, Y4, Y4
+	VPADDQ   Y4, Y3, Y3
+	VPSRLQ   $32, Y2, Y2
+	VPBLENDD $0xaa, Y3, Y2, Y2
+
+	// Reduce from [0, 2q) to [0, q)
+	VPSUBD  Y15, Y2, Y4
+	VPMINUD Y4, Y2, Y2
+	VMOVDQU Y2, (DI)
+
+	ADDQ $32, SI
+	ADDQ $32, DX
+	ADDQ $32, DI
+	DECQ CX
+	JNZ  loop
+
+	VZEROUPPER
+	RET
@@ -0,0 +1,62 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build amd64 && !purego
+
+package ring
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/ml-dsa/internal/field"
+	"github.com/trailofbits/ml-dsa/internal/params"
+)
+
+func randomTq() (a Tq) {
+	for j := range a {
+		a[j] = field.NewFromReduced(rand.Uint32N(params.Q))
+	}
+	return a
+}
+
+func TestMulAVX2(t *testing.T) {
+	if !useAVX2 {
+		t.Skip("AVX2 is not supported")
+	}
+	var zero, top Tq
+	for j := range top {
+		top[j] = field.NewFromReduced(params.Q - 1)
+	}
+	inputs := []Tq{zero, top}
+	for range 1000 {
+		inputs = append(inputs, randomTq())
+	}
+	for i := range inputs {
+		a, b := inputs[i], inputs[(i+1)%len(inputs)]
+		var expected, got Tq
+		mulGeneric(&expected, &a, &b)
+		mulAVX2(&got, &a, &b)
+		assert.Equal(t, expected, got)
+	}
+}
+
+func BenchmarkMulAVX2(b *testing.B) {
+	if !useAVX2 {
+		b.Skip("AVX2 is not supported")
+	}
+	x, y := randomTq(), randomTq()
+	var s Tq
+	b.Run("generic", func(b *testing.B) {
+		for b.Loop() {
+			mulGeneric(&s, &x, &y)
+		}
+	})
+	b.Run("avx2", func(b *testing.B) {
+		for b.Loop() {
+			mulAVX2(&s, &x, &y)
+		}
+	})
+}
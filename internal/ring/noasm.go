@@ -0,0 +1,11 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !amd64 || purego
+
+package ring
+
+func mul(s, a, b *Tq) {
+	mulGeneric(s, a, b)
+}
@@ -173,8 +173,12 @@ func (a Tq) Sub(b Tq) Tq {
 
 func (a Tq) Mul(b Tq) Tq {
 	var s Tq
+	mul(&s, &a, &b)
+	return s
+}
+
+func mulGeneric(s, a, b *Tq) {
 	for i := range s {
 		s[i] = a[i].Mul(b[i])
 	}
-	return s
 }
@@ -0,0 +1,123 @@
+package internal
+
+import (
+	"sync"
+
+	"github.com/trailofbits/ml-dsa/internal/ring"
+)
+
+// Maximum dimensions over all parameter sets, which size the scratch space below.
+const (
+	maxK      = 8
+	maxL      = 7
+	maxW1Size = maxK * 32 * 6 // W1Encode packs coefficients in at most 6 bits
+)
+
+// signScratch is the working memory of signMu. It is far too large for the
+// stack, so it is taken from signScratchPool rather than allocated for each
+// signature. It holds secret values, so it must be returned with putSignScratch.
+type signScratch struct {
+	rnd       [32]byte
+	rhopp     [64]byte
+	cTilde    [64]byte
+	w1Encoded [maxW1Size]byte
+
+	// The key-dependent values of signMu, when the key is not precomputed
+	pre      precomputedSigningKey
+	s1hat    [maxL]ring.Tq
+	s2hat    [maxK]ring.Tq
+	t0hat    [maxK]ring.Tq
+	Ahat     [maxK][maxL]ring.Tq
+	AhatRows [maxK][]ring.Tq
+
+	yz   [maxL]ring.Rz
+	y    [maxL]ring.Rq
+	yhat [maxL]ring.Tq
+	z    [maxL]ring.Rq
+	what [maxK]ring.Tq
+	w    [maxK]ring.Rq
+	w1   [maxK]ring.Rz
+	r    [maxK]ring.Rq // w - cs2, then w - cs2 + ct0
+	ct0  [maxK]ring.Rq
+	nct0 [maxK]ring.Rq // -ct0
+	h    [maxK]ring.R2
+
+	// The iterations of the rejection sampling loop, and the values recomputed
+	// by recheck
+	tries  uint16
+	rhopp2 [64]byte
+	yz2    [maxL]ring.Rz
+	r2     [maxK]ring.Rq
+	nct02  [maxK]ring.Rq
+	h2     [maxK]ring.R2
+}
+
+var signScratchPool = sync.Pool{New: func() any { return new(signScratch) }}
+
+func getSignScratch() *signScratch {
+	return signScratchPool.Get().(*signScratch)
+}
+
+// putSignScratch clears sc and returns it to signScratchPool.
+func putSignScratch(sc *signScratch) {
+	sc.clear()
+	signScratchPool.Put(sc)
+}
+
+// clear overwrites the secret-dependent values in sc with zeros, which is
+// everything but the public matrix A.
+func (sc *signScratch) clear() {
+	clear(sc.rnd[:])
+	clear(sc.rhopp[:])
+	clear(sc.cTilde[:])
+	clear(sc.w1Encoded[:])
+	sc.pre = precomputedSigningKey{}
+	clear(sc.s1hat[:])
+	clear(sc.s2hat[:])
+	clear(sc.t0hat[:])
+	clear(sc.yz[:])
+	clear(sc.y[:])
+	clear(sc.yhat[:])
+	clear(sc.z[:])
+	clear(sc.what[:])
+	clear(sc.w[:])
+	clear(sc.w1[:])
+	clear(sc.r[:])
+	clear(sc.ct0[:])
+	clear(sc.nct0[:])
+	clear(sc.h[:])
+	sc.tries = 0
+	clear(sc.rhopp2[:])
+	clear(sc.yz2[:])
+	clear(sc.r2[:])
+	clear(sc.nct02[:])
+	clear(sc.h2[:])
+}
+
+// verifyScratch is the working memory of verifyMu, taken from verifyScratchPool.
+type verifyScratch struct {
+	cTilde    [64]byte
+	w1Encoded [maxW1Size]byte
+
+	// The key-dependent values of verifyMu, when the key is not precomputed
+	Ahat     [maxK][maxL]ring.Tq
+	AhatRows [maxK][]ring.Tq
+	t1hat    [maxK]ring.Tq
+
+	z    [maxL]ring.Rz
+	zhat [maxL]ring.Tq
+	what [maxK]ring.Tq
+	w    [maxK]ring.Rq
+	w1   [maxK]ring.Rz
+	h    [maxK]ring.R2
+}
+
+var verifyScratchPool = sync.Pool{New: func() any { return new(verifyScratch) }}
+
+// matrix returns a cfg.K x cfg.L view of Ahat, using rows for the row slices.
+func matrix(Ahat *[maxK][maxL]ring.Tq, rows *[maxK][]ring.Tq, k, l uint8) [][]ring.Tq {
+	for r := range k {
+		rows[r] = Ahat[r][:l]
+	}
+	return rows[:k]
+}
@@ -3,6 +3,7 @@ package internal
 import (
 	"crypto"
 	"crypto/rand"
+	"crypto/sha3"
 	"crypto/subtle"
 	"errors"
 	"io"
@@ -22,45 +23,79 @@ import (
 // Additional randomness is passed as:
 // rnd
 //
-// We do not currently support the use fo an "external mu"
-//
-// Returns a signature as a []byte
+// Returns a signature as a []byte, or nil if sk was destroyed
 func (sk *SigningKey) SignInternal(Mprime, rnd []byte) []byte {
-	cfg := sk.cfg
-	s1hat := util.NttVec(sk.s1) // TODO - consider caching s1hat, s2hat, t0hat, Ahat
-	s2hat := util.NttVec(sk.s2)
-	t0hat := util.NttVec(sk.t0)
-	Ahat := util.ExpandA(sk.cfg, sk.rho[:])
+	if sk.destroyed {
+		return nil
+	}
 
 	// mu <- H(BytesToBits(tr) || M', 64)
-	mu := make([]byte, 64)
-	util.H(mu, append(sk.tr[:], Mprime...))
+	var mu [64]byte
+	computeMu(&mu, sk.tr[:], Mprime)
+
+	sc := getSignScratch()
+	defer putSignScratch(sc)
+	return sk.signMu(make([]byte, 0, sk.cfg.SigSize), sc, mu[:], rnd)
+}
+
+// Algorithm 7, starting from line 7, with the message representative mu
+// computed externally. The signature is appended to dst.
+func (sk *SigningKey) signMu(dst []byte, sc *signScratch, mu, rnd []byte) []byte {
+	cfg := sk.cfg
+	k, l := cfg.K, cfg.L
+	pre := sk.precomputed
+	if pre == nil {
+		pre = sk.precomputeTo(sc)
+	}
+	s1hat, s2hat, t0hat, Ahat := pre.s1hat, pre.s2hat, pre.t0hat, pre.Ahat
 
 	// rhopp <- H(K || rnd || mu, 64)
-	rhopp := make([]byte, 64)
-	tmp := append(sk.K[:], rnd...)
-	tmp = append(tmp, mu...)
-	util.H(rhopp, tmp)
+	h := sha3.NewSHAKE256()
+	h.Write(sk.K[:])    //nolint:errcheck
+	h.Write(rnd)        //nolint:errcheck
+	h.Write(mu)         //nolint:errcheck
+	h.Read(sc.rhopp[:]) //nolint:errcheck
+	h.Reset()
+
+	y, yhat, z := sc.y[:l], sc.yhat[:l], sc.z[:l]
+	w, w1, r := sc.w[:k], sc.w1[:k], sc.r[:k]
+	ct0, nct0, hints := sc.ct0[:k], sc.nct0[:k], sc.h[:k]
+	c_tilde := sc.cTilde[:cfg.Lambda>>2]
 
 	// Rejection sampling loop
 	// We do not use loop bounds:
 	// "Implementations *should* not bound the number of iterations in these loops..." (FIPS 204, Appendix C)
+	sc.tries = 0
 	for kappa := uint16(0); ; kappa += uint16(cfg.L) {
-		y := ring.FromSymmetricVec(util.ExpandMask(cfg, rhopp, kappa))
-		w := util.InvNttVec(util.MatrixVectorNTT(Ahat, util.NttVec(y)))
-		w1 := ring.HighBitsVec(w, cfg.Gamma2) // TODO - more consistent API for cfg
-		w1_encoded := util.W1Encode(cfg, w1)
-		c_tilde := make([]byte, cfg.Lambda>>2)
-		util.H(c_tilde, append(mu, w1_encoded...))
+		sc.tries++
+		util.ExpandMaskTo(cfg, sc.yz[:l], sc.rhopp[:], kappa)
+		for i := range y {
+			y[i] = ring.FromSymmetric(sc.yz[i])
+			yhat[i] = util.NTT(y[i])
+		}
+		util.MatrixVectorNTTTo(sc.what[:k], Ahat, yhat)
+		for i := range w {
+			w[i] = util.InverseNTT(sc.what[i])
+			w1[i] = w[i].HighBits(cfg.Gamma2)
+		}
+		w1_encoded := util.AppendW1Encode(sc.w1Encoded[:0], cfg, w1)
+		h := sha3.NewSHAKE256()
+		h.Write(mu)         //nolint:errcheck
+		h.Write(w1_encoded) //nolint:errcheck
+		h.Read(c_tilde)     //nolint:errcheck
 		c := util.SampleInBall(cfg, c_tilde)
 		c_hat := util.NTT(ring.FromSymmetric(c))
 
-		cs1 := util.InvNttVec(util.ScalarVectorNTT(c_hat, s1hat))
-		cs2 := util.InvNttVec(util.ScalarVectorNTT(c_hat, s2hat))
-		z := util.AddVector(y, cs1)
-		r0 := ring.LowBitsVec(util.SubVector(w, cs2), cfg.Gamma2)
-		z_inf := ring.InfinityNormVec(z)
-		r0_inf := ring.InfinityNormVec(ring.FromSymmetricVec(r0))
+		// z <- y + <<cs1>>, r <- w - <<cs2>>
+		var z_inf, r0_inf uint32
+		for i := range z {
+			z[i] = y[i].Add(util.InverseNTT(c_hat.Mul(s1hat[i])))
+			z_inf = max(z_inf, z[i].InfinityNorm())
+		}
+		for i := range r {
+			r[i] = w[i].Sub(util.InverseNTT(c_hat.Mul(s2hat[i])))
+			r0_inf = max(r0_inf, ring.FromSymmetric(r[i].LowBits(cfg.Gamma2)).InfinityNorm())
+		}
 
 		// Rejection sampling
 		gamma1_beta := (1 << cfg.LogGamma1) - uint32(cfg.Beta)
@@ -71,66 +106,188 @@ func (sk *SigningKey) SignInternal(Mprime, rnd []byte) []byte {
 		}
 
 		// <<ct0>> <- NTT^-1(c_hat o t0_hat)
-		ct0 := util.InvNttVec(util.ScalarVectorNTT(c_hat, t0hat))
-		minus_ct0 := util.NegateVector(ct0)
-		// w - cs2
-		// w_cs2 := RingVectorSub(k, w, cs2)
-		// w - cs2 + ct0
-		// w_cs2_ct0 := RingVectorAdd(k, w_cs2, ct0)
-		w_cs2_ct0 := util.AddVector(util.SubVector(w, cs2), ct0)
-		ct0_inf := ring.InfinityNormVec(ct0)
-		// Returns `nil` if hint hamming weight is too large
-		h := util.MakeHint(cfg, minus_ct0, w_cs2_ct0)
-		if ct0_inf >= cfg.Gamma2 || h == nil {
+		var ct0_inf uint32
+		for i := range ct0 {
+			ct0[i] = util.InverseNTT(c_hat.Mul(t0hat[i]))
+			ct0_inf = max(ct0_inf, ct0[i].InfinityNorm())
+			nct0[i] = ct0[i].Neg()
+			// w - cs2 + ct0
+			r[i] = r[i].Add(ct0[i])
+		}
+		// Returns false if hint hamming weight is too large
+		ok := util.MakeHintTo(cfg, hints, nct0, r)
+		if ct0_inf >= cfg.Gamma2 || !ok {
 			continue
 		}
 
-		return util.SigEncode(cfg, c_tilde, z, h)
+		return util.AppendSigEncode(dst, cfg, c_tilde, z, hints)
 	}
 }
 
 // Sign takes a message and a context and returns a signature.
-// Only pure ML-DSA is supported.
+// If opts.HashFunc() is zero and opts is not an *options.Options selecting a SHAKE
+// pre-hash function, pure ML-DSA is used (Algorithm 2). Otherwise, HashML-DSA is used
+// (Algorithm 4), and message must be the digest of the actual message under that function.
 // Context must be less than 256 bytes long, or else this function will return an error.
+//
+// If opts is an *options.Options with a FaultProtection level, the signature is checked
+// before it is returned, and options.ErrFaultDetected is returned if the check fails.
 func (sk *SigningKey) Sign(rng io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return sk.SignTo(make([]byte, 0, sk.cfg.SigSize), rng, message, opts)
+}
+
+// SignTo is like Sign, but appends the signature to dst and returns the extended
+// slice. Signing does not allocate memory if dst has room for the signature.
+func (sk *SigningKey) SignTo(dst []byte, rng io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	if sk.destroyed {
+		return nil, errDestroyed
+	}
+
 	var h crypto.Hash
-	ctx := []byte{}
+	var x options.SHAKE
+	var ctx string
+	var fp options.FaultProtection
 
 	if opts != nil {
 		h = opts.HashFunc()
 		ops, ok := opts.(*options.Options)
-		if ok {
-			ctx = []byte(ops.Context)
+		if ok && ops != nil {
+			x = ops.SHAKE
+			ctx = ops.Context
+			fp = ops.FaultProtection
 		}
 	}
 
-	if h != 0 {
-		return nil, errors.New("opts.HashFunc() must be zero for pure ML-DSA")
+	// mu <- H(BytesToBits(tr) || M', 64)
+	var mu [64]byte
+	s := sha3.NewSHAKE256()
+	s.Write(sk.tr[:]) //nolint:errcheck
+	if err := writeMessage(s, h, x, ctx, message); err != nil {
+		return nil, err
+	}
+	s.Read(mu[:]) //nolint:errcheck
+
+	sc := getSignScratch()
+	defer putSignScratch(sc)
+	if err := readRandomness(rng, &sc.rnd); err != nil {
+		return nil, err
+	}
+
+	sig := sk.signMu(dst, sc, mu[:], sc.rnd[:])
+	if fp == options.FaultProtectionNone {
+		return sig, nil
+	}
+
+	ok := true
+	if fp >= options.FaultProtectionRedundant {
+		ok = sk.recheck(sc, mu[:], sc.rnd[:])
+	}
+	// Verify with the cached t1, independently of the secret values
+	vk := VerifyingKey{cfg: sk.cfg, rho: sk.rho, t1: sk.t1}
+	if !ok || !vk.verifyMu(mu[:], sig[len(dst):]) {
+		clear(sig[len(dst):])
+		return nil, options.ErrFaultDetected
+	}
+	return sig, nil
+}
+
+// recheck recomputes the signature that signMu left in sc for mu and rnd, from
+// the unprecomputed secret key, and checks it against the signed values and the
+// rejection bounds again. It returns false if a fault was detected.
+//
+// rho” and y are derived again from K, rnd and mu, and the products with c use
+// fresh NTTs of s1, s2 and t0 rather than the precomputed ones, so faults in the
+// cached secrets are detected rather than reproduced. The commitment w is not
+// recomputed: the verification that follows recheck in SignTo derives w1 again
+// from the public key, z and the hint.
+func (sk *SigningKey) recheck(sc *signScratch, mu, rnd []byte) bool {
+	cfg := sk.cfg
+	k, l := cfg.K, cfg.L
+
+	// rhopp <- H(K || rnd || mu, 64)
+	h := sha3.NewSHAKE256()
+	h.Write(sk.K[:])     //nolint:errcheck
+	h.Write(rnd)         //nolint:errcheck
+	h.Write(mu)          //nolint:errcheck
+	h.Read(sc.rhopp2[:]) //nolint:errcheck
+	ok := sc.tries > 0 && sc.rhopp2 == sc.rhopp
+
+	kappa := (sc.tries - 1) * uint16(l)
+	util.ExpandMaskTo(cfg, sc.yz2[:l], sc.rhopp2[:], kappa)
+
+	c := util.SampleInBall(cfg, sc.cTilde[:cfg.Lambda>>2])
+	c_hat := util.NTT(ring.FromSymmetric(c))
+
+	var z_inf, r0_inf, ct0_inf uint32
+	for i := range l {
+		y := ring.FromSymmetric(sc.yz2[i])
+		ok = ok && y == sc.y[i]
+		z := y.Add(util.InverseNTT(c_hat.Mul(util.NTT(sk.s1[i]))))
+		ok = ok && z == sc.z[i]
+		z_inf = max(z_inf, z.InfinityNorm())
+	}
+	r, nct0, hints := sc.r2[:k], sc.nct02[:k], sc.h2[:k]
+	for i := range r {
+		r[i] = sc.w[i].Sub(util.InverseNTT(c_hat.Mul(util.NTT(sk.s2[i]))))
+		r0_inf = max(r0_inf, ring.FromSymmetric(r[i].LowBits(cfg.Gamma2)).InfinityNorm())
+		ct0 := util.InverseNTT(c_hat.Mul(util.NTT(sk.t0[i])))
+		ct0_inf = max(ct0_inf, ct0.InfinityNorm())
+		nct0[i] = ct0.Neg()
+		r[i] = r[i].Add(ct0)
+	}
+	ok = util.MakeHintTo(cfg, hints, nct0, r) && ok
+	for i := range hints {
+		ok = ok && hints[i] == sc.h[i]
+	}
+
+	gamma1_beta := (1 << cfg.LogGamma1) - uint32(cfg.Beta)
+	gamma2_beta := cfg.Gamma2 - uint32(cfg.Beta)
+	return ok && z_inf < gamma1_beta && r0_inf < gamma2_beta && ct0_inf < cfg.Gamma2
+}
+
+// SignMu signs a 64-byte message representative mu that was computed externally,
+// for instance with VerifyingKey.ComputeMu.
+// This corresponds to the "external mu" variant of ML-DSA.Sign_internal.
+// It takes no options, so no fault protection is applied.
+func (sk *SigningKey) SignMu(rng io.Reader, mu []byte) ([]byte, error) {
+	if sk.destroyed {
+		return nil, errDestroyed
+	}
+	if len(mu) != 64 {
+		return nil, errors.New("mu must be 64 bytes long")
 	}
 
-	if len(ctx) > 255 {
-		return nil, errors.New("context must be less than 256 bytes long")
+	sc := getSignScratch()
+	defer putSignScratch(sc)
+	if err := readRandomness(rng, &sc.rnd); err != nil {
+		return nil, err
 	}
 
-	rnd := make([]byte, 32)
+	return sk.signMu(make([]byte, 0, sk.cfg.SigSize), sc, mu, sc.rnd[:]), nil
+}
+
+// Reads the 32 bytes of additional randomness rnd used for hedged signing.
+// If rng is nil, crypto/rand is used.
+func readRandomness(rng io.Reader, rnd *[32]byte) error {
 	if rng == nil {
 		rng = rand.Reader
 	}
-	n, err := rng.Read(rnd)
+	n, err := rng.Read(rnd[:])
 	if err != nil {
-		return nil, err
+		return err
 	}
 	if n != len(rnd) {
-		return nil, errors.New("rng.Read() returned too few bytes")
+		return errors.New("rng.Read() returned too few bytes")
 	}
+	return nil
+}
 
-	Mprime := make([]byte, 0, len(ctx)+len(message)+2)
-	Mprime = append(Mprime, byte(0), byte(len(ctx)))
-	Mprime = append(Mprime, ctx...)
-	Mprime = append(Mprime, message...)
-
-	sigma := sk.SignInternal(Mprime, rnd)
-	return sigma, nil
+// mu <- H(BytesToBits(tr) || M', 64)
+func computeMu(mu *[64]byte, tr, Mprime []byte) {
+	s := sha3.NewSHAKE256()
+	s.Write(tr)     //nolint:errcheck
+	s.Write(Mprime) //nolint:errcheck
+	s.Read(mu[:])   //nolint:errcheck
 }
 
 // Algorithm 8
@@ -144,65 +301,137 @@ func (sk *SigningKey) Sign(rng io.Reader, message []byte, opts crypto.SignerOpts
 // Returns true if the signature is valid.
 // Returns false otherwise (even if an error occurs).
 func (vk *VerifyingKey) VerifyInternal(Mprime, sigma []byte) bool {
+	var tr, mu [64]byte
+	vk.computeTrTo(&tr)
+	computeMu(&mu, tr[:], Mprime)
+	return vk.verifyMu(mu[:], sigma)
+}
+
+// Algorithm 8, starting from line 6, with the message representative mu
+// computed externally.
+func (vk *VerifyingKey) verifyMu(mu, sigma []byte) bool {
 	cfg := vk.cfg
-	c_tilde, z, h, err := util.SigDecode(cfg, sigma)
+	k, l := cfg.K, cfg.L
+	sc := verifyScratchPool.Get().(*verifyScratch)
+	defer verifyScratchPool.Put(sc)
+
+	z, h := sc.z[:l], sc.h[:k]
+	c_tilde, err := util.SigDecodeTo(cfg, sigma, z, h)
 	if err != nil {
 		return false
 	}
 
-	Ahat := util.ExpandA(cfg, vk.rho[:])
-	tr := make([]byte, 64)
-	util.H(tr, vk.Bytes())
-	mu := make([]byte, 64)
-	util.H(mu, append(tr, Mprime...))
+	var Ahat [][]ring.Tq
+	var t1_2d_hat []ring.Tq
+	if pre := vk.precomputed; pre != nil {
+		Ahat, t1_2d_hat = pre.Ahat, pre.t1_2d_hat
+	} else {
+		Ahat = matrix(&sc.Ahat, &sc.AhatRows, k, l)
+		util.ExpandATo(cfg, Ahat, vk.rho[:])
+		t1_2d_hat = sc.t1hat[:k]
+		vk.computeT1HatTo(t1_2d_hat)
+	}
 
 	c := util.SampleInBall(cfg, c_tilde)
 
-	z_hat := util.NttVec(ring.FromSymmetricVec(z))
+	z_hat := sc.zhat[:l]
+	for i := range z_hat {
+		z_hat[i] = util.NTT(ring.FromSymmetric(z[i]))
+	}
 	c_hat := util.NTT(ring.FromSymmetric(c))
 
-	t1_2d := util.ScalarVector(field.NewFromReduced(1<<params.D), vk.t1)
-
-	t1_2d_hat := util.NttVec(t1_2d)
-	ct1_2d_hat := util.ScalarVectorNTT(c_hat, t1_2d_hat)
-	Azhat := util.MatrixVectorNTT(Ahat, z_hat)
 	// w_approx := InvNttVec(k, Azhat - ct1_2d_hat)
-	w_approx := util.InvNttVec(util.SubVectorNTT(Azhat, ct1_2d_hat))
+	Azhat := sc.what[:k]
+	util.MatrixVectorNTTTo(Azhat, Ahat, z_hat)
+	w_approx := sc.w[:k]
+	for i := range w_approx {
+		w_approx[i] = util.InverseNTT(Azhat[i].Sub(c_hat.Mul(t1_2d_hat[i])))
+	}
 
-	w1 := util.UseHint(cfg, h, w_approx)
-	w1_encoded := util.W1Encode(cfg, w1)
-	c_tilde_prime := make([]byte, cfg.Lambda>>2)
-	util.H(c_tilde_prime, append(mu, w1_encoded...))
+	w1 := sc.w1[:k]
+	util.UseHintTo(cfg, w1, h, w_approx)
+	w1_encoded := util.AppendW1Encode(sc.w1Encoded[:0], cfg, w1)
+	c_tilde_prime := sc.cTilde[:cfg.Lambda>>2]
+	s := sha3.NewSHAKE256()
+	s.Write(mu)           //nolint:errcheck
+	s.Write(w1_encoded)   //nolint:errcheck
+	s.Read(c_tilde_prime) //nolint:errcheck
 
-	// Seems better to just do this directly on the Rz vec..
-	z_inf := ring.InfinityNormVec(ring.FromSymmetricVec(z))
+	var z_inf uint32
+	for i := range z {
+		z_inf = max(z_inf, ring.FromSymmetric(z[i]).InfinityNorm())
+	}
 
+	// return [[ ||z||_inf < gamma1 - beta ]] and [[ c_tilde = c_tilde' ]]
 	bound := (1 << cfg.LogGamma1) - uint32(cfg.Beta)
-	return z_inf <= bound && subtle.ConstantTimeCompare(c_tilde, c_tilde_prime) == 1
+	return z_inf < bound && subtle.ConstantTimeCompare(c_tilde, c_tilde_prime) == 1
+}
+
+// NTT(t1 * 2^d)
+func (vk *VerifyingKey) computeT1Hat() []ring.Tq {
+	t1_2d_hat := make([]ring.Tq, len(vk.t1))
+	vk.computeT1HatTo(t1_2d_hat)
+	return t1_2d_hat
+}
+
+func (vk *VerifyingKey) computeT1HatTo(t1_2d_hat []ring.Tq) {
+	for i := range t1_2d_hat {
+		t1_2d_hat[i] = util.NTT(vk.t1[i].ScalarMul(field.NewFromReduced(1 << params.D)))
+	}
 }
 
 // Verify verifies a signature.
 //
-// Only pure ML-DSA is supported. opts.HashFunc() must return 0.
+// If opts.Hash and opts.SHAKE are zero, pure ML-DSA is used (Algorithm 3).
+// Otherwise, HashML-DSA is used (Algorithm 5), and msg must be the digest of the
+// actual message under the selected pre-hash function.
 //
-// opts may be nil, in which case empty context is used.
+// opts may be nil, in which case pure ML-DSA with an empty context is used.
+// Verify does not allocate memory.
 func (vk *VerifyingKey) Verify(msg, sig []byte, opts *options.Options) bool {
-	ctx := []byte{}
-	if opts != nil {
-		if opts.HashFunc() != 0 {
-			return false
-		}
-		ctx = []byte(opts.Context)
+	var mu [64]byte
+	if err := vk.computeMuTo(&mu, msg, opts); err != nil {
+		return false
 	}
+	return vk.verifyMu(mu[:], sig)
+}
 
-	if len(ctx) > 255 {
+// VerifyMu verifies a signature over a 64-byte message representative mu that was
+// computed externally, for instance with ComputeMu.
+func (vk *VerifyingKey) VerifyMu(mu, sig []byte) bool {
+	if len(mu) != 64 {
 		return false
 	}
+	return vk.verifyMu(mu, sig)
+}
+
+// ComputeMu computes the 64-byte message representative mu for msg, as used
+// by SignMu and VerifyMu. opts are interpreted as in Verify.
+func (vk *VerifyingKey) ComputeMu(msg []byte, opts *options.Options) ([]byte, error) {
+	mu := new([64]byte)
+	if err := vk.computeMuTo(mu, msg, opts); err != nil {
+		return nil, err
+	}
+	return mu[:], nil
+}
 
-	Mprime := make([]byte, 0, len(ctx)+len(msg)+2)
-	Mprime = append(Mprime, byte(0), byte(len(ctx)))
-	Mprime = append(Mprime, ctx...)
-	Mprime = append(Mprime, msg...)
+func (vk *VerifyingKey) computeMuTo(mu *[64]byte, msg []byte, opts *options.Options) error {
+	var h crypto.Hash
+	var x options.SHAKE
+	var ctx string
+	if opts != nil {
+		h = opts.HashFunc()
+		x = opts.SHAKE
+		ctx = opts.Context
+	}
 
-	return vk.VerifyInternal(Mprime, sig)
+	var tr [64]byte
+	vk.computeTrTo(&tr)
+	s := sha3.NewSHAKE256()
+	s.Write(tr[:]) //nolint:errcheck
+	if err := writeMessage(s, h, x, ctx, msg); err != nil {
+		return err
+	}
+	s.Read(mu[:]) //nolint:errcheck
+	return nil
 }
@@ -1,13 +1,17 @@
 package internal
 
 import (
+	"bytes"
+	"crypto"
 	"crypto/rand"
 	"encoding/hex"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/trailofbits/ml-dsa/internal/params"
 	"github.com/trailofbits/ml-dsa/internal/util"
+	options "github.com/trailofbits/ml-dsa/options"
 )
 
 var ps = []*params.Cfg{params.MLDSA44Cfg, params.MLDSA65Cfg, params.MLDSA87Cfg}
@@ -107,3 +111,529 @@ func TestSigDecodeRejectsWrongLength(t *testing.T) {
 		})
 	}
 }
+
+func TestSignVerifyPreHash(t *testing.T) {
+	message, _ := hex.DecodeString("48656c6c6f20776f726c64")
+	for _, p := range ps {
+		t.Run(p.Name, func(t *testing.T) {
+			sk, pk, _ := GenerateKeyPair(p, rand.Reader)
+			var preHashes []options.Options
+			for h := range preHashOIDs {
+				preHashes = append(preHashes, options.Options{Hash: h})
+			}
+			for x := range shakeOIDs {
+				preHashes = append(preHashes, options.Options{SHAKE: x})
+			}
+			for _, ph := range preHashes {
+				_, size, err := preHashOID(ph.Hash, ph.SHAKE)
+				assert.NoError(t, err)
+				digest := make([]byte, size)
+				util.H(digest, message)
+
+				opts := &options.Options{Hash: ph.Hash, SHAKE: ph.SHAKE, Context: "context"}
+				sig, err := sk.Sign(rand.Reader, digest, opts)
+				assert.NoError(t, err)
+				assert.True(t, pk.Verify(digest, sig, opts))
+
+				// HashML-DSA and ML-DSA signatures are domain separated
+				assert.False(t, pk.Verify(digest, sig, &options.Options{Context: "context"}))
+				assert.False(t, pk.Verify(digest, sig, &ph))
+
+				// The digest length must match the hash function
+				_, err = sk.Sign(rand.Reader, digest[1:], opts)
+				assert.Error(t, err)
+				assert.False(t, pk.Verify(digest[1:], sig, opts))
+			}
+		})
+	}
+}
+
+func TestSignPreHashUnsupported(t *testing.T) {
+	sk, pk, _ := GenerateKeyPair(params.MLDSA44Cfg, rand.Reader)
+	digest := make([]byte, 16)
+	_, err := sk.Sign(rand.Reader, digest, crypto.MD5)
+	assert.Error(t, err)
+	assert.False(t, pk.Verify(digest, make([]byte, params.MLDSA44Cfg.SigSize), &options.Options{Hash: crypto.MD5}))
+
+	_, err = sk.Sign(rand.Reader, make([]byte, 32), &options.Options{Hash: crypto.SHA256, SHAKE: options.SHAKE128})
+	assert.Error(t, err)
+	_, err = sk.Sign(rand.Reader, make([]byte, 32), &options.Options{SHAKE: 3})
+	assert.Error(t, err)
+}
+
+func TestSignPreHashMessageFormat(t *testing.T) {
+	sk, pk, _ := GenerateKeyPair(params.MLDSA44Cfg, rand.Reader)
+	message := []byte("Hello world")
+
+	// DER-encoded OIDs of the pre-hash functions, from the NIST Computer Security
+	// Objects Register (2.16.840.1.101.3.4.2.x)
+	for _, tc := range []struct {
+		opts options.Options
+		oid  string
+	}{
+		{options.Options{Hash: crypto.SHA256}, "0609608648016503040201"},
+		{options.Options{Hash: crypto.SHA384}, "0609608648016503040202"},
+		{options.Options{Hash: crypto.SHA512}, "0609608648016503040203"},
+		{options.Options{Hash: crypto.SHA224}, "0609608648016503040204"},
+		{options.Options{Hash: crypto.SHA512_224}, "0609608648016503040205"},
+		{options.Options{Hash: crypto.SHA512_256}, "0609608648016503040206"},
+		{options.Options{Hash: crypto.SHA3_224}, "0609608648016503040207"},
+		{options.Options{Hash: crypto.SHA3_256}, "0609608648016503040208"},
+		{options.Options{Hash: crypto.SHA3_384}, "0609608648016503040209"},
+		{options.Options{Hash: crypto.SHA3_512}, "060960864801650304020a"},
+		{options.Options{SHAKE: options.SHAKE128}, "060960864801650304020b"},
+		{options.Options{SHAKE: options.SHAKE256}, "060960864801650304020c"},
+	} {
+		opts := tc.opts
+		opts.Context = "ctx"
+		digest, err := opts.PreHash(message)
+		assert.NoError(t, err)
+
+		// M' <- 1 || |ctx| || ctx || OID || PH(M)
+		oid, _ := hex.DecodeString(tc.oid)
+		Mprime := []byte{1, 3, 'c', 't', 'x'}
+		Mprime = append(Mprime, oid...)
+		Mprime = append(Mprime, digest...)
+
+		rnd := make([]byte, 32)
+		sig, err := sk.Sign(bytes.NewReader(rnd), digest, &opts)
+		assert.NoError(t, err)
+		assert.Equal(t, sk.SignInternal(Mprime, rnd), sig, "OID %s", tc.oid)
+		assert.True(t, pk.VerifyInternal(Mprime, sig), "OID %s", tc.oid)
+	}
+}
+
+func TestSignVerifyExternalMu(t *testing.T) {
+	message, _ := hex.DecodeString("48656c6c6f20776f726c64")
+	for _, p := range ps {
+		t.Run(p.Name, func(t *testing.T) {
+			sk, pk, _ := GenerateKeyPair(p, rand.Reader)
+			opts := &options.Options{Context: "context"}
+
+			mu, err := pk.ComputeMu(message, opts)
+			assert.NoError(t, err)
+			assert.Len(t, mu, 64)
+
+			// Signing mu externally is the same as signing the message
+			rnd := make([]byte, 32)
+			sig, err := sk.SignMu(bytes.NewReader(rnd), mu)
+			assert.NoError(t, err)
+			expected, err := sk.Sign(bytes.NewReader(rnd), message, opts)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, sig)
+
+			assert.True(t, pk.VerifyMu(mu, sig))
+			assert.True(t, pk.Verify(message, sig, opts))
+			assert.False(t, pk.VerifyMu(mu[1:], sig))
+			mu[0] ^= 1
+			assert.False(t, pk.VerifyMu(mu, sig))
+
+			_, err = sk.SignMu(rand.Reader, mu[1:])
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestStreamSignVerify(t *testing.T) {
+	message := bytes.Repeat([]byte("0123456789"), 1000)
+	for _, p := range ps {
+		t.Run(p.Name, func(t *testing.T) {
+			sk, pk, _ := GenerateKeyPair(p, rand.Reader)
+			ctx := []byte("context")
+
+			signer, err := sk.NewStreamSigner(ctx)
+			assert.NoError(t, err)
+			for chunk := range slices.Chunk(message, 333) {
+				_, err = signer.Write(chunk)
+				assert.NoError(t, err)
+			}
+
+			// Streaming signatures are identical to one-shot signatures
+			rnd := make([]byte, 32)
+			sig, err := signer.Sign(bytes.NewReader(rnd))
+			assert.NoError(t, err)
+			expected, err := sk.Sign(bytes.NewReader(rnd), message, &options.Options{Context: string(ctx)})
+			assert.NoError(t, err)
+			assert.Equal(t, expected, sig)
+
+			verifier, err := pk.NewStreamVerifier(ctx)
+			assert.NoError(t, err)
+			_, err = verifier.Write(message)
+			assert.NoError(t, err)
+			assert.True(t, verifier.Verify(sig))
+
+			_, err = verifier.Write([]byte{0})
+			assert.NoError(t, err)
+			assert.False(t, verifier.Verify(sig))
+
+			verifier.Reset()
+			_, err = verifier.Write(message)
+			assert.NoError(t, err)
+			assert.True(t, verifier.Verify(sig))
+
+			_, err = sk.NewStreamSigner(make([]byte, 256))
+			assert.Error(t, err)
+			_, err = pk.NewStreamVerifier(make([]byte, 256))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestVerifyRejectsMalformedSignature(t *testing.T) {
+	message, _ := hex.DecodeString("48656c6c6f20776f726c64")
+	for _, p := range ps {
+		t.Run(p.Name, func(t *testing.T) {
+			sk, pk, _ := GenerateKeyPair(p, rand.Reader)
+			sig, err := sk.Sign(rand.Reader, message, nil)
+			assert.NoError(t, err)
+			assert.True(t, pk.Verify(message, sig, nil))
+
+			cLen := int(p.Lambda / 4)
+			hintOffset := int(p.SigSize) - int(p.Omega) - int(p.K)
+			mutate := func(f func(sig []byte)) []byte {
+				bad := slices.Clone(sig)
+				f(bad)
+				return bad
+			}
+
+			// Wrong commitment hash c_tilde
+			bad := mutate(func(sig []byte) { sig[cLen-1] ^= 0x80 })
+			assert.False(t, pk.Verify(message, bad, nil))
+
+			// z coefficient out of range: a packed value of zero decodes to gamma1
+			bad = mutate(func(sig []byte) { sig[cLen], sig[cLen+1], sig[cLen+2] = 0, 0, 0 })
+			_, z, _, err := util.SigDecode(p, bad)
+			assert.NoError(t, err)
+			assert.Equal(t, int32(1<<p.LogGamma1), z[0][0])
+			assert.False(t, pk.Verify(message, bad, nil))
+
+			// Hint count larger than omega
+			bad = mutate(func(sig []byte) { sig[len(sig)-1] = p.Omega + 1 })
+			assert.False(t, pk.Verify(message, bad, nil))
+
+			// Decreasing hint counts
+			bad = mutate(func(sig []byte) {
+				sig[len(sig)-1] = 0
+				sig[len(sig)-2] = 1
+			})
+			assert.False(t, pk.Verify(message, bad, nil))
+
+			// Hint indices that are not strictly increasing
+			bad = mutate(func(sig []byte) {
+				for i := range p.K {
+					sig[hintOffset+int(p.Omega)+int(i)] = 2
+				}
+				sig[hintOffset], sig[hintOffset+1] = 5, 5
+			})
+			assert.False(t, pk.Verify(message, bad, nil))
+
+			// Nonzero padding after the last hint index
+			bad = mutate(func(sig []byte) {
+				for i := range p.K {
+					sig[hintOffset+int(p.Omega)+int(i)] = 0
+				}
+				sig[hintOffset] = 1
+			})
+			assert.False(t, pk.Verify(message, bad, nil))
+		})
+	}
+}
+
+func TestSignPrecomputed(t *testing.T) {
+	for _, p := range ps {
+		t.Run(p.Name, func(t *testing.T) {
+			sk, pk, err := GenerateKeyPair(p, rand.Reader)
+			assert.NoError(t, err)
+			message := []byte("Hello, world!")
+			rnd := make([]byte, 32)
+
+			expected := sk.SignInternal(message, rnd)
+			sk.Precompute()
+			assert.NotNil(t, sk.precomputed)
+			assert.Equal(t, expected, sk.SignInternal(message, rnd))
+			assert.True(t, pk.VerifyInternal(message, sk.SignInternal(message, rnd)))
+
+			// Precomputing twice keeps the cache
+			cache := sk.precomputed
+			sk.Precompute()
+			assert.Same(t, cache, sk.precomputed)
+		})
+	}
+}
+
+func BenchmarkSign(b *testing.B) {
+	message := []byte("Hello, world!")
+	for _, p := range ps {
+		for _, precomputed := range []bool{false, true} {
+			name := p.Name
+			if precomputed {
+				name += "/precomputed"
+			}
+			b.Run(name, func(b *testing.B) {
+				sk, _, err := GenerateKeyPair(p, rand.Reader)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if precomputed {
+					sk.Precompute()
+				}
+				b.ReportAllocs()
+				b.ResetTimer()
+				for range b.N {
+					if _, err := sk.Sign(rand.Reader, message, nil); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestVerifyPrecomputed(t *testing.T) {
+	for _, p := range ps {
+		t.Run(p.Name, func(t *testing.T) {
+			sk, pk, err := GenerateKeyPair(p, rand.Reader)
+			assert.NoError(t, err)
+			message := []byte("Hello, world!")
+			opts := &options.Options{Context: "context"}
+			sig, err := sk.Sign(rand.Reader, message, opts)
+			assert.NoError(t, err)
+
+			mu, err := pk.ComputeMu(message, opts)
+			assert.NoError(t, err)
+			pk.Precompute()
+			assert.Equal(t, sk.tr[:], pk.computeTr())
+			assert.True(t, pk.Verify(message, sig, opts))
+			assert.False(t, pk.Verify(message, sig, nil))
+			assert.True(t, pk.VerifyMu(mu, sig))
+			computed, err := pk.ComputeMu(message, opts)
+			assert.NoError(t, err)
+			assert.Equal(t, mu, computed)
+
+			sig[0] ^= 1
+			assert.False(t, pk.Verify(message, sig, opts))
+		})
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	message := []byte("Hello, world!")
+	for _, p := range ps {
+		for _, precomputed := range []bool{false, true} {
+			name := p.Name
+			if precomputed {
+				name += "/precomputed"
+			}
+			b.Run(name, func(b *testing.B) {
+				sk, pk, err := GenerateKeyPair(p, rand.Reader)
+				if err != nil {
+					b.Fatal(err)
+				}
+				sig, err := sk.Sign(rand.Reader, message, nil)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if precomputed {
+					pk.Precompute()
+				}
+				b.ReportAllocs()
+				b.ResetTimer()
+				for range b.N {
+					if !pk.Verify(message, sig, nil) {
+						b.Fatal("verification failed")
+					}
+				}
+			})
+		}
+	}
+}
+
+// zeroReader is an allocation-free source of deterministic signing randomness.
+type zeroReader struct{}
+
+func (zeroReader) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+func TestSignTo(t *testing.T) {
+	message := []byte("Hello, world!")
+	opts := &options.Options{Context: "ctx"}
+	for _, p := range ps {
+		t.Run(p.Name, func(t *testing.T) {
+			sk, pk, err := GenerateKeyPair(p, rand.Reader)
+			assert.NoError(t, err)
+			expected, err := sk.Sign(zeroReader{}, message, opts)
+			assert.NoError(t, err)
+
+			// The signature is appended to dst
+			prefix := []byte("prefix")
+			sig, err := sk.SignTo(slices.Clone(prefix), zeroReader{}, message, opts)
+			assert.NoError(t, err)
+			assert.Equal(t, prefix, sig[:len(prefix)])
+			assert.Equal(t, expected, sig[len(prefix):])
+			assert.True(t, pk.Verify(message, sig[len(prefix):], opts))
+
+			_, err = sk.SignTo(nil, zeroReader{}, message, &options.Options{Context: string(make([]byte, 256))})
+			assert.Error(t, err)
+		})
+	}
+}
+
+// Set in race_test.go
+var raceEnabled bool
+
+func TestSignVerifyAllocs(t *testing.T) {
+	if testing.CoverMode() != "" {
+		t.Skip("coverage instrumentation allocates")
+	}
+	if raceEnabled {
+		t.Skip("the race detector makes pooled scratch space allocate")
+	}
+	message := []byte("Hello, world!")
+	opts := &options.Options{Context: "ctx"}
+	for _, p := range ps {
+		for _, precomputed := range []bool{false, true} {
+			name := p.Name
+			if precomputed {
+				name += "/precomputed"
+			}
+			t.Run(name, func(t *testing.T) {
+				sk, pk, err := GenerateKeyPair(p, rand.Reader)
+				assert.NoError(t, err)
+				if precomputed {
+					sk.Precompute()
+					pk.Precompute()
+				}
+				sig := make([]byte, 0, p.SigSize)
+
+				allocs := testing.AllocsPerRun(10, func() {
+					sig, err = sk.SignTo(sig[:0], zeroReader{}, message, opts)
+				})
+				assert.NoError(t, err)
+				assert.Zero(t, allocs, "SignTo")
+
+				hardened := &options.Options{Context: "ctx", FaultProtection: options.FaultProtectionRedundant}
+				allocs = testing.AllocsPerRun(10, func() {
+					sig, err = sk.SignTo(sig[:0], zeroReader{}, message, hardened)
+				})
+				assert.NoError(t, err)
+				assert.Zero(t, allocs, "SignTo with fault protection")
+
+				var ok bool
+				allocs = testing.AllocsPerRun(10, func() {
+					ok = pk.Verify(message, sig, opts)
+				})
+				assert.True(t, ok)
+				assert.Zero(t, allocs, "Verify")
+
+				verifier, err := pk.NewStreamVerifier([]byte("ctx"))
+				assert.NoError(t, err)
+				verifier.Write(message) //nolint:errcheck
+				verifier.Verify(sig)
+				allocs = testing.AllocsPerRun(10, func() {
+					ok = verifier.Verify(sig)
+				})
+				assert.True(t, ok)
+				assert.Zero(t, allocs, "StreamVerifier.Verify")
+			})
+		}
+	}
+}
+
+func TestSignFaultProtection(t *testing.T) {
+	message := []byte("Hello, world!")
+	levels := []options.FaultProtection{options.FaultProtectionVerify, options.FaultProtectionRedundant}
+	for _, p := range ps {
+		t.Run(p.Name, func(t *testing.T) {
+			sk, pk, err := GenerateKeyPair(p, rand.Reader)
+			assert.NoError(t, err)
+			expected, err := sk.Sign(zeroReader{}, message, &options.Options{Context: "ctx"})
+			assert.NoError(t, err)
+			for _, fp := range levels {
+				sig, err := sk.Sign(zeroReader{}, message, &options.Options{Context: "ctx", FaultProtection: fp})
+				assert.NoError(t, err)
+				assert.Equal(t, expected, sig)
+			}
+
+			// A persistent fault in the precomputed s1 produces invalid signatures
+			sk.Precompute()
+			sk.precomputed.s1hat[0] = sk.precomputed.s1hat[1]
+			sig, err := sk.Sign(rand.Reader, message, nil)
+			assert.NoError(t, err)
+			assert.False(t, pk.Verify(message, sig, nil))
+			for _, fp := range levels {
+				sig, err := sk.Sign(rand.Reader, message, &options.Options{FaultProtection: fp})
+				assert.ErrorIs(t, err, options.ErrFaultDetected)
+				assert.Nil(t, sig)
+			}
+		})
+	}
+}
+
+// recheck detects faults in the values that signMu computed.
+func TestSignRecheck(t *testing.T) {
+	faults := map[string]func(sc *signScratch){
+		"z":      func(sc *signScratch) { sc.z[0][0] = sc.z[0][1] },
+		"y":      func(sc *signScratch) { sc.y[0][0] = sc.y[0][1] },
+		"rhopp":  func(sc *signScratch) { sc.rhopp[0] ^= 1 },
+		"tries":  func(sc *signScratch) { sc.tries++ },
+		"hint":   func(sc *signScratch) { sc.h[0][0] ^= 1 },
+		"cTilde": func(sc *signScratch) { sc.cTilde[0] ^= 1 },
+	}
+	mu, rnd := make([]byte, 64), make([]byte, 32)
+	for _, p := range ps {
+		t.Run(p.Name, func(t *testing.T) {
+			sk, _, err := GenerateKeyPair(p, rand.Reader)
+			assert.NoError(t, err)
+			for name, fault := range faults {
+				sc := new(signScratch)
+				sk.signMu(nil, sc, mu, rnd)
+				assert.True(t, sk.recheck(sc, mu, rnd))
+				fault(sc)
+				assert.False(t, sk.recheck(sc, mu, rnd), name)
+			}
+
+			// Faults in the precomputed s1 and t0 change the signature, and are not
+			// reproduced. A fault in s2 only changes r, which is not signed.
+			for name, fault := range map[string]func(){
+				"s1": func() { sk.precomputed.s1hat[0] = sk.precomputed.s1hat[1] },
+				"t0": func() { sk.precomputed.t0hat[0] = sk.precomputed.t0hat[1] },
+			} {
+				sk.precomputed = sk.precompute()
+				fault()
+				sc := new(signScratch)
+				sk.signMu(nil, sc, mu, rnd)
+				assert.False(t, sk.recheck(sc, mu, rnd), name)
+			}
+		})
+	}
+}
+
+func BenchmarkSignFaultProtection(b *testing.B) {
+	message := []byte("Hello, world!")
+	levels := map[string]options.FaultProtection{
+		"none":      options.FaultProtectionNone,
+		"verify":    options.FaultProtectionVerify,
+		"redundant": options.FaultProtectionRedundant,
+	}
+	for _, p := range ps {
+		sk, _, err := GenerateKeyPair(p, rand.Reader)
+		if err != nil {
+			b.Fatal(err)
+		}
+		sk.Precompute()
+		for _, name := range []string{"none", "verify", "redundant"} {
+			opts := &options.Options{FaultProtection: levels[name]}
+			b.Run(p.Name+"/"+name, func(b *testing.B) {
+				sig := make([]byte, 0, p.SigSize)
+				for range b.N {
+					// The same randomness gives the same number of iterations
+					if sig, err = sk.SignTo(sig[:0], zeroReader{}, message, opts); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,101 @@
+package internal
+
+import (
+	"crypto/sha3"
+	"errors"
+	"io"
+)
+
+// muHash incrementally computes mu <- H(BytesToBits(tr) || M', 64) for pure ML-DSA,
+// where M' = IntegerToBytes(0, 1) || IntegerToBytes(|ctx|, 1) || ctx || M.
+// The message M is absorbed as it is written, so it never needs to be buffered.
+type muHash struct {
+	prefix []byte // tr || M' prefix, kept so that the state can be reset
+	h      *sha3.SHAKE
+
+	// Scratch space for mu, so that finalizing does not allocate.
+	clone   sha3.SHAKE
+	state   []byte
+	muBytes [64]byte
+}
+
+func newMuHash(tr, ctx []byte) (*muHash, error) {
+	if len(ctx) > 255 {
+		return nil, errors.New("context must be less than 256 bytes long")
+	}
+
+	prefix := make([]byte, 0, len(tr)+len(ctx)+2)
+	prefix = append(prefix, tr...)
+	prefix = append(prefix, byte(0), byte(len(ctx)))
+	prefix = append(prefix, ctx...)
+
+	m := &muHash{prefix: prefix, h: sha3.NewSHAKE256()}
+	m.Reset()
+	return m, nil
+}
+
+// Write absorbs more of the message. It never returns an error.
+func (m *muHash) Write(p []byte) (int, error) {
+	return m.h.Write(p)
+}
+
+// Reset discards the message written so far.
+func (m *muHash) Reset() {
+	m.h.Reset()
+	m.h.Write(m.prefix) //nolint:errcheck
+}
+
+// Returns mu for the message written so far, without modifying the state.
+// The result is stored in m and overwritten by the next call.
+func (m *muHash) mu() []byte {
+	// Squeezing finalizes the state, so it is read from a copy. Marshaling never fails.
+	m.state, _ = m.h.AppendBinary(m.state[:0])
+	m.clone.UnmarshalBinary(m.state) //nolint:errcheck
+	m.clone.Read(m.muBytes[:])       //nolint:errcheck
+	return m.muBytes[:]
+}
+
+// StreamSigner computes a pure ML-DSA signature over a message that is written to it
+// incrementally, for messages that are too large to hold in memory.
+type StreamSigner struct {
+	muHash
+	sk *SigningKey
+}
+
+// NewStreamSigner returns a StreamSigner for the given context string.
+// Context must be less than 256 bytes long, or else this function will return an error.
+func (sk *SigningKey) NewStreamSigner(ctx []byte) (*StreamSigner, error) {
+	m, err := newMuHash(sk.tr[:], ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &StreamSigner{muHash: *m, sk: sk}, nil
+}
+
+// Sign returns a signature over the message written so far.
+// If rng is nil, crypto/rand is used. Like SignMu, it applies no fault protection.
+func (s *StreamSigner) Sign(rng io.Reader) ([]byte, error) {
+	return s.sk.SignMu(rng, s.mu())
+}
+
+// StreamVerifier verifies a pure ML-DSA signature over a message that is written to it
+// incrementally, for messages that are too large to hold in memory.
+type StreamVerifier struct {
+	muHash
+	vk *VerifyingKey
+}
+
+// NewStreamVerifier returns a StreamVerifier for the given context string.
+// Context must be less than 256 bytes long, or else this function will return an error.
+func (vk *VerifyingKey) NewStreamVerifier(ctx []byte) (*StreamVerifier, error) {
+	m, err := newMuHash(vk.computeTr(), ctx)
+	if err != nil {
+		return nil, err
+	}
+	return &StreamVerifier{muHash: *m, vk: vk}, nil
+}
+
+// Verify reports whether sig is a valid signature over the message written so far.
+func (s *StreamVerifier) Verify(sig []byte) bool {
+	return s.vk.VerifyMu(s.mu(), sig)
+}
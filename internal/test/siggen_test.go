@@ -8,76 +8,101 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	internal "github.com/trailofbits/ml-dsa/internal"
 	"github.com/trailofbits/ml-dsa/internal/params"
 	options "github.com/trailofbits/ml-dsa/options"
 )
 
+// The interfaces that the sigGen vectors must exercise. There are no HashML-DSA
+// ("preHash") vectors yet, see README.md.
+var sigGenInterfaces = []string{"internal", "external/pure", "externalMu"}
+
 func TestSignatureGeneration(t *testing.T) {
-	testVectors, err := ParseTestVectorFile[*sigGenTestGroup]("siggen_test.json")
-	if err != nil {
-		t.Fatalf("failed to parse test vector file: %v", err)
-	}
+	exercised := make(map[string]bool)
+	for _, path := range []string{"siggen_test.json", "siggen_external_test.json"} {
+		testVectors, err := ParseTestVectorFile[*sigGenTestGroup](path)
+		if err != nil {
+			t.Fatalf("failed to parse test vector file %s: %v", path, err)
+		}
 
-	for _, testGroup := range testVectors.TestGroups {
-		name := fmt.Sprintf("TestGroup-%d", testGroup.id)
-		t.Run(name, func(t *testing.T) {
-			// Skip external mu test groups, we don't support the feature
-			// Skip prehash test groups, we don't support the feature
-			if testGroup.externalMu || testGroup.preHash == "preHash" {
-				t.Log("skipping test group with external mu or preHash")
-				return
-			}
+		for _, testGroup := range testVectors.TestGroups {
+			name := fmt.Sprintf("%s/TestGroup-%d", path, testGroup.id)
+			t.Run(name, func(t *testing.T) {
+				if len(testGroup.tests) == 0 {
+					t.Fatal("no test cases found")
+				}
+				iface := testGroup.signatureInterface
+				switch {
+				case testGroup.externalMu:
+					iface = "externalMu"
+				case iface == "external":
+					iface += "/" + testGroup.preHash
+				}
+				switch iface {
+				case "internal", "externalMu", "external/pure", "external/preHash":
+				default:
+					t.Fatalf("unsupported signature interface %q", iface)
+				}
+				exercised[iface] = true
 
-			if len(testGroup.tests) == 0 {
-				panic("no test cases found")
-			}
-			for _, test := range testGroup.tests {
-				var sig []byte
-				var err error
-				var rnd []byte
+				for _, test := range testGroup.tests {
+					var sig []byte
+					var rnd []byte
 
-				if testGroup.deterministic {
-					rnd = make([]byte, 32)
-				} else {
-					rnd = test.rnd
-				}
+					if testGroup.deterministic {
+						rnd = make([]byte, 32)
+					} else {
+						rnd = test.rnd
+					}
 
-				sk, err := internal.SkDecode(testGroup.parameterSet, test.sk)
-				assert.NoError(t, err, "failed to parse signing key in test case %d", test.id)
+					sk, err := internal.SkDecode(testGroup.parameterSet, test.sk)
+					require.NoError(t, err, "failed to parse signing key in test case %d", test.id)
 
-				if testGroup.signatureInterface == "internal" {
-					sig = sk.SignInternal(test.msg, rnd[:])
-				} else {
-					reader := bytes.NewReader(rnd[:])
-					sig, err = sk.Sign(reader, test.msg, &options.Options{Context: string(test.ctx)})
+					switch iface {
+					case "externalMu":
+						sig, err = sk.SignMu(bytes.NewReader(rnd), test.mu)
+					case "internal":
+						sig = sk.SignInternal(test.msg, rnd)
+					case "external/preHash":
+						opts, digest := preHashDigest(t, test.hashAlg, test.ctx, test.msg)
+						sig, err = sk.Sign(bytes.NewReader(rnd), digest, opts)
+					case "external/pure":
+						sig, err = sk.Sign(bytes.NewReader(rnd), test.msg, &options.Options{Context: string(test.ctx)})
+					}
 					assert.NoError(t, err, "failed to sign message in test case %d", test.id)
+					assert.Equal(t, test.sig, sig, "signatures differ in test case %d", test.id)
 				}
-				assert.NoError(t, err, "failed to sign message in test case %d", test.id)
-				assert.Equal(t, test.sig, sig, "signatures differ in test case %d", test.id)
-			}
-		})
+			})
+		}
+	}
+	for _, iface := range sigGenInterfaces {
+		assert.True(t, exercised[iface], "no sigGen test group for the %s interface", iface)
 	}
 }
 
 type sigGenTestCase struct {
-	id  int
-	sk  []byte
-	vk  []byte
-	msg []byte
-	rnd []byte
-	ctx []byte
-	sig []byte
+	id      int
+	sk      []byte
+	vk      []byte
+	msg     []byte
+	mu      []byte
+	rnd     []byte
+	ctx     []byte
+	hashAlg string
+	sig     []byte
 }
 
 type sigGenTestCaseMarshaller struct {
-	Id  int     `json:"tcId"`
-	SK  string  `json:"sk"`
-	VK  string  `json:"pk"`
-	Msg *string `json:"message"`
-	Ctx *string `json:"context"`
-	Rnd *string `json:"rnd"`
-	Sig string  `json:"signature"`
+	Id      int     `json:"tcId"`
+	SK      string  `json:"sk"`
+	VK      string  `json:"pk"`
+	Msg     *string `json:"message"`
+	Mu      *string `json:"mu"`
+	Ctx     *string `json:"context"`
+	Rnd     *string `json:"rnd"`
+	HashAlg string  `json:"hashAlg"`
+	Sig     string  `json:"signature"`
 }
 
 func (t *sigGenTestCase) UnmarshalJSON(data []byte) error {
@@ -91,12 +116,16 @@ func (t *sigGenTestCase) UnmarshalJSON(data []byte) error {
 	if tRaw.Msg != nil {
 		t.msg, _ = hex.DecodeString(*tRaw.Msg)
 	}
+	if tRaw.Mu != nil {
+		t.mu, _ = hex.DecodeString(*tRaw.Mu)
+	}
 	if tRaw.Rnd != nil {
 		t.rnd, _ = hex.DecodeString(*tRaw.Rnd)
 	}
 	if tRaw.Ctx != nil {
 		t.ctx, _ = hex.DecodeString(*tRaw.Ctx)
 	}
+	t.hashAlg = tRaw.HashAlg
 	t.sig, _ = hex.DecodeString(tRaw.Sig)
 	return nil
 }
@@ -129,7 +158,12 @@ func (tg *sigGenTestGroup) UnmarshalJSON(data []byte) error {
 	tg.id = tgRaw.TgId
 	tg.parameterSet = parseCfg(tgRaw.ParameterSet)
 	tg.deterministic = tgRaw.Deterministic
+	// Vector sets that predate the external interface do not specify one,
+	// and test ML-DSA.Sign_internal.
 	tg.signatureInterface = tgRaw.SignatureInterface
+	if tg.signatureInterface == "" {
+		tg.signatureInterface = "internal"
+	}
 	tg.preHash = tgRaw.PreHash
 	tg.externalMu = tgRaw.ExternalMu
 	tg.tests = tgRaw.Tests
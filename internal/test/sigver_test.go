@@ -0,0 +1,163 @@
+package mldsa_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	internal "github.com/trailofbits/ml-dsa/internal"
+	"github.com/trailofbits/ml-dsa/internal/params"
+	options "github.com/trailofbits/ml-dsa/options"
+)
+
+// The interfaces that the sigVer vectors must exercise. There are no HashML-DSA
+// ("preHash") vectors yet, see README.md.
+var sigVerInterfaces = []string{"internal", "external/pure", "externalMu"}
+
+func TestSignatureVerification(t *testing.T) {
+	exercised := make(map[string]bool)
+	for _, path := range []string{"sigver_test.json", "sigver_external_test.json"} {
+		testVectors, err := ParseTestVectorFile[*sigVerTestGroup](path)
+		if err != nil {
+			t.Fatalf("failed to parse test vector file %s: %v", path, err)
+		}
+
+		for _, testGroup := range testVectors.TestGroups {
+			name := fmt.Sprintf("%s/TestGroup-%d", path, testGroup.id)
+			t.Run(name, func(t *testing.T) {
+				if len(testGroup.tests) == 0 {
+					t.Fatal("no test cases found")
+				}
+				iface := testGroup.signatureInterface
+				switch {
+				case testGroup.externalMu:
+					iface = "externalMu"
+				case iface == "external":
+					iface += "/" + testGroup.preHash
+				}
+				switch iface {
+				case "internal", "externalMu", "external/pure", "external/preHash":
+				default:
+					t.Fatalf("unsupported signature interface %q", iface)
+				}
+				exercised[iface] = true
+
+				for _, test := range testGroup.tests {
+					// Older vector sets have a single public key per group
+					pkBytes := test.pk
+					if pkBytes == nil {
+						pkBytes = testGroup.pk
+					}
+					pk, err := internal.PkDecode(testGroup.parameterSet, pkBytes)
+					require.NoError(t, err, "failed to parse verifying key in test case %d", test.id)
+
+					var ok bool
+					switch iface {
+					case "externalMu":
+						ok = pk.VerifyMu(test.mu, test.sig)
+					case "internal":
+						ok = pk.VerifyInternal(test.msg, test.sig)
+					case "external/preHash":
+						opts, digest := preHashDigest(t, test.hashAlg, test.ctx, test.msg)
+						ok = pk.Verify(digest, test.sig, opts)
+					case "external/pure":
+						ok = pk.Verify(test.msg, test.sig, &options.Options{Context: string(test.ctx)})
+					}
+					assert.Equal(t, test.testPassed, ok, "unexpected verification result in test case %d", test.id)
+				}
+			})
+		}
+	}
+	for _, iface := range sigVerInterfaces {
+		assert.True(t, exercised[iface], "no sigVer test group for the %s interface", iface)
+	}
+}
+
+type sigVerTestCase struct {
+	id         int
+	pk         []byte
+	msg        []byte
+	mu         []byte
+	ctx        []byte
+	hashAlg    string
+	sig        []byte
+	testPassed bool
+}
+
+type sigVerTestCaseMarshaller struct {
+	Id         int     `json:"tcId"`
+	VK         *string `json:"pk"`
+	Msg        *string `json:"message"`
+	Mu         *string `json:"mu"`
+	Ctx        *string `json:"context"`
+	HashAlg    string  `json:"hashAlg"`
+	Sig        string  `json:"signature"`
+	TestPassed bool    `json:"testPassed"`
+}
+
+func (t *sigVerTestCase) UnmarshalJSON(data []byte) error {
+	var tRaw sigVerTestCaseMarshaller
+	if err := json.Unmarshal(data, &tRaw); err != nil {
+		return err
+	}
+	t.id = tRaw.Id
+	if tRaw.VK != nil {
+		t.pk, _ = hex.DecodeString(*tRaw.VK)
+	}
+	if tRaw.Msg != nil {
+		t.msg, _ = hex.DecodeString(*tRaw.Msg)
+	}
+	if tRaw.Mu != nil {
+		t.mu, _ = hex.DecodeString(*tRaw.Mu)
+	}
+	if tRaw.Ctx != nil {
+		t.ctx, _ = hex.DecodeString(*tRaw.Ctx)
+	}
+	t.hashAlg = tRaw.HashAlg
+	t.sig, _ = hex.DecodeString(tRaw.Sig)
+	t.testPassed = tRaw.TestPassed
+	return nil
+}
+
+type sigVerTestGroup struct {
+	id                 int
+	parameterSet       *params.Cfg
+	pk                 []byte
+	signatureInterface string
+	preHash            string
+	externalMu         bool
+	tests              []sigVerTestCase
+}
+
+type sigVerTestGroupUnmarshaler struct {
+	TgId               int              `json:"tgId"`
+	ParameterSet       string           `json:"parameterSet"`
+	VK                 string           `json:"pk"`
+	SignatureInterface string           `json:"signatureInterface"`
+	PreHash            string           `json:"preHash"`
+	ExternalMu         bool             `json:"externalMu"`
+	Tests              []sigVerTestCase `json:"tests"`
+}
+
+func (tg *sigVerTestGroup) UnmarshalJSON(data []byte) error {
+	tgRaw := new(sigVerTestGroupUnmarshaler)
+	if err := json.Unmarshal(data, &tgRaw); err != nil {
+		return err
+	}
+	tg.id = tgRaw.TgId
+	tg.parameterSet = parseCfg(tgRaw.ParameterSet)
+	tg.pk, _ = hex.DecodeString(tgRaw.VK)
+	// Vector sets that predate the external interface do not specify one,
+	// and test ML-DSA.Verify_internal.
+	tg.signatureInterface = tgRaw.SignatureInterface
+	if tg.signatureInterface == "" {
+		tg.signatureInterface = "internal"
+	}
+	tg.preHash = tgRaw.PreHash
+	tg.externalMu = tgRaw.ExternalMu
+	tg.tests = tgRaw.Tests
+	return nil
+}
@@ -1,11 +1,14 @@
 package mldsa_test
 
 import (
+	"crypto"
 	"encoding/json"
 	"fmt"
 	"os"
+	"testing"
 
 	"github.com/trailofbits/ml-dsa/internal/params"
+	options "github.com/trailofbits/ml-dsa/options"
 )
 
 func parseCfg(paramString string) *params.Cfg {
@@ -21,6 +24,37 @@ func parseCfg(paramString string) *params.Cfg {
 	}
 }
 
+// Pre-hash functions of the HashML-DSA test groups, by ACVP name.
+var preHashes = map[string]options.Options{
+	"SHA2-224":     {Hash: crypto.SHA224},
+	"SHA2-256":     {Hash: crypto.SHA256},
+	"SHA2-384":     {Hash: crypto.SHA384},
+	"SHA2-512":     {Hash: crypto.SHA512},
+	"SHA2-512/224": {Hash: crypto.SHA512_224},
+	"SHA2-512/256": {Hash: crypto.SHA512_256},
+	"SHA3-224":     {Hash: crypto.SHA3_224},
+	"SHA3-256":     {Hash: crypto.SHA3_256},
+	"SHA3-384":     {Hash: crypto.SHA3_384},
+	"SHA3-512":     {Hash: crypto.SHA3_512},
+	"SHAKE-128":    {SHAKE: options.SHAKE128},
+	"SHAKE-256":    {SHAKE: options.SHAKE256},
+}
+
+// Computes PH(M) for the ACVP hash algorithm name, as used by the HashML-DSA test groups,
+// and returns it with the options to sign or verify it under the context ctx.
+func preHashDigest(t *testing.T, hashAlg string, ctx, msg []byte) (*options.Options, []byte) {
+	opts, ok := preHashes[hashAlg]
+	if !ok {
+		t.Fatalf("unknown hash algorithm: %q", hashAlg)
+	}
+	opts.Context = string(ctx)
+	digest, err := opts.PreHash(msg)
+	if err != nil {
+		t.Fatalf("failed to hash message: %v", err)
+	}
+	return &opts, digest
+}
+
 // TestVectorFile represents the structure of the test vector file
 type TestVectorFile[TestGroup json.Unmarshaler] struct {
 	TestGroups []TestGroup `json:"testGroups"`
@@ -15,13 +15,13 @@
 package util
 
 import (
+	"crypto/sha3"
 	"crypto/subtle"
 	"errors"
 
 	"github.com/trailofbits/ml-dsa/internal/field"
 	"github.com/trailofbits/ml-dsa/internal/params"
 	"github.com/trailofbits/ml-dsa/internal/ring"
-	"golang.org/x/crypto/sha3"
 )
 
 // Algorithm 24
@@ -47,22 +47,36 @@ func SKEncode(k, l, log_eta uint8, rho, K, tr []byte, s1, s2, t0 []ring.Rz) []by
 
 // Algorithm 26
 func SigEncode(cfg *params.Cfg, c []byte, z []ring.Rq, h []ring.R2) []byte {
-	sigma := c[:]
+	return AppendSigEncode(make([]byte, 0, cfg.SigSize), cfg, c, z, h)
+}
+
+// AppendSigEncode appends SigEncode(cfg, c, z, h) to dst.
+func AppendSigEncode(dst []byte, cfg *params.Cfg, c []byte, z []ring.Rq, h []ring.R2) []byte {
+	dst = append(dst, c...)
 	for i := range cfg.L {
-		packed := BitPack(z[i].Symmetric(), cfg.LogGamma1)
-		sigma = append(sigma, packed...)
+		zi := z[i].Symmetric()
+		dst = appendBitPackOpen(dst, &zi, cfg.LogGamma1)
 	}
-	packed := HintBitPack(cfg.K, cfg.Omega, h)
-	sigma = append(sigma, packed...)
-	return sigma[:]
+	return appendHintBitPack(dst, cfg.Omega, h[:cfg.K])
 }
 
 // Algorithm 27
 func SigDecode(cfg *params.Cfg, sig []byte) ([]byte, []ring.Rz, []ring.R2, error) {
+	z := make([]ring.Rz, cfg.L)
+	h := make([]ring.R2, cfg.K)
+	c, err := SigDecodeTo(cfg, sig, z, h)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return c, z, h, nil
+}
+
+// SigDecodeTo is SigDecode, decoding z and h into the given vectors of length
+// cfg.L and cfg.K. The returned c is a subslice of sig.
+func SigDecodeTo(cfg *params.Cfg, sig []byte, z []ring.Rz, h []ring.R2) ([]byte, error) {
 	if len(sig) != int(cfg.SigSize) {
-		return nil, nil, nil, errors.New("invalid signature size")
+		return nil, errors.New("invalid signature size")
 	}
-	z := make([]ring.Rz, cfg.L)
 
 	length := cfg.Lambda / 4
 	c, sigma := sig[:length], sig[length:]
@@ -70,32 +84,34 @@ func SigDecode(cfg *params.Cfg, sig []byte) ([]byte, []ring.Rz, []ring.R2, error
 	elemLen := 32 * (1 + int(cfg.LogGamma1))
 	for i := range cfg.L {
 		x, sigma = sigma[:elemLen], sigma[elemLen:]
-		z[i] = BitUnpack(x, cfg.LogGamma1)
+		bitUnpackTo(&z[i], x, cfg.LogGamma1)
 	}
-	h, err := HintBitUnpack(cfg.K, cfg.Omega, sigma)
-	if err != nil {
-		return nil, nil, nil, err
+	if err := hintBitUnpackTo(h[:cfg.K], cfg.Omega, sigma); err != nil {
+		return nil, err
 	}
-	return c, z, h, nil
+	return c, nil
 }
 
 // Algorithm 28
 // This is just SimpleBitPack with a precomputed per-paramset length
 func W1Encode(cfg *params.Cfg, w1 []ring.Rz) []byte {
-	var w []byte
+	return AppendW1Encode(nil, cfg, w1)
+}
+
+// AppendW1Encode appends W1Encode(cfg, w1) to dst.
+func AppendW1Encode(dst []byte, cfg *params.Cfg, w1 []ring.Rz) []byte {
 	for i := range cfg.K {
-		packed := SimpleBitPack(w1[i], cfg.W1Bits)
-		w = append(w, packed...)
+		dst = appendSimpleBitPack(dst, &w1[i], cfg.W1Bits)
 	}
-	return w
+	return dst
 }
 
 // Algorithm 29
 func SampleInBall(cfg *params.Cfg, seed []byte) (c ring.Rz) {
 	var s [8]byte
-	ctx := sha3.NewShake256()
-	ctx.Write(seed)
-	ctx.Read(s[:]) //nolint:errcheck
+	ctx := sha3.NewSHAKE256()
+	ctx.Write(seed) //nolint:errcheck
+	ctx.Read(s[:])  //nolint:errcheck
 
 	var j [1]byte
 	// The NIST specification says "to 255" which means "< 256"
@@ -120,36 +136,47 @@ func SampleInBall(cfg *params.Cfg, seed []byte) (c ring.Rz) {
 
 // Algorithm 30
 func RejNTTPoly(seed []byte) (ah ring.Tq) {
-	ctx := sha3.NewShake128()
-	var s [3]byte
-	ctx.Write(seed)
-	for j := 0; j < 256; j++ {
-		var tmp *field.T
-		for tmp == nil {
-			ctx.Read(s[:]) //nolint:errcheck
-			tmp = field.FromThreeBytes(s[0], s[1], s[2])
-		}
-		ah[j] = *tmp
+	ctx := sha3.NewSHAKE128()
+	ctx.Write(seed) //nolint:errcheck
+	// Reading whole SHAKE128 blocks at a time gives the same stream of
+	// 3-byte candidates as reading them one by one.
+	var buf [5 * 168]byte
+	for j := 0; j < 256; {
+		ctx.Read(buf[:]) //nolint:errcheck
+		j = rejNTTPoly(&ah, j, buf[:])
 	}
 	return ah
 }
 
+// rejNTTPolyGeneric samples coefficients from the 3-byte candidates in buf into
+// a[j:], and returns the new j.
+func rejNTTPolyGeneric(a *ring.Tq, j int, buf []byte) int {
+	for ; j < 256 && len(buf) >= 3; buf = buf[3:] {
+		if c, ok := field.FromThreeBytes(buf[0], buf[1], buf[2]); ok {
+			a[j] = c
+			j++
+		}
+	}
+	return j
+}
+
 // Algorithm 31
 func RejBoundedPoly(eta int, seed []byte) (a ring.Rq) {
 	var z [1]byte
-	ctx := sha3.NewShake256()
-	ctx.Write(seed)
+	ctx := sha3.NewSHAKE256()
+	defer ctx.Reset() // the seed is secret
+	ctx.Write(seed)   //nolint:errcheck
 	for j := 0; j < 256; {
 		ctx.Read(z[:]) //nolint:errcheck
-		z0 := field.FromHalfByte(eta, z[0]&0xf)
-		if z0 != nil {
-			a[j] = *z0
+		z0, ok := field.FromHalfByte(eta, z[0]&0xf)
+		if ok {
+			a[j] = z0
 			j++
 		}
-		z1 := field.FromHalfByte(eta, z[0]>>4)
-		if z1 != nil {
+		z1, ok := field.FromHalfByte(eta, z[0]>>4)
+		if ok {
 			if j < 256 {
-				a[j] = *z1
+				a[j] = z1
 				j++
 			}
 		}
@@ -159,18 +186,27 @@ func RejBoundedPoly(eta int, seed []byte) (a ring.Rq) {
 
 // Algorithm 32
 func ExpandA(cfg *params.Cfg, rho []byte) [][]ring.Tq {
-	k, l := cfg.K, cfg.L
-	Ahat := make([][]ring.Tq, k)
-	for r := range k {
-		Ahat[r] = make([]ring.Tq, l)
-		for s := range l {
-			rhoprime := append(rho, byte(s), byte(r))
-			Ahat[r][s] = RejNTTPoly(rhoprime)
-		}
+	Ahat := make([][]ring.Tq, cfg.K)
+	for r := range Ahat {
+		Ahat[r] = make([]ring.Tq, cfg.L)
 	}
+	ExpandATo(cfg, Ahat, rho)
 	return Ahat
 }
 
+// ExpandATo is ExpandA, writing into a cfg.K x cfg.L matrix.
+func ExpandATo(cfg *params.Cfg, Ahat [][]ring.Tq, rho []byte) {
+	// rho' <- rho || IntegerToBytes(s, 1) || IntegerToBytes(r, 1)
+	var rhoprime [34]byte
+	copy(rhoprime[:], rho)
+	for r := range cfg.K {
+		for s := range cfg.L {
+			rhoprime[32], rhoprime[33] = byte(s), byte(r)
+			Ahat[r][s] = RejNTTPoly(rhoprime[:])
+		}
+	}
+}
+
 // Appends a uint16 as a []byte of length 2, in little-endian order
 // Modifies rho in place, if rho has sufficient capacity.
 func tweakUint16(rho []byte, x uint16) []byte {
@@ -185,6 +221,7 @@ func ExpandS(cfg *params.Cfg, rho []byte) ([]ring.Rq, []ring.Rq) {
 
 	// copy rho so that we can tweak in-place
 	packed := make([]byte, 0, len(rho)+2)
+	defer clear(packed[:cap(packed)])
 	rho = append(packed, rho...)
 
 	for r := range l {
@@ -198,30 +235,35 @@ func ExpandS(cfg *params.Cfg, rho []byte) ([]ring.Rq, []ring.Rq) {
 
 // H(str, l) -> SHAKE256(str, 8l)
 func H(out []byte, data []byte) {
-	ctx := sha3.NewShake256()
-	ctx.Write(data)
-	ctx.Read(out) //nolint:errcheck
+	ctx := sha3.NewSHAKE256()
+	ctx.Write(data) //nolint:errcheck
+	ctx.Read(out)   //nolint:errcheck
 }
 
 // Algorithm 34
 func ExpandMask(cfg *params.Cfg, rho []byte, mu uint16) []ring.Rz {
 	y := make([]ring.Rz, cfg.L)
-	c := uint32(1 + cfg.LogGamma1)
-
-	// copy rho so that we can tweak in-place
-	packed := make([]byte, 0, len(rho)+2)
-	rho = append(packed, rho...)
+	ExpandMaskTo(cfg, y, rho, mu)
+	return y
+}
 
-	v := make([]byte, c<<5)
+// ExpandMaskTo is ExpandMask, writing into a vector of length cfg.L.
+func ExpandMaskTo(cfg *params.Cfg, y []ring.Rz, rho []byte, mu uint16) {
+	c := uint32(1 + cfg.LogGamma1)
+	var v [32 * 20]byte // c is at most 20
 	for r := range cfg.L {
 		// rho' <- rho || IntegerToBytes(mu + r, 2)
-		packed := tweakUint16(rho, uint16(r)+mu)
+		x := uint16(r) + mu
+		ctx := sha3.NewSHAKE256()
+		ctx.Write(rho)                           //nolint:errcheck
+		ctx.Write([]byte{byte(x), byte(x >> 8)}) //nolint:errcheck
 		// v <- H(rho', 32c)
-		H(v, packed)
+		ctx.Read(v[:c<<5]) //nolint:errcheck
+		ctx.Reset()        // rho is secret
 		// y[r] = BitUnpack(v, gamma1 - 1, gamma1)
-		y[r] = BitUnpack(v, cfg.LogGamma1)
+		bitUnpackTo(&y[r], v[:c<<5], cfg.LogGamma1)
 	}
-	return y
+	clear(v[:])
 }
 
 func makeHint(cfg *params.Cfg, z, r field.T) uint8 {
@@ -235,6 +277,15 @@ func makeHint(cfg *params.Cfg, z, r field.T) uint8 {
 // Returns nil when the number of 1s in the hint is greater than omega
 func MakeHint(cfg *params.Cfg, z, r []ring.Rq) []ring.R2 {
 	hints := make([]ring.R2, cfg.K)
+	if !MakeHintTo(cfg, hints, z, r) {
+		return nil
+	}
+	return hints
+}
+
+// MakeHintTo is MakeHint, writing into a vector of length cfg.K.
+// It returns false when the number of 1s in the hint is greater than omega.
+func MakeHintTo(cfg *params.Cfg, hints []ring.R2, z, r []ring.Rq) bool {
 	weight := 0
 	for i := range cfg.K {
 		for j := range params.N {
@@ -242,17 +293,20 @@ func MakeHint(cfg *params.Cfg, z, r []ring.Rq) []ring.R2 {
 			weight += int(hints[i][j])
 		}
 	}
-	if weight > int(cfg.Omega) {
-		return nil
-	}
-	return hints
+	return weight <= int(cfg.Omega)
 }
 
 // Algorithm 40
 // Not constant time - inputs and outputs are public
 func UseHint(cfg *params.Cfg, h []ring.R2, r []ring.Rq) []ring.Rz {
-	m := int32((params.Q - 1) / (2 * cfg.Gamma2))
 	v := make([]ring.Rz, cfg.K)
+	UseHintTo(cfg, v, h, r)
+	return v
+}
+
+// UseHintTo is UseHint, writing into a vector of length cfg.K.
+func UseHintTo(cfg *params.Cfg, v []ring.Rz, h []ring.R2, r []ring.Rq) {
+	m := int32((params.Q - 1) / (2 * cfg.Gamma2))
 	for i := range cfg.K {
 		for j := range params.N {
 			r1, r0 := r[i][j].Decompose(cfg.Gamma2)
@@ -266,7 +320,6 @@ func UseHint(cfg *params.Cfg, h []ring.R2, r []ring.Rq) []ring.Rz {
 			}
 		}
 	}
-	return v
 }
 
 // Multiplies each element of a vector by a scalar
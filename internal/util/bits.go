@@ -19,12 +19,27 @@ func IntegerToBits[T ~uint32](x T, a int) []byte {
 	return y
 }
 
-// bitPack takes a slice of k-bit unsigned integers and packs them into a byte slice in lsb order.
-// If entries are signed, they are assumed to be nonnegative
-func bitPack[T int32 | uint32](w []T, k uint8) []byte {
+// sliceForAppend extends the input slice by n bytes. head is the full extended
+// slice, while tail is the appended part. If the original slice has sufficient
+// capacity no allocation is performed.
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		head = in[:total]
+	} else {
+		head = make([]byte, total)
+		copy(head, in)
+	}
+	tail = head[len(in):]
+	return
+}
+
+// appendBitPack takes k-bit unsigned integers, packs them in lsb order,
+// and appends the result to dst.
+func appendBitPack(dst []byte, w *[params.N]uint32, k uint8) []byte {
 	n := len(w)
 	numBytes := (n*int(k) + 7) / 8
-	z := make([]byte, numBytes)
+	dst, z := sliceForAppend(dst, numBytes)
+	clear(z)
 
 	// l is the number of unoccupied bits remaining in the current byte
 	l := uint8(8)
@@ -32,7 +47,7 @@ func bitPack[T int32 | uint32](w []T, k uint8) []byte {
 	m := 0
 
 	for i := 0; i < n; i++ {
-		v := uint32(w[i])
+		v := w[i]
 		j := k // number of bits left to store in the current value
 
 		for l <= j {
@@ -51,39 +66,56 @@ func bitPack[T int32 | uint32](w []T, k uint8) []byte {
 		z[m] |= byte(v << (8 - l))
 		l -= j
 	}
-	return z
+	return dst
 }
 
 // Algorithm 16
 // Assumes that all coefficients are in the range 0 <= x < 2^k
 func SimpleBitPack(w ring.Rz, k uint8) []byte {
-	return bitPack(w[:], k)
+	return appendSimpleBitPack(nil, &w, k)
+}
+
+// AppendSimpleBitPack appends SimpleBitPack(w, k) to dst.
+func AppendSimpleBitPack(dst []byte, w ring.Rz, k uint8) []byte {
+	return appendSimpleBitPack(dst, &w, k)
+}
+
+func appendSimpleBitPack(dst []byte, w *ring.Rz, k uint8) []byte {
+	var z [params.N]uint32
+	for i := range w {
+		z[i] = uint32(w[i])
+	}
+	return appendBitPack(dst, &z, k)
 }
 
 // Algorithm 17, specialized to values in the closed interval -2^k <= x <= 2^k
 // 2^k is called eta in the context of FIPS 204
 func BitPackClosed(w ring.Rz, k uint8) []byte {
-	z := make([]uint32, len(w))
+	var z [params.N]uint32
 	for i := range w {
 		z[i] = uint32((1 << k) - w[i])
 	}
-	return bitPack(z, k+2) // max value is 2^(k+1), which requires k+2 bits
+	return appendBitPack(nil, &z, k+2) // max value is 2^(k+1), which requires k+2 bits
 }
 
 // Algorithm 17, for open intervals -2^k < x <= 2^k
 func BitPack(w ring.Rz, k uint8) []byte {
-	z := make([]uint32, len(w))
+	return appendBitPackOpen(nil, &w, k)
+}
+
+// appendBitPackOpen appends BitPack(w, k) to dst.
+func appendBitPackOpen(dst []byte, w *ring.Rz, k uint8) []byte {
+	var z [params.N]uint32
 	for i := range w {
 		z[i] = uint32((1 << k) - w[i])
 	}
-	return bitPack(z, uint8(k+1)) // max value is 2^(k+1) - 1, which requires k+1 bits
+	return appendBitPack(dst, &z, uint8(k+1)) // max value is 2^(k+1) - 1, which requires k+1 bits
 }
 
-// bitUnpack takes a byte slice and unpacks it into a slice of k-bit unsigned integers.
+// bitUnpack takes a byte slice and unpacks it into w as k-bit unsigned integers.
 // The byte slice is assumed to be in lsb order.
-func bitUnpack(z []byte, k uint8) []int32 {
+func bitUnpack(w *ring.Rz, z []byte, k uint8) {
 	// Every use case packs or unpacks full ring elements
-	w := make([]int32, params.N)
 
 	// l is the number of bits available to be read from the current byte
 	l := uint8(8)
@@ -109,37 +141,43 @@ func bitUnpack(z []byte, k uint8) []int32 {
 		w[i] = int32(v)
 		l -= j
 	}
-	return w
 }
 
 // Algorithm 18
 func SimpleBitUnpack(b []byte, k uint8) (z ring.Rz) {
-	w := bitUnpack(b, k)
-	copy(z[:], w)
+	bitUnpack(&z, b, k)
 	return z
 }
 
 // Algorithm 19, for open intervals -2^k < x <= 2^k
 func BitUnpack(b []byte, k uint8) (z ring.Rz) {
-	w := bitUnpack(b, k+1)
-	for i := range w {
-		z[i] = (1 << k) - w[i]
-	}
+	bitUnpackTo(&z, b, k)
 	return z
 }
 
+// bitUnpackTo sets z to BitUnpack(b, k).
+func bitUnpackTo(z *ring.Rz, b []byte, k uint8) {
+	bitUnpack(z, b, k+1)
+	for i := range z {
+		z[i] = (1 << k) - z[i]
+	}
+}
+
 // Algorithm 19, specialized to values in -eta <= x <= eta
 // Returns an error if any value is out of range
 // 2^k is called eta in the context of FIPS 204
 // k is always either 1 or 2.
 // This is only used during sk decoding
 func BitUnpackClosed(b []byte, k uint8) (z ring.Rz, err error) {
-	w := bitUnpack(b, k+2)
+	if len(b) != 32*(int(k)+2) {
+		return z, errors.New("invalid input length")
+	}
+	bitUnpack(&z, b, k+2)
 	ok := 1
-	for i := range w {
+	for i := range z {
 		// Malformed values can fall in the range 2^(k+1) < x < 2^(k+2)
-		ok &= subtle.ConstantTimeLessOrEq(int(w[i]), int(2<<k))
-		z[i] = (1 << k) - w[i]
+		ok &= subtle.ConstantTimeLessOrEq(int(z[i]), int(2<<k))
+		z[i] = (1 << k) - z[i]
 	}
 
 	// ok to be non-constant-time here, since it won't leak non-negligible information about the secret key
@@ -153,7 +191,14 @@ func BitUnpackClosed(b []byte, k uint8) (z ring.Rz, err error) {
 // Does not need to be constant-time, as hints are public
 // This is used during signature encoding
 func HintBitPack(k, omega uint8, h []ring.R2) []byte {
-	y := make([]byte, k+omega)
+	return appendHintBitPack(nil, omega, h[:k])
+}
+
+// appendHintBitPack appends HintBitPack(len(h), omega, h) to dst.
+func appendHintBitPack(dst []byte, omega uint8, h []ring.R2) []byte {
+	k := uint8(len(h))
+	dst, y := sliceForAppend(dst, int(k+omega))
+	clear(y)
 	index := uint8(0)
 	for i := range k {
 		for j := range 256 {
@@ -164,23 +209,36 @@ func HintBitPack(k, omega uint8, h []ring.R2) []byte {
 		}
 		y[omega+i] = byte(index)
 	}
-	return y
+	return dst
 }
 
 // Algorithm 21
 // This is used by signature verification, which does not need to be constant-time
 func HintBitUnpack(k, omega uint8, y []byte) ([]ring.R2, error) {
+	if len(y) != int(k)+int(omega) {
+		return nil, errors.New("invalid input length")
+	}
 	h := make([]ring.R2, k)
+	if err := hintBitUnpackTo(h, omega, y); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// hintBitUnpackTo sets h to HintBitUnpack(len(h), omega, y).
+func hintBitUnpackTo(h []ring.R2, omega uint8, y []byte) error {
+	k := uint8(len(h))
+	clear(h)
 	index := byte(0)
 	for i := range k {
 		if y[omega+i] < index || y[omega+i] > omega {
-			return nil, errors.New("malformed input")
+			return errors.New("malformed input")
 		}
 		first := index
 		for index < y[omega+i] {
 			if index > first {
 				if y[index-1] >= y[index] {
-					return nil, errors.New("malformed input")
+					return errors.New("malformed input")
 				}
 			}
 			yidx := y[index]
@@ -190,8 +248,8 @@ func HintBitUnpack(k, omega uint8, y []byte) ([]ring.R2, error) {
 	}
 	for i := index; i < omega; i++ {
 		if y[i] != 0 {
-			return nil, errors.New("malformed input: trailing nonzero values")
+			return errors.New("malformed input: trailing nonzero values")
 		}
 	}
-	return h, nil
+	return nil
 }
@@ -0,0 +1,57 @@
+package util_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/ml-dsa/internal/params"
+	"github.com/trailofbits/ml-dsa/internal/ring"
+	"github.com/trailofbits/ml-dsa/internal/util"
+)
+
+// The seed corpora in testdata/fuzz hold valid encodings for each parameter set.
+
+var fuzzCfgs = []*params.Cfg{params.MLDSA44Cfg, params.MLDSA65Cfg, params.MLDSA87Cfg}
+
+// fuzzCfg selects a parameter set from a fuzzed byte.
+func fuzzCfg(p uint8) *params.Cfg {
+	return fuzzCfgs[int(p)%len(fuzzCfgs)]
+}
+
+func FuzzSigDecode(f *testing.F) {
+	f.Fuzz(func(t *testing.T, p uint8, sig []byte) {
+		cfg := fuzzCfg(p)
+		c, z, h, err := util.SigDecode(cfg, sig)
+		if err != nil {
+			return
+		}
+		// Signature encoding is canonical
+		assert.Equal(t, sig, util.SigEncode(cfg, c, ring.FromSymmetricVec(z), h))
+	})
+}
+
+func FuzzHintBitUnpack(f *testing.F) {
+	f.Fuzz(func(t *testing.T, p uint8, y []byte) {
+		cfg := fuzzCfg(p)
+		h, err := util.HintBitUnpack(cfg.K, cfg.Omega, y)
+		if err != nil {
+			return
+		}
+		assert.Equal(t, y, util.HintBitPack(cfg.K, cfg.Omega, h))
+	})
+}
+
+func FuzzBitUnpackClosed(f *testing.F) {
+	f.Fuzz(func(t *testing.T, p uint8, b []byte) {
+		k := fuzzCfg(p).LogEta
+		z, err := util.BitUnpackClosed(b, k)
+		if err != nil {
+			return
+		}
+		for _, x := range z {
+			assert.LessOrEqual(t, x, int32(1)<<k)
+			assert.GreaterOrEqual(t, x, -int32(1)<<k)
+		}
+		assert.Equal(t, b, util.BitPackClosed(z, k))
+	})
+}
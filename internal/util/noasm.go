@@ -0,0 +1,21 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build !amd64 || purego
+
+package util
+
+import "github.com/trailofbits/ml-dsa/internal/ring"
+
+func ntt(a *ring.Tq) {
+	nttGeneric(a)
+}
+
+func invNTT(a *ring.Tq) {
+	invNTTGeneric(a)
+}
+
+func rejNTTPoly(a *ring.Tq, j int, buf []byte) int {
+	return rejNTTPolyGeneric(a, j, buf)
+}
@@ -0,0 +1,174 @@
+package util_test
+
+import (
+	"math/bits"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/ml-dsa/internal/field"
+	"github.com/trailofbits/ml-dsa/internal/params"
+	"github.com/trailofbits/ml-dsa/internal/ring"
+	"github.com/trailofbits/ml-dsa/internal/util"
+)
+
+// The reference implementation below is the straightforward NTT of FIPS 204,
+// with a Barrett reduction after every operation. It is the previous
+// implementation of util.NTT, kept to check and benchmark the Montgomery one.
+
+var referenceZetas = func() (z [256]uint32) {
+	// zetas[m] = 1753^BitRev8(m) mod q
+	for m := range z {
+		z[m] = referencePow(1753, uint32(bits.Reverse8(uint8(m))))
+	}
+	return z
+}()
+
+func referencePow(x, e uint32) uint32 {
+	r := uint32(1)
+	for range e {
+		r = referenceMul(r, x)
+	}
+	return r
+}
+
+func referenceMul(a, b uint32) uint32 {
+	const mu uint64 = 2201172575745 // floor(2^64 / q)
+	x := uint64(a) * uint64(b)
+	hi, _ := bits.Mul64(x, mu)
+	r := x - hi*params.Q
+	for range 2 {
+		r1, borrow := bits.Sub64(r, params.Q, 0)
+		mask := uint64(0) - uint64(borrow^1)
+		r ^= mask & (r ^ r1)
+	}
+	return uint32(r)
+}
+
+func referenceReduceOnce(a uint32) uint32 {
+	x := a - params.Q
+	x += (x >> 31) * params.Q
+	return x
+}
+
+func referenceNTT(w [256]uint32) [256]uint32 {
+	m := 0
+	for len := 128; len >= 1; len >>= 1 {
+		for start := 0; start < 256; start += len << 1 {
+			m++
+			z := referenceZetas[m]
+			for j := start; j < start+len; j++ {
+				t := referenceMul(z, w[j+len])
+				w[j+len] = referenceReduceOnce(w[j] - t + params.Q)
+				w[j] = referenceReduceOnce(w[j] + t)
+			}
+		}
+	}
+	return w
+}
+
+func referenceInverseNTT(w [256]uint32) [256]uint32 {
+	m := 256
+	for len := 1; len < 256; len <<= 1 {
+		for start := 0; start < 256; start += len << 1 {
+			m--
+			z := params.Q - referenceZetas[m]
+			for j := start; j < start+len; j++ {
+				t := w[j]
+				w[j] = referenceReduceOnce(t + w[j+len])
+				w[j+len] = referenceMul(z, referenceReduceOnce(t-w[j+len]+params.Q))
+			}
+		}
+	}
+	for j := range w {
+		w[j] = referenceMul(w[j], 8347681) // 256⁻¹ mod q
+	}
+	return w
+}
+
+// nttInputs returns random polynomials, and the ones that maximize the lazily
+// reduced coefficients.
+func nttInputs() [][256]uint32 {
+	var zero, one, top [256]uint32
+	for j := range top {
+		one[j] = 1
+		top[j] = params.Q - 1
+	}
+	inputs := [][256]uint32{zero, one, top}
+	for range 100 {
+		var w [256]uint32
+		for j := range w {
+			w[j] = rand.Uint32N(params.Q)
+		}
+		inputs = append(inputs, w)
+	}
+	return inputs
+}
+
+func TestNTT(t *testing.T) {
+	for _, w := range nttInputs() {
+		var r ring.Rq
+		for j := range r {
+			r[j] = field.NewFromReduced(w[j])
+		}
+		var got [256]uint32
+		for j, c := range util.NTT(r) {
+			got[j] = c.Reduced()
+		}
+		assert.Equal(t, referenceNTT(w), got)
+	}
+}
+
+func TestInverseNTT(t *testing.T) {
+	for _, w := range nttInputs() {
+		var r ring.Tq
+		for j := range r {
+			r[j] = field.NewFromReduced(w[j])
+		}
+		inv := util.InverseNTT(r)
+		var got [256]uint32
+		for j, c := range inv {
+			got[j] = c.Reduced()
+		}
+		assert.Equal(t, referenceInverseNTT(w), got)
+		assert.Equal(t, r, util.NTT(inv))
+	}
+}
+
+func BenchmarkNTT(b *testing.B) {
+	var w [256]uint32
+	var r ring.Rq
+	for j := range w {
+		w[j] = rand.Uint32N(params.Q)
+		r[j] = field.NewFromReduced(w[j])
+	}
+	b.Run("montgomery", func(b *testing.B) {
+		for b.Loop() {
+			util.NTT(r)
+		}
+	})
+	b.Run("reference", func(b *testing.B) {
+		for b.Loop() {
+			referenceNTT(w)
+		}
+	})
+}
+
+func BenchmarkInverseNTT(b *testing.B) {
+	var w [256]uint32
+	var r ring.Tq
+	for j := range w {
+		w[j] = rand.Uint32N(params.Q)
+		r[j] = field.NewFromReduced(w[j])
+	}
+	b.Run("montgomery", func(b *testing.B) {
+		for b.Loop() {
+			util.InverseNTT(r)
+		}
+	})
+	b.Run("reference", func(b *testing.B) {
+		for b.Loop() {
+			referenceInverseNTT(w)
+		}
+	})
+}
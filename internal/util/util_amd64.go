@@ -0,0 +1,100 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build amd64 && !purego
+
+package util
+
+import (
+	"github.com/trailofbits/ml-dsa/internal/field"
+	"github.com/trailofbits/ml-dsa/internal/ring"
+	"golang.org/x/sys/cpu"
+)
+
+// The AVX2 rejection sampling also uses POPCNT, which all AVX2 processors support.
+var useAVX2 = cpu.X86.HasAVX2 && cpu.X86.HasPOPCNT
+
+// The last three layers of nttAVX2, and the first three of invNTTAVX2, shuffle
+// the coefficients a[16k:16k+16] into two registers X and Y, and apply a
+// butterfly to each lane of X and the same lane of Y. zetasAVX2[i][k] holds the
+// zetas of the lanes of X for the layer with len = 4 >> i, and invZetasAVX2[i][k]
+// the negated ones for the layer with len = 1 << i.
+var zetasAVX2, invZetasAVX2 = func() (zetas, invZetas [3][16][8]field.T) {
+	// The coefficients in the lanes of X, relative to 16k, for len = 4, 2 and 1
+	lanes := [3][8]int{
+		{0, 1, 2, 3, 8, 9, 10, 11},
+		{0, 1, 8, 9, 4, 5, 12, 13},
+		{0, 8, 2, 10, 4, 12, 6, 14},
+	}
+	for i, len := range []int{4, 2, 1} {
+		for k := range 16 {
+			for lane, c := range lanes[i] {
+				b := (16*k + c) / (2 * len)
+				zetas[i][k][lane] = zetasMont[128/len+b]
+			}
+		}
+	}
+	for i, len := range []int{1, 2, 4} {
+		for k := range 16 {
+			for lane, c := range lanes[2-i] {
+				b := (16*k + c) / (2 * len)
+				invZetas[i][k][lane] = zetasMont[256/len-1-b].Neg()
+			}
+		}
+	}
+	return zetas, invZetas
+}()
+
+// rejIdx[mask] lists the lanes set in mask, for rejNTTPolyAVX2 to move the
+// accepted coefficients to the bottom lanes.
+var rejIdx = func() (idx [256][8]uint8) {
+	for mask := range idx {
+		n := 0
+		for lane := range 8 {
+			if mask&(1<<lane) != 0 {
+				idx[mask][n] = uint8(lane)
+				n++
+			}
+		}
+	}
+	return idx
+}()
+
+//go:noescape
+func nttAVX2(a *ring.Tq)
+
+//go:noescape
+func invNTTAVX2(a *ring.Tq)
+
+// rejNTTPolyAVX2 samples coefficients from buf into a[j:], eight candidates at a
+// time, until fewer than 32 bytes are left in buf or j exceeds 248. It returns the
+// new j and the number of bytes read from buf.
+//
+//go:noescape
+func rejNTTPolyAVX2(a *ring.Tq, j int, buf []byte) (n int, read int)
+
+func ntt(a *ring.Tq) {
+	if useAVX2 {
+		nttAVX2(a)
+	} else {
+		nttGeneric(a)
+	}
+}
+
+func invNTT(a *ring.Tq) {
+	if useAVX2 {
+		invNTTAVX2(a)
+	} else {
+		invNTTGeneric(a)
+	}
+}
+
+func rejNTTPoly(a *ring.Tq, j int, buf []byte) int {
+	if useAVX2 {
+		var read int
+		j, read = rejNTTPolyAVX2(a, j, buf)
+		buf = buf[read:]
+	}
+	return rejNTTPolyGeneric(a, j, buf)
+}
@@ -0,0 +1,341 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build amd64 && !purego
+
+#include "textflag.h"
+
+// The coefficients are field elements in Montgomery form, lazily reduced exactly
+// as in nttGeneric and invNTTGeneric, eight at a time. Y15 holds q, Y14 -q⁻¹ mod 2³²,
+// and Y13, Y12 and Y11 hold 2q, 4q and 8q.
+
+// MONTMUL sets out to a * z * 2⁻³² mod q in [0, 2q), lane by lane, for a < 2³² and
+// z < q. zodd holds the odd lanes of z in the low halves of its quadwords.
+// Clobbers Y4, Y5 and Y6.
+#define MONTMUL(a, z, zodd, out) \
+	VPMULUDQ z, a, Y4;          \
+	VPSRLQ   $32, a, Y5;        \
+	VPMULUDQ zodd, Y5, Y5;      \
+	VPMULUDQ Y14, Y4, Y6;       \
+	VPMULUDQ Y15, Y6, Y6;       \
+	VPADDQ   Y6, Y4, Y4;        \
+	VPMULUDQ Y14, Y5, Y6;       \
+	VPMULUDQ Y15, Y6, Y6;       \
+	VPADDQ   Y6, Y5, Y5;        \
+	VPSRLQ   $32, Y4, Y4;       \
+	VPBLENDD $0xaa, Y5, Y4, out
+
+// FWD is the Cooley-Tukey butterfly of NTT. Clobbers Y4 to Y7.
+#define FWD(a, b, z, zodd) \
+	MONTMUL(b, z, zodd, Y7); \
+	VPADDD Y13, a, b;        \
+	VPSUBD Y7, b, b;         \
+	VPADDD Y7, a, a
+
+// INV is the Gentleman-Sande butterfly of InverseNTT, where b < bound.
+// Clobbers Y4 to Y7.
+#define INV(a, b, z, zodd, bound) \
+	VPADDD bound, a, Y7;       \
+	VPSUBD b, Y7, Y7;          \
+	VPADDD b, a, a;            \
+	MONTMUL(Y7, z, zodd, b)
+
+// REDUCE reduces a < 17q to [0, q), by conditionally subtracting 8q, 4q, 2q, q
+// and q. Clobbers Y4.
+#define REDUCE(a) \
+	VPSUBD  Y11, a, Y4; \
+	VPMINUD Y4, a, a;   \
+	VPSUBD  Y12, a, Y4; \
+	VPMINUD Y4, a, a;   \
+	VPSUBD  Y13, a, Y4; \
+	VPMINUD Y4, a, a;   \
+	VPSUBD  Y15, a, Y4; \
+	VPMINUD Y4, a, a;   \
+	VPSUBD  Y15, a, Y4; \
+	VPMINUD Y4, a, a
+
+#define LOAD_CONSTANTS \
+	VPBROADCASTD q<>(SB), Y15;       \
+	VPBROADCASTD qNegInv<>(SB), Y14; \
+	VPADDD       Y15, Y15, Y13;      \
+	VPADDD       Y13, Y13, Y12;      \
+	VPADDD       Y12, Y12, Y11
+
+DATA q<>+0(SB)/4, $8380417
+GLOBL q<>(SB), RODATA|NOPTR, $4
+
+DATA qNegInv<>+0(SB)/4, $4236238847
+GLOBL qNegInv<>(SB), RODATA|NOPTR, $4
+
+// 256⁻¹ mod q, in Montgomery form
+DATA invN<>+0(SB)/4, $16382
+GLOBL invN<>(SB), RODATA|NOPTR, $4
+
+// func nttAVX2(a *ring.Tq)
+TEXT ·nttAVX2(SB), NOSPLIT, $0-8
+	MOVQ a+0(FP), DI
+	LOAD_CONSTANTS
+
+	// Layers with len >= 8 use a single zeta for each group of eight coefficients.
+	// R8 is len in bytes and R9 points to zetasMont[m].
+	MOVQ $512, R8
+	LEAQ ·zetasMont+4(SB), R9
+	LEAQ 1024(DI), R12
+
+nttLayer:
+	MOVQ DI, SI
+
+nttBlock:
+	VPBROADCASTD (R9), Y8
+	ADDQ         $4, R9
+	MOVQ         SI, R10
+	LEAQ         (SI)(R8*1), R11
+
+nttButterflies:
+	VMOVDQU (R10), Y0
+	VMOVDQU (R10)(R8*1), Y1
+	FWD(Y0, Y1, Y8, Y8)
+	VMOVDQU Y0, (R10)
+	VMOVDQU Y1, (R10)(R8*1)
+	ADDQ    $32, R10
+	CMPQ    R10, R11
+	JB      nttButterflies
+
+	LEAQ (SI)(R8*2), SI
+	CMPQ SI, R12
+	JB   nttBlock
+
+	SHRQ $1, R8
+	CMPQ R8, $32
+	JAE  nttLayer
+
+	// The last three layers work on pairs of registers A = a[16k:16k+8] and
+	// B = a[16k+8:16k+16], shuffled into X and Y so that each butterfly pairs a
+	// lane of X with the same lane of Y. The zetas of each lane of X come from
+	// zetasAVX2, which is laid out for these shuffles.
+	LEAQ ·zetasAVX2(SB), R9
+
+nttTail:
+	VMOVDQU (DI), Y0
+	VMOVDQU 32(DI), Y1
+
+	// len = 4: X = [A.lo, B.lo], Y = [A.hi, B.hi]
+	VPERM2I128 $0x20, Y1, Y0, Y2
+	VPERM2I128 $0x31, Y1, Y0, Y3
+	VMOVDQU    (R9), Y8
+	VPSRLQ     $32, Y8, Y9
+	FWD(Y2, Y3, Y8, Y9)
+	VPERM2I128 $0x20, Y3, Y2, Y0
+	VPERM2I128 $0x31, Y3, Y2, Y1
+
+	// len = 2: X = [a0, a1, b0, b1, a4, a5, b4, b5], Y = [a2, a3, b2, b3, a6, a7, b6, b7]
+	VPUNPCKLQDQ Y1, Y0, Y2
+	VPUNPCKHQDQ Y1, Y0, Y3
+	VMOVDQU     512(R9), Y8
+	VPSRLQ      $32, Y8, Y9
+	FWD(Y2, Y3, Y8, Y9)
+	VPUNPCKLQDQ Y3, Y2, Y0
+	VPUNPCKHQDQ Y3, Y2, Y1
+
+	// len = 1: X = [a0, b0, a2, b2, ...], Y = [a1, b1, a3, b3, ...]
+	VPSLLQ   $32, Y1, Y2
+	VPBLENDD $0xaa, Y2, Y0, Y2
+	VPSRLQ   $32, Y0, Y3
+	VPBLENDD $0xaa, Y1, Y3, Y3
+	VMOVDQU  1024(R9), Y8
+	VPSRLQ   $32, Y8, Y9
+	FWD(Y2, Y3, Y8, Y9)
+	VPSLLQ   $32, Y3, Y0
+	VPBLENDD $0xaa, Y0, Y2, Y0
+	VPSRLQ   $32, Y2, Y1
+	VPBLENDD $0xaa, Y3, Y1, Y1
+
+	REDUCE(Y0)
+	REDUCE(Y1)
+	VMOVDQU Y0, (DI)
+	VMOVDQU Y1, 32(DI)
+
+	ADDQ $32, R9
+	ADDQ $64, DI
+	CMPQ DI, R12
+	JB   nttTail
+
+	VZEROUPPER
+	RET
+
+// func invNTTAVX2(a *ring.Tq)
+TEXT ·invNTTAVX2(SB), NOSPLIT, $0-8
+	MOVQ a+0(FP), DI
+	LOAD_CONSTANTS
+	MOVQ DI, SI
+	LEAQ 1024(DI), R12
+
+	// The first three layers, as in nttAVX2 but in reverse. Before the layer
+	// for len, the coefficients are below len * q.
+	LEAQ ·invZetasAVX2(SB), R9
+
+invNTTHead:
+	VMOVDQU (SI), Y0
+	VMOVDQU 32(SI), Y1
+
+	// len = 1
+	VPSLLQ   $32, Y1, Y2
+	VPBLENDD $0xaa, Y2, Y0, Y2
+	VPSRLQ   $32, Y0, Y3
+	VPBLENDD $0xaa, Y1, Y3, Y3
+	VMOVDQU  (R9), Y8
+	VPSRLQ   $32, Y8, Y9
+	INV(Y2, Y3, Y8, Y9, Y15)
+	VPSLLQ   $32, Y3, Y0
+	VPBLENDD $0xaa, Y0, Y2, Y0
+	VPSRLQ   $32, Y2, Y1
+	VPBLENDD $0xaa, Y3, Y1, Y1
+
+	// len = 2
+	VPUNPCKLQDQ Y1, Y0, Y2
+	VPUNPCKHQDQ Y1, Y0, Y3
+	VMOVDQU     512(R9), Y8
+	VPSRLQ      $32, Y8, Y9
+	INV(Y2, Y3, Y8, Y9, Y13)
+	VPUNPCKLQDQ Y3, Y2, Y0
+	VPUNPCKHQDQ Y3, Y2, Y1
+
+	// len = 4
+	VPERM2I128 $0x20, Y1, Y0, Y2
+	VPERM2I128 $0x31, Y1, Y0, Y3
+	VMOVDQU    1024(R9), Y8
+	VPSRLQ     $32, Y8, Y9
+	INV(Y2, Y3, Y8, Y9, Y12)
+	VPERM2I128 $0x20, Y3, Y2, Y0
+	VPERM2I128 $0x31, Y3, Y2, Y1
+
+	VMOVDQU Y0, (SI)
+	VMOVDQU Y1, 32(SI)
+
+	ADDQ $32, R9
+	ADDQ $64, SI
+	CMPQ SI, R12
+	JB   invNTTHead
+
+	// Layers with len >= 8. R8 is len in bytes, R9 points to zetasMont[m], and
+	// Y10 holds the bound len * q.
+	MOVQ    $32, R8
+	LEAQ    ·zetasMont+124(SB), R9
+	VMOVDQU Y11, Y10
+
+invNTTLayer:
+	MOVQ DI, SI
+
+invNTTBlock:
+	VPBROADCASTD (R9), Y8
+	VPSUBD       Y8, Y15, Y8 // -zetas[m]
+	SUBQ         $4, R9
+	MOVQ         SI, R10
+	LEAQ         (SI)(R8*1), R11
+
+invNTTButterflies:
+	VMOVDQU (R10), Y0
+	VMOVDQU (R10)(R8*1), Y1
+	INV(Y0, Y1, Y8, Y8, Y10)
+	VMOVDQU Y0, (R10)
+	VMOVDQU Y1, (R10)(R8*1)
+	ADDQ    $32, R10
+	CMPQ    R10, R11
+	JB      invNTTButterflies
+
+	LEAQ (SI)(R8*2), SI
+	CMPQ SI, R12
+	JB   invNTTBlock
+
+	VPADDD Y10, Y10, Y10
+	SHLQ   $1, R8
+	CMPQ   R8, $512
+	JBE    invNTTLayer
+
+	// Multiply by 256⁻¹ and reduce
+	VPBROADCASTD invN<>(SB), Y8
+
+invNTTScale:
+	VMOVDQU (DI), Y0
+	MONTMUL(Y0, Y8, Y8, Y0)
+	VPSUBD  Y15, Y0, Y4
+	VPMINUD Y4, Y0, Y0
+	VMOVDQU Y0, (DI)
+	ADDQ    $32, DI
+	CMPQ    DI, R12
+	JB      invNTTScale
+
+	VZEROUPPER
+	RET
+
+DATA rSquared<>+0(SB)/4, $2365951
+GLOBL rSquared<>(SB), RODATA|NOPTR, $4
+
+DATA mask23<>+0(SB)/4, $0x7fffff
+GLOBL mask23<>(SB), RODATA|NOPTR, $4
+
+// Spreads bytes 0-11 of the low lane and 4-15 of the high lane into 3-byte
+// little-endian integers
+DATA rejShuffle<>+0(SB)/8, $0xff050403ff020100
+DATA rejShuffle<>+8(SB)/8, $0xff0b0a09ff080706
+DATA rejShuffle<>+16(SB)/8, $0xff090807ff060504
+DATA rejShuffle<>+24(SB)/8, $0xff0f0e0dff0c0b0a
+GLOBL rejShuffle<>(SB), RODATA|NOPTR, $32
+
+// func rejNTTPolyAVX2(a *ring.Tq, j int, buf []byte) (n int, read int)
+TEXT ·rejNTTPolyAVX2(SB), NOSPLIT, $0-56
+	MOVQ a+0(FP), DI
+	MOVQ j+8(FP), AX
+	MOVQ buf_base+16(FP), SI
+	MOVQ buf_len+24(FP), CX
+	MOVQ SI, BX
+	CMPQ CX, $32
+	JB   rejDone
+
+	VPBROADCASTD q<>(SB), Y15
+	VPBROADCASTD qNegInv<>(SB), Y14
+	VPBROADCASTD rSquared<>(SB), Y13
+	VPBROADCASTD mask23<>(SB), Y12
+	VMOVDQU      rejShuffle<>(SB), Y11
+	LEAQ         ·rejIdx(SB), R9
+
+	// CX is the last position of SI from which 32 bytes can be loaded
+	LEAQ -32(SI)(CX*1), CX
+
+rejLoop:
+	// Eight coefficients are written at a time, and 32 bytes are read for
+	// every eight candidates of 24 bytes.
+	CMPQ AX, $248
+	JA   rejDone
+	CMPQ SI, CX
+	JA   rejDone
+
+	VMOVDQU  (SI), Y0
+	VPERMQ   $0x94, Y0, Y0
+	VPSHUFB  Y11, Y0, Y0
+	VPAND    Y12, Y0, Y0
+	VPCMPGTD Y0, Y15, Y1
+	VMOVMSKPS Y1, DX
+
+	// Convert to Montgomery form, as in field.NewFromReduced
+	MONTMUL(Y0, Y13, Y13, Y0)
+	VPSUBD  Y15, Y0, Y4
+	VPMINUD Y4, Y0, Y0
+
+	// Move the accepted coefficients to the bottom lanes
+	VPMOVZXBD (R9)(DX*8), Y2
+	VPERMD    Y0, Y2, Y0
+	VMOVDQU   Y0, (DI)(AX*4)
+
+	POPCNTL DX, DX
+	ADDQ    DX, AX
+	ADDQ    $24, SI
+	JMP     rejLoop
+
+rejDone:
+	SUBQ BX, SI
+	MOVQ AX, n+40(FP)
+	MOVQ SI, read+48(FP)
+	VZEROUPPER
+	RET
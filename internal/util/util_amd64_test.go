@@ -0,0 +1,128 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+//go:build amd64 && !purego
+
+package util
+
+import (
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/ml-dsa/internal/field"
+	"github.com/trailofbits/ml-dsa/internal/params"
+	"github.com/trailofbits/ml-dsa/internal/ring"
+)
+
+func skipWithoutAVX2(t testing.TB) {
+	if !useAVX2 {
+		t.Skip("AVX2 is not supported")
+	}
+}
+
+func randomTq() (a ring.Tq) {
+	for j := range a {
+		a[j] = field.NewFromReduced(rand.Uint32N(params.Q))
+	}
+	return a
+}
+
+func nttTestInputs() []ring.Tq {
+	var zero, top ring.Tq
+	for j := range top {
+		top[j] = field.NewFromReduced(params.Q - 1)
+	}
+	inputs := []ring.Tq{zero, top}
+	for range 1000 {
+		inputs = append(inputs, randomTq())
+	}
+	return inputs
+}
+
+func TestNTTAVX2(t *testing.T) {
+	skipWithoutAVX2(t)
+	for _, a := range nttTestInputs() {
+		expected, got := a, a
+		nttGeneric(&expected)
+		nttAVX2(&got)
+		assert.Equal(t, expected, got)
+	}
+}
+
+func TestInverseNTTAVX2(t *testing.T) {
+	skipWithoutAVX2(t)
+	for _, a := range nttTestInputs() {
+		expected, got := a, a
+		invNTTGeneric(&expected)
+		invNTTAVX2(&got)
+		assert.Equal(t, expected, got)
+	}
+}
+
+func TestRejNTTPolyAVX2(t *testing.T) {
+	skipWithoutAVX2(t)
+	for i := range 1000 {
+		buf := make([]byte, 3*rand.IntN(300))
+		for k := range buf {
+			buf[k] = byte(rand.Uint32())
+			// Make rejections more likely for some of the buffers
+			if i%2 == 1 && rand.IntN(4) == 0 {
+				buf[k] |= 0x7f
+			}
+		}
+		j := rand.IntN(256)
+		var expected, got ring.Tq
+		n := rejNTTPolyGeneric(&expected, j, buf)
+		assert.Equal(t, n, rejNTTPoly(&got, j, buf))
+		assert.Equal(t, expected[j:n], got[j:n])
+	}
+}
+
+func BenchmarkNTTAVX2(b *testing.B) {
+	skipWithoutAVX2(b)
+	a := randomTq()
+	b.Run("generic", func(b *testing.B) {
+		for b.Loop() {
+			nttGeneric(&a)
+		}
+	})
+	b.Run("avx2", func(b *testing.B) {
+		for b.Loop() {
+			nttAVX2(&a)
+		}
+	})
+}
+
+func BenchmarkInverseNTTAVX2(b *testing.B) {
+	skipWithoutAVX2(b)
+	a := randomTq()
+	b.Run("generic", func(b *testing.B) {
+		for b.Loop() {
+			invNTTGeneric(&a)
+		}
+	})
+	b.Run("avx2", func(b *testing.B) {
+		for b.Loop() {
+			invNTTAVX2(&a)
+		}
+	})
+}
+
+func BenchmarkRejNTTPolyAVX2(b *testing.B) {
+	skipWithoutAVX2(b)
+	seed := make([]byte, 34)
+	b.Run("generic", func(b *testing.B) {
+		useAVX2 = false
+		defer func() { useAVX2 = true }()
+		for b.Loop() {
+			RejNTTPoly(seed)
+		}
+	})
+	b.Run("avx2", func(b *testing.B) {
+		for b.Loop() {
+			RejNTTPoly(seed)
+		}
+	})
+}
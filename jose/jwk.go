@@ -0,0 +1,169 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package jose encodes ML-DSA keys as JSON Web Keys (JWK, [RFC 7517]), and signs
+// and verifies JSON Web Signatures (JWS, [RFC 7515]) in the compact serialization,
+// as specified in [draft-ietf-cose-dilithium].
+//
+// Keys use the "AKP" (Algorithm Key Pair) key type, with the parameter set as the
+// "alg" parameter (e.g. "ML-DSA-65"). The public key defined in FIPS 204 is the
+// "pub" parameter, and private keys hold their 32-byte seed in the "priv" parameter.
+// Private keys without a known seed cannot be encoded.
+//
+// Signatures use pure ML-DSA and the empty context string.
+//
+// [RFC 7517]: https://www.rfc-editor.org/rfc/rfc7517
+// [RFC 7515]: https://www.rfc-editor.org/rfc/rfc7515
+// [draft-ietf-cose-dilithium]: https://datatracker.ietf.org/doc/draft-ietf-cose-dilithium/
+package jose
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/trailofbits/ml-dsa/mldsa"
+)
+
+// keyType is the JWK "kty" of ML-DSA keys.
+const keyType = "AKP"
+
+// b64 is the base64url encoding without padding used throughout JOSE.
+var b64 = base64.RawURLEncoding.Strict()
+
+// jwk holds the parameters of an AKP JSON Web Key. Byte strings are base64url
+// encoded; encoding/json would use the standard encoding.
+type jwk struct {
+	Kty  string `json:"kty"`
+	Alg  string `json:"alg"`
+	Kid  string `json:"kid,omitempty"`
+	Pub  string `json:"pub"`
+	Priv string `json:"priv,omitempty"`
+}
+
+// MarshalPublicJWK encodes pub as a JSON Web Key. kid is the optional "kid"
+// parameter; see [JWKThumbprint] for a way to derive it from the key.
+func MarshalPublicJWK(pub mldsa.PublicKey, kid string) ([]byte, error) {
+	scheme := mldsa.SchemeOf(pub)
+	if scheme == nil {
+		return nil, errors.New("jose: unsupported public key type")
+	}
+	return json.Marshal(&jwk{
+		Kty: keyType,
+		Alg: scheme.Name(),
+		Kid: kid,
+		Pub: b64.EncodeToString(pub.Bytes()),
+	})
+}
+
+// MarshalPrivateJWK encodes priv as a JSON Web Key, holding both its seed and its
+// public key. It returns an error if the seed of priv is not known.
+func MarshalPrivateJWK(priv mldsa.PrivateKey, kid string) ([]byte, error) {
+	scheme := mldsa.SchemeOf(priv)
+	if scheme == nil {
+		return nil, errors.New("jose: unsupported private key type")
+	}
+	seed, err := priv.Seed()
+	if err != nil {
+		return nil, fmt.Errorf("jose: %w", err)
+	}
+	defer clear(seed)
+	return json.Marshal(&jwk{
+		Kty:  keyType,
+		Alg:  scheme.Name(),
+		Kid:  kid,
+		Pub:  b64.EncodeToString(priv.Public().(mldsa.PublicKey).Bytes()),
+		Priv: b64.EncodeToString(seed),
+	})
+}
+
+// parseJWK decodes the common parameters of a JWK, and returns its parameter
+// set and public key.
+func parseJWK(data []byte, k *jwk) (mldsa.Scheme, mldsa.PublicKey, error) {
+	if err := json.Unmarshal(data, k); err != nil {
+		return nil, nil, fmt.Errorf("jose: malformed JWK: %w", err)
+	}
+	if k.Kty != keyType {
+		return nil, nil, fmt.Errorf("jose: unsupported JWK key type %q", k.Kty)
+	}
+	scheme := schemeByAlg(k.Alg)
+	if scheme == nil {
+		return nil, nil, fmt.Errorf("jose: unsupported JWK algorithm %q", k.Alg)
+	}
+	b, err := b64.DecodeString(k.Pub)
+	if err != nil || len(b) != scheme.PublicKeySize() {
+		return nil, nil, errors.New("jose: malformed JWK public key")
+	}
+	pub, err := scheme.PublicKeyFromBytes(b)
+	if err != nil {
+		return nil, nil, fmt.Errorf("jose: %w", err)
+	}
+	return scheme, pub, nil
+}
+
+// ParsePublicJWK decodes an ML-DSA JSON Web Key, and returns its public key
+// and "kid" parameter. The "priv" parameter of a private JWK is ignored.
+func ParsePublicJWK(data []byte) (mldsa.PublicKey, string, error) {
+	var k jwk
+	_, pub, err := parseJWK(data, &k)
+	if err != nil {
+		return nil, "", err
+	}
+	return pub, k.Kid, nil
+}
+
+// ParsePrivateJWK decodes a private ML-DSA JSON Web Key, and returns its private
+// key and "kid" parameter. The public key in the JWK must be the one derived from
+// the seed.
+func ParsePrivateJWK(data []byte) (mldsa.PrivateKey, string, error) {
+	var k jwk
+	scheme, pub, err := parseJWK(data, &k)
+	if err != nil {
+		return nil, "", err
+	}
+	if k.Priv == "" {
+		return nil, "", errors.New("jose: JWK is not a private key")
+	}
+	seed, err := b64.DecodeString(k.Priv)
+	defer clear(seed)
+	if err != nil || len(seed) != scheme.SeedSize() {
+		return nil, "", errors.New("jose: malformed JWK private key")
+	}
+	priv, err := scheme.PrivateKeyFromSeed(seed)
+	if err != nil {
+		return nil, "", fmt.Errorf("jose: %w", err)
+	}
+	if !bytes.Equal(priv.Public().(mldsa.PublicKey).Bytes(), pub.Bytes()) {
+		priv.Destroy()
+		return nil, "", errors.New("jose: JWK public key does not match the private key")
+	}
+	return priv, k.Kid, nil
+}
+
+// JWKThumbprint returns the SHA-256 JWK thumbprint of pub, as defined in RFC 7638.
+// Its base64url encoding is a common choice of "kid".
+func JWKThumbprint(pub mldsa.PublicKey) ([]byte, error) {
+	scheme := mldsa.SchemeOf(pub)
+	if scheme == nil {
+		return nil, errors.New("jose: unsupported public key type")
+	}
+	// The required members of an AKP key, in lexicographic order, without whitespace
+	h := sha256.New()
+	fmt.Fprintf(h, `{"alg":"%s","kty":"%s","pub":"%s"}`, scheme.Name(), keyType, b64.EncodeToString(pub.Bytes()))
+	return h.Sum(nil), nil
+}
+
+// schemeByAlg returns the parameter set named by a JOSE "alg", which, unlike
+// mldsa.SchemeByName, is case-sensitive.
+func schemeByAlg(alg string) mldsa.Scheme {
+	for _, s := range mldsa.Schemes() {
+		if s.Name() == alg {
+			return s
+		}
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package jose_test
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trailofbits/ml-dsa/jose"
+	"github.com/trailofbits/ml-dsa/mldsa"
+)
+
+func TestJWKRoundTrip(t *testing.T) {
+	for _, s := range mldsa.Schemes() {
+		t.Run(s.Name(), func(t *testing.T) {
+			pub, priv, err := s.GenerateKey(nil)
+			require.NoError(t, err)
+
+			data, err := jose.MarshalPublicJWK(pub, "key-1")
+			assert.NoError(t, err)
+			var params map[string]string
+			assert.NoError(t, json.Unmarshal(data, &params))
+			assert.Equal(t, map[string]string{
+				"kty": "AKP",
+				"alg": s.Name(),
+				"kid": "key-1",
+				"pub": base64.RawURLEncoding.EncodeToString(pub.Bytes()),
+			}, params)
+			parsedPub, kid, err := jose.ParsePublicJWK(data)
+			assert.NoError(t, err)
+			assert.Equal(t, pub, parsedPub)
+			assert.Equal(t, "key-1", kid)
+			_, _, err = jose.ParsePrivateJWK(data)
+			assert.Error(t, err)
+
+			data, err = jose.MarshalPrivateJWK(priv, "")
+			assert.NoError(t, err)
+			params = nil
+			assert.NoError(t, json.Unmarshal(data, &params))
+			seed, err := priv.Seed()
+			assert.NoError(t, err)
+			assert.Equal(t, base64.RawURLEncoding.EncodeToString(seed), params["priv"])
+			assert.NotContains(t, params, "kid")
+			parsedPriv, kid, err := jose.ParsePrivateJWK(data)
+			assert.NoError(t, err)
+			assert.Equal(t, priv.EncodeExpanded(), parsedPriv.EncodeExpanded())
+			assert.Empty(t, kid)
+			parsedPub, _, err = jose.ParsePublicJWK(data)
+			assert.NoError(t, err)
+			assert.Equal(t, pub, parsedPub)
+		})
+	}
+}
+
+func TestMarshalPrivateJWKWithoutSeed(t *testing.T) {
+	_, priv, err := mldsa.MLDSA44.GenerateKey(nil)
+	require.NoError(t, err)
+	expanded, err := mldsa.MLDSA44.PrivateKeyFromExpanded(priv.EncodeExpanded())
+	assert.NoError(t, err)
+	_, err = jose.MarshalPrivateJWK(expanded, "")
+	assert.Error(t, err)
+
+	priv.Destroy()
+	_, err = jose.MarshalPrivateJWK(priv, "")
+	assert.Error(t, err)
+}
+
+func TestJWKThumbprint(t *testing.T) {
+	pub, _, err := mldsa.MLDSA65.GenerateKey(nil)
+	require.NoError(t, err)
+	thumbprint, err := jose.JWKThumbprint(pub)
+	assert.NoError(t, err)
+	assert.Len(t, thumbprint, 32)
+
+	// RFC 7638, Section 3: the hash of the required parameters, in lexicographic order
+	expected := sha256.Sum256([]byte(`{"alg":"ML-DSA-65","kty":"AKP","pub":"` +
+		base64.RawURLEncoding.EncodeToString(pub.Bytes()) + `"}`))
+	assert.Equal(t, expected[:], thumbprint)
+
+	other, _, err := mldsa.MLDSA65.GenerateKey(nil)
+	require.NoError(t, err)
+	otherThumbprint, err := jose.JWKThumbprint(other)
+	assert.NoError(t, err)
+	assert.NotEqual(t, thumbprint, otherThumbprint)
+}
+
+func TestParseJWKMalformed(t *testing.T) {
+	pub, _, err := mldsa.MLDSA44.GenerateKey(nil)
+	require.NoError(t, err)
+	_, other, err := mldsa.MLDSA44.GenerateKey(nil)
+	require.NoError(t, err)
+	pubB64 := base64.RawURLEncoding.EncodeToString(pub.Bytes())
+	seed, err := other.Seed()
+	assert.NoError(t, err)
+	otherSeed := base64.RawURLEncoding.EncodeToString(seed)
+
+	for name, data := range map[string]string{
+		"not JSON":      `AKP`,
+		"key type":      fmt.Sprintf(`{"kty":"OKP","alg":"ML-DSA-44","pub":%q}`, pubB64),
+		"algorithm":     fmt.Sprintf(`{"kty":"AKP","alg":"ml-dsa-44","pub":%q}`, pubB64),
+		"wrong size":    fmt.Sprintf(`{"kty":"AKP","alg":"ML-DSA-65","pub":%q}`, pubB64),
+		"padding":       fmt.Sprintf(`{"kty":"AKP","alg":"ML-DSA-44","pub":%q}`, pubB64+"=="),
+		"std encoding":  fmt.Sprintf(`{"kty":"AKP","alg":"ML-DSA-44","pub":%q}`, base64.StdEncoding.EncodeToString(pub.Bytes())),
+		"missing pub":   `{"kty":"AKP","alg":"ML-DSA-44"}`,
+		"seed size":     fmt.Sprintf(`{"kty":"AKP","alg":"ML-DSA-44","pub":%q,"priv":"AAAA"}`, pubB64),
+		"mismatched":    fmt.Sprintf(`{"kty":"AKP","alg":"ML-DSA-44","pub":%q,"priv":%q}`, pubB64, otherSeed),
+		"priv encoding": fmt.Sprintf(`{"kty":"AKP","alg":"ML-DSA-44","pub":%q,"priv":"!"}`, pubB64),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := jose.ParsePrivateJWK([]byte(data))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package jose
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/trailofbits/ml-dsa/mldsa"
+)
+
+// Header holds the JOSE header parameters of a JWS that this package understands.
+type Header struct {
+	// Algorithm is the "alg" parameter, the parameter set of the signing key.
+	// It is set by SignCompact.
+	Algorithm string `json:"alg"`
+	// KeyID is the optional "kid" parameter.
+	KeyID string `json:"kid,omitempty"`
+	// Type is the optional "typ" parameter, e.g. "JWT".
+	Type string `json:"typ,omitempty"`
+	// ContentType is the optional "cty" parameter.
+	ContentType string `json:"cty,omitempty"`
+}
+
+// SignCompact signs payload with priv, and returns the JWS in the compact
+// serialization. The "alg" parameter of the header is set to the parameter set of
+// priv, and h provides the other parameters. h may be nil.
+func SignCompact(priv mldsa.PrivateKey, payload []byte, h *Header) (string, error) {
+	scheme := mldsa.SchemeOf(priv)
+	if scheme == nil {
+		return "", errors.New("jose: unsupported private key type")
+	}
+	var header Header
+	if h != nil {
+		header = *h
+	}
+	header.Algorithm = scheme.Name()
+	encodedHeader, err := json.Marshal(&header)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := b64.EncodeToString(encodedHeader) + "." + b64.EncodeToString(payload)
+	sig, err := scheme.Sign(priv, nil, []byte(signingInput), nil)
+	if err != nil {
+		return "", fmt.Errorf("jose: %w", err)
+	}
+	return signingInput + "." + b64.EncodeToString(sig), nil
+}
+
+// splitCompact splits a compact JWS into its signing input and its decoded
+// header, payload and signature.
+func splitCompact(jws string) (signingInput string, h *Header, payload, sig []byte, err error) {
+	parts := strings.Split(jws, ".")
+	if len(parts) != 3 {
+		return "", nil, nil, nil, errors.New("jose: malformed compact JWS")
+	}
+	encodedHeader, err := b64.DecodeString(parts[0])
+	if err != nil {
+		return "", nil, nil, nil, errors.New("jose: malformed JWS header")
+	}
+	var params map[string]json.RawMessage
+	h = new(Header)
+	if json.Unmarshal(encodedHeader, &params) != nil || json.Unmarshal(encodedHeader, h) != nil {
+		return "", nil, nil, nil, errors.New("jose: malformed JWS header")
+	}
+	// No extensions are supported, so critical ones must be rejected (RFC 7515, Section 4.1.11)
+	if _, ok := params["crit"]; ok {
+		return "", nil, nil, nil, errors.New("jose: unsupported critical JWS header parameters")
+	}
+	if payload, err = b64.DecodeString(parts[1]); err != nil {
+		return "", nil, nil, nil, errors.New("jose: malformed JWS payload")
+	}
+	if sig, err = b64.DecodeString(parts[2]); err != nil {
+		return "", nil, nil, nil, errors.New("jose: malformed JWS signature")
+	}
+	return parts[0] + "." + parts[1], h, payload, sig, nil
+}
+
+// ParseCompactHeader returns the header of a compact JWS, without verifying it,
+// for instance to select the verification key by its "kid".
+func ParseCompactHeader(jws string) (*Header, error) {
+	_, h, _, _, err := splitCompact(jws)
+	return h, err
+}
+
+// VerifyCompact verifies a JWS in the compact serialization with pub, and returns
+// its payload and header. The "alg" parameter of the header must be the parameter
+// set of pub.
+func VerifyCompact(pub mldsa.PublicKey, jws string) ([]byte, *Header, error) {
+	scheme := mldsa.SchemeOf(pub)
+	if scheme == nil {
+		return nil, nil, errors.New("jose: unsupported public key type")
+	}
+	signingInput, h, payload, sig, err := splitCompact(jws)
+	if err != nil {
+		return nil, nil, err
+	}
+	if h.Algorithm != scheme.Name() {
+		return nil, nil, fmt.Errorf("jose: JWS algorithm %q does not match the %s key", h.Algorithm, scheme.Name())
+	}
+	if !scheme.Verify(pub, []byte(signingInput), sig, nil) {
+		return nil, nil, errors.New("jose: invalid JWS signature")
+	}
+	return payload, h, nil
+}
@@ -0,0 +1,145 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package jose_test
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trailofbits/ml-dsa/jose"
+	"github.com/trailofbits/ml-dsa/mldsa"
+)
+
+func TestSignVerifyCompact(t *testing.T) {
+	payload := []byte(`{"iss":"example.com","sub":"alice"}`)
+	for _, s := range mldsa.Schemes() {
+		t.Run(s.Name(), func(t *testing.T) {
+			pub, priv, err := s.GenerateKey(nil)
+			require.NoError(t, err)
+			jws, err := jose.SignCompact(priv, payload, &jose.Header{KeyID: "key-1", Type: "JWT"})
+			assert.NoError(t, err)
+
+			parts := strings.Split(jws, ".")
+			assert.Len(t, parts, 3)
+			header, err := base64.RawURLEncoding.DecodeString(parts[0])
+			assert.NoError(t, err)
+			assert.Equal(t, fmt.Sprintf(`{"alg":%q,"kid":"key-1","typ":"JWT"}`, s.Name()), string(header))
+			sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+			assert.NoError(t, err)
+			assert.Len(t, sig, s.SignatureSize())
+
+			// The signature is a pure ML-DSA signature of the signing input, with
+			// an empty context
+			assert.True(t, s.Verify(pub, []byte(parts[0]+"."+parts[1]), sig, nil))
+
+			h, err := jose.ParseCompactHeader(jws)
+			assert.NoError(t, err)
+			assert.Equal(t, &jose.Header{Algorithm: s.Name(), KeyID: "key-1", Type: "JWT"}, h)
+
+			verified, h, err := jose.VerifyCompact(pub, jws)
+			assert.NoError(t, err)
+			assert.Equal(t, payload, verified)
+			assert.Equal(t, "key-1", h.KeyID)
+
+			other, _, err := s.GenerateKey(nil)
+			require.NoError(t, err)
+			_, _, err = jose.VerifyCompact(other, jws)
+			assert.Error(t, err)
+			tampered := parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"mallory"}`)) + "." + parts[2]
+			_, _, err = jose.VerifyCompact(pub, tampered)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestVerifyCompactAlgorithm(t *testing.T) {
+	pub44, priv44, err := mldsa.MLDSA44.GenerateKey(nil)
+	require.NoError(t, err)
+	pub65, _, err := mldsa.MLDSA65.GenerateKey(nil)
+	require.NoError(t, err)
+	jws, err := jose.SignCompact(priv44, []byte("payload"), nil)
+	assert.NoError(t, err)
+	_, _, err = jose.VerifyCompact(pub65, jws)
+	assert.ErrorContains(t, err, "does not match")
+
+	// The "alg" parameter is part of the signed header, so it cannot be changed
+	parts := strings.Split(jws, ".")
+	for _, header := range []string{`{"alg":"ML-DSA-65"}`, `{"alg":"ml-dsa-44"}`, `{"alg":"none"}`} {
+		forged := base64.RawURLEncoding.EncodeToString([]byte(header)) + "." + parts[1] + "." + parts[2]
+		_, _, err = jose.VerifyCompact(pub44, forged)
+		assert.Error(t, err, header)
+	}
+}
+
+func TestVerifyCompactMalformed(t *testing.T) {
+	pub, priv, err := mldsa.MLDSA44.GenerateKey(nil)
+	require.NoError(t, err)
+	sign := func(header string) string {
+		t.Helper()
+		input := base64.RawURLEncoding.EncodeToString([]byte(header)) + ".cGF5bG9hZA"
+		sig, err := mldsa.MLDSA44.Sign(priv, nil, []byte(input), nil)
+		assert.NoError(t, err)
+		return input + "." + base64.RawURLEncoding.EncodeToString(sig)
+	}
+	valid := sign(`{"alg":"ML-DSA-44"}`)
+	_, _, err = jose.VerifyCompact(pub, valid)
+	assert.NoError(t, err)
+
+	for name, jws := range map[string]string{
+		"parts":     "a.b",
+		"header":    sign(`{"alg":`),
+		"alg type":  sign(`{"alg":44}`),
+		"crit":      sign(`{"alg":"ML-DSA-44","crit":["b64"],"b64":false}`),
+		"padding":   valid + "==",
+		"payload":   strings.Replace(valid, ".cGF5bG9hZA.", ".cGF5bG9hZA=.", 1),
+		"extra dot": valid + ".",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := jose.VerifyCompact(pub, jws)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func ExampleSignCompact() {
+	pub, priv, err := mldsa.MLDSA65.GenerateKey(nil)
+	if err != nil {
+		panic(err)
+	}
+	thumbprint, err := jose.JWKThumbprint(pub)
+	if err != nil {
+		panic(err)
+	}
+	kid := base64.RawURLEncoding.EncodeToString(thumbprint)
+
+	jwt, err := jose.SignCompact(priv, []byte(`{"sub":"alice"}`), &jose.Header{KeyID: kid, Type: "JWT"})
+	if err != nil {
+		panic(err)
+	}
+
+	// The verifier selects the key by its "kid", from its JWK
+	jwk, err := jose.MarshalPublicJWK(pub, kid)
+	if err != nil {
+		panic(err)
+	}
+	header, err := jose.ParseCompactHeader(jwt)
+	if err != nil {
+		panic(err)
+	}
+	verifier, verifierKid, err := jose.ParsePublicJWK(jwk)
+	if err != nil || verifierKid != header.KeyID {
+		panic("unknown key")
+	}
+	claims, _, err := jose.VerifyCompact(verifier, jwt)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(header.Algorithm, string(claims))
+	// Output: ML-DSA-65 {"sub":"alice"}
+}
@@ -0,0 +1,239 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package mldsa provides a common interface to the ML-DSA parameter sets, so that
+// the parameter set can be selected at runtime. See [FIPS 204] for more details.
+//
+// The keys returned by a [Scheme] are the concrete key types of the
+// [mldsa44], [mldsa65] and [mldsa87] packages.
+//
+// [FIPS 204]: https://nvlpubs.nist.gov/nistpubs/fips/nist.fips.204.pdf
+// [mldsa44]: https://pkg.go.dev/github.com/trailofbits/ml-dsa/mldsa44
+// [mldsa65]: https://pkg.go.dev/github.com/trailofbits/ml-dsa/mldsa65
+// [mldsa87]: https://pkg.go.dev/github.com/trailofbits/ml-dsa/mldsa87
+package mldsa
+
+import (
+	"crypto"
+	"encoding/asn1"
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/trailofbits/ml-dsa/mldsa44"
+	"github.com/trailofbits/ml-dsa/mldsa65"
+	"github.com/trailofbits/ml-dsa/mldsa87"
+	options "github.com/trailofbits/ml-dsa/options"
+)
+
+// PublicKey is implemented by *mldsa44.PublicKey, *mldsa65.PublicKey and *mldsa87.PublicKey.
+type PublicKey interface {
+	Bytes() []byte
+	Verify(msg, sig []byte) bool
+	VerifyWithOptions(msg, sig []byte, opts *options.Options) bool
+	Precompute()
+}
+
+// PrivateKey is implemented by *mldsa44.PrivateKey, *mldsa65.PrivateKey and *mldsa87.PrivateKey.
+type PrivateKey interface {
+	crypto.Signer
+	SignTo(dst []byte, rand io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error)
+	Seed() ([]byte, error)
+	EncodeExpanded() []byte
+	Precompute()
+	Destroy()
+}
+
+// Scheme is an ML-DSA parameter set.
+type Scheme interface {
+	// Name returns the name of the parameter set, e.g. "ML-DSA-65".
+	Name() string
+	// OID returns the object identifier of the parameter set, as assigned by NIST.
+	OID() asn1.ObjectIdentifier
+
+	// PublicKeySize returns the size in bytes of an encoded public key.
+	PublicKeySize() int
+	// PrivateKeySize returns the size in bytes of an expanded private key.
+	PrivateKeySize() int
+	// SeedSize returns the size in bytes of a private key seed.
+	SeedSize() int
+	// SignatureSize returns the size in bytes of a signature.
+	SignatureSize() int
+
+	// GenerateKey generates a key pair. If rand is nil, crypto/rand is used.
+	GenerateKey(rand io.Reader) (PublicKey, PrivateKey, error)
+	// PublicKeyFromBytes decodes a public key as defined in FIPS 204.
+	PublicKeyFromBytes(b []byte) (PublicKey, error)
+	// PrivateKeyFromSeed derives a private key from a 32-byte seed.
+	PrivateKeyFromSeed(seed []byte) (PrivateKey, error)
+	// PrivateKeyFromExpanded decodes an expanded private key as defined in FIPS 204.
+	PrivateKeyFromExpanded(b []byte) (PrivateKey, error)
+
+	// Sign signs msg with priv, which must belong to this parameter set.
+	// If rand is nil, crypto/rand is used. opts may be nil.
+	Sign(priv PrivateKey, rand io.Reader, msg []byte, opts *options.Options) ([]byte, error)
+	// Verify reports whether sig is a valid signature of msg under pub,
+	// which must belong to this parameter set. opts may be nil.
+	Verify(pub PublicKey, msg, sig []byte, opts *options.Options) bool
+}
+
+var (
+	// MLDSA44 is the ML-DSA-44 parameter set, implemented by package mldsa44.
+	MLDSA44 Scheme = &scheme[*mldsa44.PublicKey, *mldsa44.PrivateKey]{
+		name:                   "ML-DSA-44",
+		oid:                    asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 17},
+		publicKeySize:          mldsa44.PublicKeySize,
+		privateKeySize:         mldsa44.PrivateKeySize,
+		seedSize:               mldsa44.SeedSize,
+		signatureSize:          mldsa44.SignatureSize,
+		generateKeyPair:        mldsa44.GenerateKeyPair,
+		publicKeyFromBytes:     mldsa44.PublicKeyFromBytes,
+		privateKeyFromSeed:     mldsa44.PrivateKeyFromSeed,
+		privateKeyFromExpanded: mldsa44.PrivateKeyFromExpanded,
+	}
+
+	// MLDSA65 is the ML-DSA-65 parameter set, implemented by package mldsa65.
+	MLDSA65 Scheme = &scheme[*mldsa65.PublicKey, *mldsa65.PrivateKey]{
+		name:                   "ML-DSA-65",
+		oid:                    asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 18},
+		publicKeySize:          mldsa65.PublicKeySize,
+		privateKeySize:         mldsa65.PrivateKeySize,
+		seedSize:               mldsa65.SeedSize,
+		signatureSize:          mldsa65.SignatureSize,
+		generateKeyPair:        mldsa65.GenerateKeyPair,
+		publicKeyFromBytes:     mldsa65.PublicKeyFromBytes,
+		privateKeyFromSeed:     mldsa65.PrivateKeyFromSeed,
+		privateKeyFromExpanded: mldsa65.PrivateKeyFromExpanded,
+	}
+
+	// MLDSA87 is the ML-DSA-87 parameter set, implemented by package mldsa87.
+	MLDSA87 Scheme = &scheme[*mldsa87.PublicKey, *mldsa87.PrivateKey]{
+		name:                   "ML-DSA-87",
+		oid:                    asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 19},
+		publicKeySize:          mldsa87.PublicKeySize,
+		privateKeySize:         mldsa87.PrivateKeySize,
+		seedSize:               mldsa87.SeedSize,
+		signatureSize:          mldsa87.SignatureSize,
+		generateKeyPair:        mldsa87.GenerateKeyPair,
+		publicKeyFromBytes:     mldsa87.PublicKeyFromBytes,
+		privateKeyFromSeed:     mldsa87.PrivateKeyFromSeed,
+		privateKeyFromExpanded: mldsa87.PrivateKeyFromExpanded,
+	}
+)
+
+// Schemes returns all supported parameter sets, in increasing order of security.
+func Schemes() []Scheme {
+	return []Scheme{MLDSA44, MLDSA65, MLDSA87}
+}
+
+// SchemeByName returns the parameter set with the given name (e.g. "ML-DSA-65"),
+// compared case-insensitively, or nil if there is none.
+func SchemeByName(name string) Scheme {
+	for _, s := range Schemes() {
+		if strings.EqualFold(s.Name(), name) {
+			return s
+		}
+	}
+	return nil
+}
+
+// SchemeByOID returns the parameter set with the given object identifier,
+// or nil if there is none.
+func SchemeByOID(oid asn1.ObjectIdentifier) Scheme {
+	for _, s := range Schemes() {
+		if s.OID().Equal(oid) {
+			return s
+		}
+	}
+	return nil
+}
+
+// SchemeOf returns the parameter set of a public or private key from the
+// mldsa44, mldsa65 or mldsa87 packages, or nil if key is not such a key.
+func SchemeOf(key any) Scheme {
+	for _, s := range Schemes() {
+		if s.(interface{ owns(key any) bool }).owns(key) {
+			return s
+		}
+	}
+	return nil
+}
+
+var errWrongScheme = errors.New("mldsa: key does not belong to this parameter set")
+
+// scheme implements Scheme on top of the package for a single parameter set.
+type scheme[Pub PublicKey, Priv PrivateKey] struct {
+	name           string
+	oid            asn1.ObjectIdentifier
+	publicKeySize  int
+	privateKeySize int
+	seedSize       int
+	signatureSize  int
+
+	generateKeyPair        func(io.Reader) (Pub, Priv, error)
+	publicKeyFromBytes     func([]byte) (Pub, error)
+	privateKeyFromSeed     func([]byte) (Priv, error)
+	privateKeyFromExpanded func([]byte) (Priv, error)
+}
+
+func (s *scheme[Pub, Priv]) Name() string               { return s.name }
+func (s *scheme[Pub, Priv]) OID() asn1.ObjectIdentifier { return s.oid }
+func (s *scheme[Pub, Priv]) PublicKeySize() int         { return s.publicKeySize }
+func (s *scheme[Pub, Priv]) PrivateKeySize() int        { return s.privateKeySize }
+func (s *scheme[Pub, Priv]) SeedSize() int              { return s.seedSize }
+func (s *scheme[Pub, Priv]) SignatureSize() int         { return s.signatureSize }
+
+func (s *scheme[Pub, Priv]) owns(key any) bool {
+	switch key.(type) {
+	case Pub, Priv:
+		return true
+	}
+	return false
+}
+
+func (s *scheme[Pub, Priv]) GenerateKey(rand io.Reader) (PublicKey, PrivateKey, error) {
+	pub, priv, err := s.generateKeyPair(rand)
+	if err != nil {
+		return nil, nil, err
+	}
+	return pub, priv, nil
+}
+
+func (s *scheme[Pub, Priv]) PublicKeyFromBytes(b []byte) (PublicKey, error) {
+	pub, err := s.publicKeyFromBytes(b)
+	if err != nil {
+		return nil, err
+	}
+	return pub, nil
+}
+
+func (s *scheme[Pub, Priv]) PrivateKeyFromSeed(seed []byte) (PrivateKey, error) {
+	priv, err := s.privateKeyFromSeed(seed)
+	if err != nil {
+		return nil, err
+	}
+	return priv, nil
+}
+
+func (s *scheme[Pub, Priv]) PrivateKeyFromExpanded(b []byte) (PrivateKey, error) {
+	priv, err := s.privateKeyFromExpanded(b)
+	if err != nil {
+		return nil, err
+	}
+	return priv, nil
+}
+
+func (s *scheme[Pub, Priv]) Sign(priv PrivateKey, rand io.Reader, msg []byte, opts *options.Options) ([]byte, error) {
+	if _, ok := priv.(Priv); !ok {
+		return nil, errWrongScheme
+	}
+	return priv.Sign(rand, msg, opts)
+}
+
+func (s *scheme[Pub, Priv]) Verify(pub PublicKey, msg, sig []byte, opts *options.Options) bool {
+	if _, ok := pub.(Pub); !ok {
+		return false
+	}
+	return pub.VerifyWithOptions(msg, sig, opts)
+}
@@ -0,0 +1,133 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mldsa_test
+
+import (
+	"crypto/rand"
+	"encoding/asn1"
+	"fmt"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/ml-dsa/mldsa"
+	"github.com/trailofbits/ml-dsa/mldsa44"
+	options "github.com/trailofbits/ml-dsa/options"
+)
+
+func TestSchemeRoundTrip(t *testing.T) {
+	message := []byte("Hello, world!")
+	for _, s := range mldsa.Schemes() {
+		t.Run(s.Name(), func(t *testing.T) {
+			pub, priv, err := s.GenerateKey(rand.Reader)
+			assert.NoError(t, err)
+			assert.Len(t, pub.Bytes(), s.PublicKeySize())
+			assert.Len(t, priv.EncodeExpanded(), s.PrivateKeySize())
+			assert.Equal(t, s, mldsa.SchemeOf(pub))
+			assert.Equal(t, s, mldsa.SchemeOf(priv))
+			assert.Equal(t, pub, priv.Public())
+
+			opts := &options.Options{Context: "context"}
+			sig, err := s.Sign(priv, rand.Reader, message, opts)
+			assert.NoError(t, err)
+			assert.Len(t, sig, s.SignatureSize())
+			assert.True(t, s.Verify(pub, message, sig, opts))
+			assert.False(t, s.Verify(pub, message, sig, nil))
+
+			seed, err := priv.Seed()
+			assert.NoError(t, err)
+			assert.Len(t, seed, s.SeedSize())
+			priv2, err := s.PrivateKeyFromSeed(seed)
+			assert.NoError(t, err)
+			assert.Equal(t, priv.EncodeExpanded(), priv2.EncodeExpanded())
+
+			priv3, err := s.PrivateKeyFromExpanded(priv.EncodeExpanded())
+			assert.NoError(t, err)
+			assert.Equal(t, priv.EncodeExpanded(), priv3.EncodeExpanded())
+
+			pub2, err := s.PublicKeyFromBytes(pub.Bytes())
+			assert.NoError(t, err)
+			assert.True(t, s.Verify(pub2, message, sig, opts))
+
+			_, err = s.PublicKeyFromBytes(pub.Bytes()[1:])
+			assert.Error(t, err)
+			_, err = s.PrivateKeyFromSeed(seed[1:])
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSchemeRejectsOtherParameterSets(t *testing.T) {
+	message := []byte("Hello, world!")
+	pub, priv, err := mldsa.MLDSA44.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	sig, err := mldsa.MLDSA44.Sign(priv, rand.Reader, message, nil)
+	assert.NoError(t, err)
+
+	_, err = mldsa.MLDSA65.Sign(priv, rand.Reader, message, nil)
+	assert.Error(t, err)
+	assert.False(t, mldsa.MLDSA65.Verify(pub, message, sig, nil))
+	assert.Nil(t, mldsa.SchemeOf("not a key"))
+}
+
+func TestDestroy(t *testing.T) {
+	message := []byte("Hello, world!")
+	for _, s := range mldsa.Schemes() {
+		t.Run(s.Name(), func(t *testing.T) {
+			pub, priv, err := s.GenerateKey(rand.Reader)
+			assert.NoError(t, err)
+			priv.Destroy()
+
+			_, err = s.Sign(priv, rand.Reader, message, nil)
+			assert.Error(t, err)
+			_, err = priv.Seed()
+			assert.Error(t, err)
+			assert.Nil(t, priv.EncodeExpanded())
+			for _, format := range []mldsa.PrivateKeyFormat{mldsa.FormatSeed, mldsa.FormatExpanded, mldsa.FormatBoth} {
+				_, err = mldsa.MarshalPKCS8PrivateKeyWithFormat(priv, format)
+				assert.Error(t, err)
+			}
+			assert.Equal(t, pub, priv.Public())
+		})
+	}
+}
+
+func TestSchemeLookup(t *testing.T) {
+	for _, s := range mldsa.Schemes() {
+		assert.Equal(t, s, mldsa.SchemeByName(s.Name()))
+		assert.Equal(t, s, mldsa.SchemeByOID(s.OID()))
+	}
+	assert.Equal(t, mldsa.MLDSA87, mldsa.SchemeByName("ml-dsa-87"))
+	assert.Equal(t, mldsa.MLDSA65, mldsa.SchemeByOID(asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 3, 18}))
+	assert.Nil(t, mldsa.SchemeByName("ML-DSA-128"))
+	assert.Nil(t, mldsa.SchemeByOID(asn1.ObjectIdentifier{1, 2, 3}))
+
+	// Keys from a Scheme are the concrete key types of the parameter set packages
+	_, priv, err := mldsa.MLDSA44.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	assert.IsType(t, &mldsa44.PrivateKey{}, priv)
+}
+
+func ExampleSchemeByName() {
+	scheme := mldsa.SchemeByName("ML-DSA-65")
+	if scheme == nil {
+		log.Fatal("unknown parameter set")
+	}
+
+	pub, priv, err := scheme.GenerateKey(nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	msg := []byte("Hello, world!")
+	sig, err := scheme.Sign(priv, nil, msg, nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ok := scheme.Verify(pub, msg, sig, nil)
+	fmt.Println(len(sig) == scheme.SignatureSize(), ok)
+	// Output: true true
+}
@@ -0,0 +1,85 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mldsa
+
+import (
+	"encoding/pem"
+	"errors"
+	"fmt"
+)
+
+const (
+	privateKeyPEMType = "PRIVATE KEY"
+	publicKeyPEMType  = "PUBLIC KEY"
+)
+
+// MarshalPrivateKeyPEM encodes a private key as a PKCS #8 "PRIVATE KEY" PEM block.
+//
+// The seed format is used if the seed of the key is known, and the expanded format otherwise.
+// OpenSSL 3.5 and later read both, but write the seed and expanded key together by
+// default; use [MarshalPrivateKeyPEMWithFormat] with [FormatBoth] to do the same.
+func MarshalPrivateKeyPEM(priv PrivateKey) ([]byte, error) {
+	der, err := MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: privateKeyPEMType, Bytes: der}), nil
+}
+
+// MarshalPrivateKeyPEMWithFormat is like [MarshalPrivateKeyPEM], but uses the given
+// form of the ML-DSA-PrivateKey CHOICE.
+func MarshalPrivateKeyPEMWithFormat(priv PrivateKey, format PrivateKeyFormat) ([]byte, error) {
+	der, err := MarshalPKCS8PrivateKeyWithFormat(priv, format)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: privateKeyPEMType, Bytes: der}), nil
+}
+
+// MarshalPublicKeyPEM encodes a public key as a SubjectPublicKeyInfo "PUBLIC KEY" PEM block,
+// the format read and written by OpenSSL 3.5 and later.
+func MarshalPublicKeyPEM(pub PublicKey) ([]byte, error) {
+	der, err := MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: publicKeyPEMType, Bytes: der}), nil
+}
+
+// ParsePrivateKeyPEM parses the first PEM block of data, which must be a PKCS #8
+// "PRIVATE KEY" block holding an ML-DSA private key of any parameter set.
+// See [ParsePKCS8PrivateKey] for the supported private key formats.
+func ParsePrivateKeyPEM(data []byte) (PrivateKey, error) {
+	der, err := decodePEM(data, privateKeyPEMType)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePKCS8PrivateKey(der)
+}
+
+// ParsePublicKeyPEM parses the first PEM block of data, which must be a "PUBLIC KEY"
+// block holding an ML-DSA public key of any parameter set.
+func ParsePublicKeyPEM(data []byte) (PublicKey, error) {
+	der, err := decodePEM(data, publicKeyPEMType)
+	if err != nil {
+		return nil, err
+	}
+	return ParsePKIXPublicKey(der)
+}
+
+func decodePEM(data []byte, expectedType string) ([]byte, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("mldsa: no PEM block found")
+	}
+	if block.Type != expectedType {
+		return nil, fmt.Errorf("mldsa: unexpected PEM block type %q, expected %q", block.Type, expectedType)
+	}
+	if len(block.Headers) != 0 {
+		// Such as the legacy "Proc-Type: 4,ENCRYPTED" header
+		return nil, errors.New("mldsa: unsupported PEM headers")
+	}
+	return block.Bytes, nil
+}
@@ -0,0 +1,223 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mldsa
+
+import (
+	"crypto/subtle"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/cryptobyte"
+	cbasn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// PrivateKeyFormat selects the form of the ML-DSA-PrivateKey CHOICE used in a PKCS #8
+// private key, as defined in RFC 9881:
+//
+//	ML-DSA-PrivateKey ::= CHOICE {
+//	  seed [0] OCTET STRING (SIZE (32)),
+//	  expandedKey OCTET STRING,
+//	  both SEQUENCE {
+//	      seed OCTET STRING (SIZE (32)),
+//	      expandedKey OCTET STRING
+//	      }
+//	  }
+type PrivateKeyFormat int
+
+const (
+	// FormatSeed stores only the 32-byte seed. This is the recommended format.
+	FormatSeed PrivateKeyFormat = iota
+	// FormatExpanded stores only the expanded private key defined in FIPS 204.
+	FormatExpanded
+	// FormatBoth stores both the seed and the expanded private key.
+	FormatBoth
+)
+
+// MarshalPKCS8PrivateKey converts a private key to PKCS #8, ASN.1 DER form.
+//
+// The seed format is used if the seed of the key is known, and the expanded format otherwise.
+func MarshalPKCS8PrivateKey(priv PrivateKey) ([]byte, error) {
+	if _, err := priv.Seed(); err != nil {
+		return MarshalPKCS8PrivateKeyWithFormat(priv, FormatExpanded)
+	}
+	return MarshalPKCS8PrivateKeyWithFormat(priv, FormatSeed)
+}
+
+// MarshalPKCS8PrivateKeyWithFormat converts a private key to PKCS #8, ASN.1 DER form,
+// using the given form of the ML-DSA-PrivateKey CHOICE.
+//
+// Returns an error if the format requires the seed, and the seed of the key is not known.
+func MarshalPKCS8PrivateKeyWithFormat(priv PrivateKey, format PrivateKeyFormat) ([]byte, error) {
+	scheme := SchemeOf(priv)
+	if scheme == nil {
+		return nil, errors.New("mldsa: unsupported private key type")
+	}
+
+	expanded := priv.EncodeExpanded()
+	if expanded == nil {
+		return nil, errors.New("mldsa: private key has been destroyed")
+	}
+	defer clear(expanded)
+
+	var key cryptobyte.Builder
+	switch format {
+	case FormatSeed:
+		seed, err := priv.Seed()
+		if err != nil {
+			return nil, err
+		}
+		key.AddASN1(cbasn1.Tag(0).ContextSpecific(), func(b *cryptobyte.Builder) {
+			b.AddBytes(seed)
+		})
+	case FormatExpanded:
+		key.AddASN1OctetString(expanded)
+	case FormatBoth:
+		seed, err := priv.Seed()
+		if err != nil {
+			return nil, err
+		}
+		key.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			b.AddASN1OctetString(seed)
+			b.AddASN1OctetString(expanded)
+		})
+	default:
+		return nil, errors.New("mldsa: unknown private key format")
+	}
+	privateKey, err := key.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	var b cryptobyte.Builder
+	b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1Int64(0) // version
+		addAlgorithmIdentifier(b, scheme.OID())
+		b.AddASN1OctetString(privateKey)
+	})
+	return b.Bytes()
+}
+
+// ParsePKCS8PrivateKey parses an ML-DSA private key in PKCS #8, ASN.1 DER form.
+// The parameter set is determined by the algorithm identifier.
+//
+// All three forms of the ML-DSA-PrivateKey CHOICE are supported. For the "both" form,
+// the seed and the expanded key must be consistent.
+// Note that the expanded form should only be parsed from trusted sources.
+func ParsePKCS8PrivateKey(der []byte) (PrivateKey, error) {
+	var version int64
+	var oid asn1.ObjectIdentifier
+	var parameters cryptobyte.String
+	var privateKey cryptobyte.String
+
+	input := cryptobyte.String(der)
+	var oneAsymmetricKey cryptobyte.String
+	if !input.ReadASN1(&oneAsymmetricKey, cbasn1.SEQUENCE) || !input.Empty() ||
+		!oneAsymmetricKey.ReadASN1Int64WithTag(&version, cbasn1.INTEGER) ||
+		!readAlgorithmIdentifier(&oneAsymmetricKey, &oid, &parameters) ||
+		!oneAsymmetricKey.ReadASN1(&privateKey, cbasn1.OCTET_STRING) {
+		return nil, errors.New("mldsa: malformed PKCS #8 private key")
+	}
+	if version != 0 && version != 1 {
+		return nil, errors.New("mldsa: unsupported PKCS #8 version")
+	}
+
+	// Skip the optional attributes, but keep the optional public key to check it.
+	var publicKey cryptobyte.String
+	var hasPublicKey bool
+	if !oneAsymmetricKey.SkipOptionalASN1(cbasn1.Tag(0).Constructed().ContextSpecific()) ||
+		!oneAsymmetricKey.ReadOptionalASN1(&publicKey, &hasPublicKey, cbasn1.Tag(1).ContextSpecific()) ||
+		!oneAsymmetricKey.Empty() {
+		return nil, errors.New("mldsa: malformed PKCS #8 private key")
+	}
+	// RFC 5958 requires version 2 (v2) when the public key is present
+	if hasPublicKey && version != 1 {
+		return nil, errors.New("mldsa: PKCS #8 private key with a public key must be version 2")
+	}
+
+	scheme := SchemeByOID(oid)
+	if scheme == nil {
+		return nil, fmt.Errorf("mldsa: PKCS #8 private key is not an ML-DSA key (algorithm %s)", oid)
+	}
+	if !parameters.Empty() {
+		return nil, errors.New("mldsa: malformed PKCS #8 private key")
+	}
+
+	priv, err := parsePrivateKeyChoice(scheme, privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if hasPublicKey {
+		// The public key is an IMPLICIT BIT STRING, with a leading byte for the unused bits
+		pub := priv.Public().(PublicKey).Bytes()
+		if len(publicKey) != len(pub)+1 || publicKey[0] != 0 ||
+			subtle.ConstantTimeCompare(publicKey[1:], pub) != 1 {
+			return nil, errors.New("mldsa: PKCS #8 public key does not match the private key")
+		}
+	}
+
+	return priv, nil
+}
+
+func parsePrivateKeyChoice(scheme Scheme, input cryptobyte.String) (PrivateKey, error) {
+	var tag cbasn1.Tag
+	var value cryptobyte.String
+	if !input.ReadAnyASN1(&value, &tag) || !input.Empty() {
+		return nil, errors.New("mldsa: malformed ML-DSA private key")
+	}
+
+	switch tag {
+	case cbasn1.Tag(0).ContextSpecific():
+		return scheme.PrivateKeyFromSeed(value)
+	case cbasn1.OCTET_STRING:
+		return scheme.PrivateKeyFromExpanded(value)
+	case cbasn1.SEQUENCE:
+		var seed, expanded cryptobyte.String
+		if !value.ReadASN1(&seed, cbasn1.OCTET_STRING) ||
+			!value.ReadASN1(&expanded, cbasn1.OCTET_STRING) || !value.Empty() {
+			return nil, errors.New("mldsa: malformed ML-DSA private key")
+		}
+		priv, err := scheme.PrivateKeyFromSeed(seed)
+		if err != nil {
+			return nil, err
+		}
+		// SkDecode validates the expanded key on its own, before comparing it to the seed.
+		decoded, err := scheme.PrivateKeyFromExpanded(expanded)
+		if err != nil {
+			priv.Destroy()
+			return nil, err
+		}
+		decoded.Destroy()
+		encoded := priv.EncodeExpanded()
+		consistent := subtle.ConstantTimeCompare(encoded, expanded) == 1
+		clear(encoded)
+		if !consistent {
+			priv.Destroy()
+			return nil, errors.New("mldsa: seed and expanded private key are inconsistent")
+		}
+		return priv, nil
+	default:
+		return nil, errors.New("mldsa: malformed ML-DSA private key")
+	}
+}
+
+// AlgorithmIdentifier with absent parameters, as required for ML-DSA by RFC 9881.
+func addAlgorithmIdentifier(b *cryptobyte.Builder, oid asn1.ObjectIdentifier) {
+	b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1ObjectIdentifier(oid)
+	})
+}
+
+// readAlgorithmIdentifier accepts any parameters, so that keys of other algorithms can be
+// reported as such. The caller must check that parameters is empty for ML-DSA.
+func readAlgorithmIdentifier(input *cryptobyte.String, oid *asn1.ObjectIdentifier, parameters *cryptobyte.String) bool {
+	var algorithm cryptobyte.String
+	if !input.ReadASN1(&algorithm, cbasn1.SEQUENCE) || !algorithm.ReadASN1ObjectIdentifier(oid) {
+		return false
+	}
+	*parameters = algorithm
+	return true
+}
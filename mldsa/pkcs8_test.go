@@ -0,0 +1,209 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mldsa_test
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/pem"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trailofbits/ml-dsa/mldsa"
+	"golang.org/x/crypto/cryptobyte"
+	cbasn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// The files in testdata are the examples from RFC 9881, Appendix C.
+// All example private keys are derived from the seed 000102...1f.
+const rfc9881Seed = "000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f"
+
+func readPEM(t *testing.T, name string) []byte {
+	data, err := os.ReadFile("testdata/" + name)
+	assert.NoError(t, err)
+	block, _ := pem.Decode(data)
+	if block == nil {
+		t.Fatalf("no PEM block in %s", name)
+	}
+	return block.Bytes
+}
+
+func TestParsePKCS8PrivateKeyRFC9881(t *testing.T) {
+	tests := []struct {
+		file   string
+		scheme mldsa.Scheme
+		format mldsa.PrivateKeyFormat
+	}{
+		{"ml-dsa-44-seed.priv.pem", mldsa.MLDSA44, mldsa.FormatSeed},
+		{"ml-dsa-44-expanded.priv.pem", mldsa.MLDSA44, mldsa.FormatExpanded},
+		{"ml-dsa-44-both.priv.pem", mldsa.MLDSA44, mldsa.FormatBoth},
+		{"ml-dsa-65-seed.priv.pem", mldsa.MLDSA65, mldsa.FormatSeed},
+		{"ml-dsa-87-seed.priv.pem", mldsa.MLDSA87, mldsa.FormatSeed},
+	}
+	for _, test := range tests {
+		t.Run(test.file, func(t *testing.T) {
+			der := readPEM(t, test.file)
+			priv, err := mldsa.ParsePKCS8PrivateKey(der)
+			assert.NoError(t, err)
+			assert.Equal(t, test.scheme, mldsa.SchemeOf(priv))
+
+			expected, err := test.scheme.PrivateKeyFromSeed(hexDecode(t, rfc9881Seed))
+			assert.NoError(t, err)
+			assert.Equal(t, expected.EncodeExpanded(), priv.EncodeExpanded())
+
+			// Keys parsed from the seed or both forms are re-encoded identically
+			if test.format != mldsa.FormatExpanded {
+				enc, err := mldsa.MarshalPKCS8PrivateKeyWithFormat(priv, test.format)
+				assert.NoError(t, err)
+				assert.Equal(t, der, enc)
+			}
+		})
+	}
+}
+
+func TestMarshalPKCS8PrivateKey(t *testing.T) {
+	for _, s := range mldsa.Schemes() {
+		t.Run(s.Name(), func(t *testing.T) {
+			_, priv, err := s.GenerateKey(rand.Reader)
+			assert.NoError(t, err)
+
+			for _, format := range []mldsa.PrivateKeyFormat{mldsa.FormatSeed, mldsa.FormatExpanded, mldsa.FormatBoth} {
+				der, err := mldsa.MarshalPKCS8PrivateKeyWithFormat(priv, format)
+				assert.NoError(t, err)
+				parsed, err := mldsa.ParsePKCS8PrivateKey(der)
+				assert.NoError(t, err)
+				assert.Equal(t, priv.EncodeExpanded(), parsed.EncodeExpanded())
+			}
+
+			// The default is the seed format
+			der, err := mldsa.MarshalPKCS8PrivateKey(priv)
+			assert.NoError(t, err)
+			seedDer, err := mldsa.MarshalPKCS8PrivateKeyWithFormat(priv, mldsa.FormatSeed)
+			assert.NoError(t, err)
+			assert.Equal(t, seedDer, der)
+
+			// Keys without a seed fall back to the expanded format
+			expanded, err := s.PrivateKeyFromExpanded(priv.EncodeExpanded())
+			assert.NoError(t, err)
+			der, err = mldsa.MarshalPKCS8PrivateKey(expanded)
+			assert.NoError(t, err)
+			expandedDer, err := mldsa.MarshalPKCS8PrivateKeyWithFormat(priv, mldsa.FormatExpanded)
+			assert.NoError(t, err)
+			assert.Equal(t, expandedDer, der)
+			_, err = mldsa.MarshalPKCS8PrivateKeyWithFormat(expanded, mldsa.FormatBoth)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParsePKCS8PrivateKeyInconsistent(t *testing.T) {
+	_, priv1, err := mldsa.MLDSA44.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	_, priv2, err := mldsa.MLDSA44.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+	seed, err := priv1.Seed()
+	assert.NoError(t, err)
+
+	// Seed from one key, expanded key from the other
+	var key cryptobyte.Builder
+	key.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1OctetString(seed)
+		b.AddASN1OctetString(priv2.EncodeExpanded())
+	})
+	der := pkcs8(t, mldsa.MLDSA44, key.BytesOrPanic())
+	_, err = mldsa.ParsePKCS8PrivateKey(der)
+	assert.Error(t, err)
+
+	// Seed with the wrong length
+	key = cryptobyte.Builder{}
+	key.AddASN1(cbasn1.Tag(0).ContextSpecific(), func(b *cryptobyte.Builder) {
+		b.AddBytes(seed[1:])
+	})
+	_, err = mldsa.ParsePKCS8PrivateKey(pkcs8(t, mldsa.MLDSA44, key.BytesOrPanic()))
+	assert.Error(t, err)
+
+	// Expanded key for the wrong parameter set
+	key = cryptobyte.Builder{}
+	key.AddASN1OctetString(priv1.EncodeExpanded())
+	_, err = mldsa.ParsePKCS8PrivateKey(pkcs8(t, mldsa.MLDSA65, key.BytesOrPanic()))
+	assert.Error(t, err)
+}
+
+// A OneAsymmetricKey with a publicKey field must be version 2, and its public
+// key must match the private key.
+func TestParsePKCS8PrivateKeyPublicKey(t *testing.T) {
+	pub, priv, err := mldsa.MLDSA44.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	otherPub, _, err := mldsa.MLDSA44.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	seed, err := priv.Seed()
+	require.NoError(t, err)
+
+	withPublicKey := func(version int64, pub mldsa.PublicKey) []byte {
+		var b cryptobyte.Builder
+		b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			b.AddASN1Int64(version)
+			b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+				b.AddASN1ObjectIdentifier(mldsa.MLDSA44.OID())
+			})
+			b.AddASN1(cbasn1.OCTET_STRING, func(b *cryptobyte.Builder) {
+				b.AddASN1(cbasn1.Tag(0).ContextSpecific(), func(b *cryptobyte.Builder) {
+					b.AddBytes(seed)
+				})
+			})
+			b.AddASN1(cbasn1.Tag(1).ContextSpecific(), func(b *cryptobyte.Builder) {
+				b.AddUint8(0) // unused bits
+				b.AddBytes(pub.Bytes())
+			})
+		})
+		der, err := b.Bytes()
+		require.NoError(t, err)
+		return der
+	}
+
+	parsed, err := mldsa.ParsePKCS8PrivateKey(withPublicKey(1, pub))
+	assert.NoError(t, err)
+	assert.Equal(t, priv.EncodeExpanded(), parsed.EncodeExpanded())
+
+	_, err = mldsa.ParsePKCS8PrivateKey(withPublicKey(0, pub))
+	assert.ErrorContains(t, err, "version 2")
+	_, err = mldsa.ParsePKCS8PrivateKey(withPublicKey(1, otherPub))
+	assert.ErrorContains(t, err, "does not match")
+}
+
+func TestParsePKCS8PrivateKeyMalformed(t *testing.T) {
+	der := readPEM(t, "ml-dsa-44-seed.priv.pem")
+	_, err := mldsa.ParsePKCS8PrivateKey(der[:len(der)-1])
+	assert.Error(t, err)
+	_, err = mldsa.ParsePKCS8PrivateKey(append(der, 0))
+	assert.Error(t, err)
+
+	// Ed25519 key from RFC 8410, Section 10.3
+	ed25519 := hexDecode(t, "302e020100300506032b657004220420d4ee72dbf913584ad5b6d8f1f769f8ad3afe7c28cbf1d4fbe097a88f44755842")
+	_, err = mldsa.ParsePKCS8PrivateKey(ed25519)
+	assert.ErrorContains(t, err, "not an ML-DSA key")
+}
+
+func pkcs8(t *testing.T, s mldsa.Scheme, privateKey []byte) []byte {
+	var b cryptobyte.Builder
+	b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1Int64(0)
+		b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			b.AddASN1ObjectIdentifier(s.OID())
+		})
+		b.AddASN1OctetString(privateKey)
+	})
+	der, err := b.Bytes()
+	assert.NoError(t, err)
+	return der
+}
+
+func hexDecode(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(s)
+	assert.NoError(t, err)
+	return b
+}
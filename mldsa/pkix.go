@@ -0,0 +1,60 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mldsa
+
+import (
+	"encoding/asn1"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/cryptobyte"
+	cbasn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// MarshalPKIXPublicKey converts a public key to PKIX, ASN.1 DER form,
+// as defined in RFC 9881. The encoded public key is a SubjectPublicKeyInfo structure
+// (see RFC 5280, Section 4.1), with the raw public key defined in FIPS 204
+// as the subjectPublicKey.
+func MarshalPKIXPublicKey(pub PublicKey) ([]byte, error) {
+	scheme := SchemeOf(pub)
+	if scheme == nil {
+		return nil, errors.New("mldsa: unsupported public key type")
+	}
+
+	var b cryptobyte.Builder
+	b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		addAlgorithmIdentifier(b, scheme.OID())
+		b.AddASN1BitString(pub.Bytes())
+	})
+	return b.Bytes()
+}
+
+// ParsePKIXPublicKey parses an ML-DSA public key in PKIX, ASN.1 DER form.
+// The parameter set is determined by the algorithm identifier, and the
+// public key must have the size of that parameter set.
+func ParsePKIXPublicKey(der []byte) (PublicKey, error) {
+	input := cryptobyte.String(der)
+	var spki cryptobyte.String
+	var oid asn1.ObjectIdentifier
+	var parameters cryptobyte.String
+	var subjectPublicKey asn1.BitString
+	if !input.ReadASN1(&spki, cbasn1.SEQUENCE) || !input.Empty() ||
+		!readAlgorithmIdentifier(&spki, &oid, &parameters) ||
+		!spki.ReadASN1BitString(&subjectPublicKey) || !spki.Empty() {
+		return nil, errors.New("mldsa: malformed PKIX public key")
+	}
+
+	scheme := SchemeByOID(oid)
+	if scheme == nil {
+		return nil, fmt.Errorf("mldsa: PKIX public key is not an ML-DSA key (algorithm %s)", oid)
+	}
+	if !parameters.Empty() {
+		return nil, errors.New("mldsa: malformed PKIX public key")
+	}
+	if subjectPublicKey.BitLength%8 != 0 {
+		return nil, errors.New("mldsa: malformed PKIX public key")
+	}
+	return scheme.PublicKeyFromBytes(subjectPublicKey.Bytes)
+}
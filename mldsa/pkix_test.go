@@ -0,0 +1,94 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package mldsa_test
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/trailofbits/ml-dsa/mldsa"
+	"golang.org/x/crypto/cryptobyte"
+	cbasn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+func TestParsePKIXPublicKeyRFC9881(t *testing.T) {
+	tests := []struct {
+		file   string
+		scheme mldsa.Scheme
+	}{
+		{"ml-dsa-44.pub.pem", mldsa.MLDSA44},
+		{"ml-dsa-65.pub.pem", mldsa.MLDSA65},
+		{"ml-dsa-87.pub.pem", mldsa.MLDSA87},
+	}
+	for _, test := range tests {
+		t.Run(test.file, func(t *testing.T) {
+			der := readPEM(t, test.file)
+			pub, err := mldsa.ParsePKIXPublicKey(der)
+			assert.NoError(t, err)
+			assert.Equal(t, test.scheme, mldsa.SchemeOf(pub))
+
+			priv, err := test.scheme.PrivateKeyFromSeed(hexDecode(t, rfc9881Seed))
+			assert.NoError(t, err)
+			assert.Equal(t, priv.Public(), pub)
+
+			enc, err := mldsa.MarshalPKIXPublicKey(pub)
+			assert.NoError(t, err)
+			assert.Equal(t, der, enc)
+		})
+	}
+}
+
+func TestMarshalPKIXPublicKey(t *testing.T) {
+	for _, s := range mldsa.Schemes() {
+		t.Run(s.Name(), func(t *testing.T) {
+			pub, _, err := s.GenerateKey(rand.Reader)
+			assert.NoError(t, err)
+			der, err := mldsa.MarshalPKIXPublicKey(pub)
+			assert.NoError(t, err)
+			parsed, err := mldsa.ParsePKIXPublicKey(der)
+			assert.NoError(t, err)
+			assert.Equal(t, pub, parsed)
+		})
+	}
+
+	_, err := mldsa.MarshalPKIXPublicKey(nil)
+	assert.Error(t, err)
+}
+
+func TestParsePKIXPublicKeyMalformed(t *testing.T) {
+	pub, _, err := mldsa.MLDSA44.GenerateKey(rand.Reader)
+	assert.NoError(t, err)
+
+	// Public key for the wrong parameter set
+	_, err = mldsa.ParsePKIXPublicKey(spki(t, mldsa.MLDSA65, pub.Bytes()))
+	assert.Error(t, err)
+	// Truncated public key
+	_, err = mldsa.ParsePKIXPublicKey(spki(t, mldsa.MLDSA44, pub.Bytes()[1:]))
+	assert.Error(t, err)
+	// Trailing data
+	der, err := mldsa.MarshalPKIXPublicKey(pub)
+	assert.NoError(t, err)
+	_, err = mldsa.ParsePKIXPublicKey(append(der, 0))
+	assert.Error(t, err)
+
+	// Ed25519 key from RFC 8410, Section 10.1
+	ed25519 := hexDecode(t, "302a300506032b657003210019bf44096984cdfe8541bac167dc3b96c85086aa30b6b6cb0c5c38ad703166e1")
+	_, err = mldsa.ParsePKIXPublicKey(ed25519)
+	assert.ErrorContains(t, err, "not an ML-DSA key")
+}
+
+func spki(t *testing.T, s mldsa.Scheme, publicKey []byte) []byte {
+	var b cryptobyte.Builder
+	b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			b.AddASN1ObjectIdentifier(s.OID())
+		})
+		b.AddASN1BitString(publicKey)
+	})
+	der, err := b.Bytes()
+	assert.NoError(t, err)
+	return der
+}
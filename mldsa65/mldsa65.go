@@ -19,6 +19,17 @@ import (
 
 // Package mldsa65 implements the ML-DSA-65 parameter set of the ML-DSA algorithm.
 
+const (
+	// PublicKeySize is the size in bytes of an encoded public key.
+	PublicKeySize = 1952
+	// PrivateKeySize is the size in bytes of an expanded private key.
+	PrivateKeySize = 4032
+	// SeedSize is the size in bytes of a private key seed.
+	SeedSize = 32
+	// SignatureSize is the size in bytes of a signature.
+	SignatureSize = 3309
+)
+
 // PublicKey is the type of ML-DSA public keys. Implements [crypto.PublicKey].
 type PublicKey struct {
 	pk internal.VerifyingKey
@@ -26,7 +37,19 @@ type PublicKey struct {
 
 // PrivateKey is the type of ML-DSA private keys. It implements [crypto.Signer].
 type PrivateKey struct {
-	sk internal.SigningKey
+	sk *internal.SigningKey
+}
+
+// StreamSigner signs a message that is written to it incrementally. It implements [io.Writer].
+// Use it instead of Sign for messages too large to hold in memory.
+type StreamSigner struct {
+	s *internal.StreamSigner
+}
+
+// StreamVerifier verifies a signature over a message that is written to it incrementally.
+// It implements [io.Writer].
+type StreamVerifier struct {
+	v *internal.StreamVerifier
 }
 
 // GenerateKeyPair generates a key pair for the ML-DSA algorithm.
@@ -39,20 +62,77 @@ func GenerateKeyPair(rng io.Reader) (*PublicKey, *PrivateKey, error) {
 	if err != nil {
 		return nil, nil, err
 	}
-	return &PublicKey{*pk}, &PrivateKey{*sk}, nil
+	return &PublicKey{*pk}, &PrivateKey{sk}, nil
 }
 
+// Verify verifies a pure ML-DSA signature with an empty context.
 func (pub *PublicKey) Verify(msg, sig []byte) bool {
 	return pub.pk.Verify(msg, sig, nil)
 }
 
+// VerifyWithOptions verifies a signature using the context and pre-hash function in opts.
+// If opts.Hash or opts.SHAKE is non-zero, msg must be the digest of the actual message,
+// as computed by [options.Options.PreHash].
 func (pub *PublicKey) VerifyWithOptions(msg, sig []byte, opts *options.Options) bool {
 	return pub.pk.Verify(msg, sig, opts)
 }
 
-// Public returns the public key corresponding to the ML-DSA private key.
+// Precompute caches the values that are derived from pub for every verification:
+// the hash tr of the public key, the matrix A expanded from rho, and the NTT form of t1.
+// Subsequent verifications with pub only do the work that depends on the signature.
+//
+// For ML-DSA-65, the cache takes 36 KiB of memory in addition to the key:
+// 30 KiB for the 6x5 matrix A, and 6 KiB for t1.
+// The cache is freed with pub.
+//
+// Precompute must not be called concurrently with other methods of pub; call it
+// before sharing pub between goroutines.
+func (pub *PublicKey) Precompute() {
+	pub.pk.Precompute()
+}
+
+// VerifyMu verifies a signature over a 64-byte message representative mu,
+// as computed by [ComputeMu].
+func (pub *PublicKey) VerifyMu(mu, sig []byte) bool {
+	return pub.pk.VerifyMu(mu, sig)
+}
+
+// ComputeMu computes the 64-byte message representative mu of a pure ML-DSA
+// signature over msg with context ctx, for use with SignMu and VerifyMu.
+// This allows the holder of a large message to send only mu to a remote signer.
+// Returns an error if ctx is longer than 255 bytes.
+func ComputeMu(pub *PublicKey, msg []byte, ctx string) ([]byte, error) {
+	return pub.pk.ComputeMu(msg, &options.Options{Context: ctx})
+}
+
+// NewStreamVerifier returns a [StreamVerifier] for pure ML-DSA signatures with context ctx.
+// Returns an error if ctx is longer than 255 bytes.
+func (pub *PublicKey) NewStreamVerifier(ctx string) (*StreamVerifier, error) {
+	v, err := pub.pk.NewStreamVerifier([]byte(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return &StreamVerifier{v}, nil
+}
+
+// Write absorbs more of the message. It never returns an error.
+func (v *StreamVerifier) Write(p []byte) (int, error) {
+	return v.v.Write(p)
+}
+
+// Reset discards the message written so far.
+func (v *StreamVerifier) Reset() {
+	v.v.Reset()
+}
+
+// Verify reports whether sig is a valid signature over the message written so far.
+func (v *StreamVerifier) Verify(sig []byte) bool {
+	return v.v.Verify(sig)
+}
+
+// Public returns the public key corresponding to the ML-DSA private key, as a *PublicKey.
 func (priv *PrivateKey) Public() crypto.PublicKey {
-	return priv.sk.Public()
+	return &PublicKey{*priv.sk.Public()}
 }
 
 // Returns the 1952-byte public key as defined in FIPS 204.
@@ -72,14 +152,84 @@ func PublicKeyFromBytes(bytes []byte) (*PublicKey, error) {
 // Signs the given message with priv. If rand is nil, [crypto/rand] is used.
 // For deterministic signing, you may explicitly pass in a reader that always returns zeros.
 //
-// Only pure ML-DSA is supported. opts.HashFunc() must return 0.
-// opts may be nil, in which case empty context is used.
+// If opts.HashFunc() returns 0 and opts does not select a SHAKE pre-hash function with
+// [options.Options.SHAKE], pure ML-DSA is used. Otherwise, HashML-DSA is used, and message
+// must be the digest of the actual message, as computed by [options.Options.PreHash].
+// opts may be nil, in which case pure ML-DSA with an empty context is used.
+//
+// To protect against fault attacks, set the FaultProtection field of an [options.Options].
+// Sign then returns [options.ErrFaultDetected] instead of a signature that fails its check.
 //
 // [crypto/rand]: https://pkg.go.dev/crypto/rand
 func (priv *PrivateKey) Sign(rand io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
 	return priv.sk.Sign(rand, message, opts)
 }
 
+// SignTo is like Sign, but appends the signature to dst and returns the extended slice.
+// If dst has a spare capacity of at least SignatureSize bytes, SignTo does not allocate
+// memory, which makes it suitable for signing in a loop with a reused buffer.
+//
+// Verify and VerifyWithOptions do not allocate memory either.
+func (priv *PrivateKey) SignTo(dst []byte, rand io.Reader, message []byte, opts crypto.SignerOpts) ([]byte, error) {
+	return priv.sk.SignTo(dst, rand, message, opts)
+}
+
+// Precompute caches the values that are derived from priv for every signature:
+// the NTT forms of the secret vectors s1, s2 and t0, and the matrix A expanded from rho.
+// Subsequent signatures with priv skip these computations, which are a significant
+// part of the cost of signing.
+//
+// For ML-DSA-65, the cache takes 47 KiB of memory in addition to the key:
+// 30 KiB for the 6x5 matrix A, and 17 KiB for the secret vectors.
+// The cache is freed with priv.
+//
+// Precompute must not be called concurrently with other methods of priv; call it
+// before sharing priv between goroutines.
+func (priv *PrivateKey) Precompute() {
+	priv.sk.Precompute()
+}
+
+// SignMu signs a 64-byte message representative mu, as computed by [ComputeMu].
+// If rand is nil, [crypto/rand] is used.
+// Returns an error if mu is not 64 bytes long.
+//
+// SignMu does not apply [options.FaultProtection]: to detect faults, check the
+// signature with [PublicKey.VerifyMu].
+//
+// [crypto/rand]: https://pkg.go.dev/crypto/rand
+func (priv *PrivateKey) SignMu(rand io.Reader, mu []byte) ([]byte, error) {
+	return priv.sk.SignMu(rand, mu)
+}
+
+// NewStreamSigner returns a [StreamSigner] producing pure ML-DSA signatures with context ctx.
+// The signatures are identical to those of Sign with the same context.
+// Returns an error if ctx is longer than 255 bytes.
+func (priv *PrivateKey) NewStreamSigner(ctx string) (*StreamSigner, error) {
+	s, err := priv.sk.NewStreamSigner([]byte(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return &StreamSigner{s}, nil
+}
+
+// Write absorbs more of the message. It never returns an error.
+func (s *StreamSigner) Write(p []byte) (int, error) {
+	return s.s.Write(p)
+}
+
+// Reset discards the message written so far.
+func (s *StreamSigner) Reset() {
+	s.s.Reset()
+}
+
+// Sign returns a signature over the message written so far. More data may be written afterwards.
+// If rand is nil, [crypto/rand] is used. Sign does not apply [options.FaultProtection].
+//
+// [crypto/rand]: https://pkg.go.dev/crypto/rand
+func (s *StreamSigner) Sign(rand io.Reader) ([]byte, error) {
+	return s.s.Sign(rand)
+}
+
 // Returns the seed used to generate the private key.
 // This is the recommended way to store the private key.
 // Note that this is not the fully expanded private key defined in FIPS 204.
@@ -88,6 +238,17 @@ func (priv *PrivateKey) Seed() ([]byte, error) {
 	return priv.sk.Bytes()
 }
 
+// Destroy overwrites the secret values of priv with zeros. Afterwards, Sign, SignTo,
+// SignMu, Seed and signers created by NewStreamSigner return an error, and
+// EncodeExpanded returns nil. Public still returns the public key.
+//
+// Zeroization is best-effort: the Go runtime may have left copies of secret values
+// in memory that is not reachable from priv. Destroy must not be called concurrently
+// with other methods of priv.
+func (priv *PrivateKey) Destroy() {
+	priv.sk.Destroy()
+}
+
 // Reads a private key from a 32-byte seed.
 // Returns an error if the seed is not 32 bytes.
 func PrivateKeyFromSeed(seed []byte) (*PrivateKey, error) {
@@ -95,7 +256,7 @@ func PrivateKeyFromSeed(seed []byte) (*PrivateKey, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &PrivateKey{*sk}, nil
+	return &PrivateKey{sk}, nil
 }
 
 // Returns the expanded 4032-byte private key defined in FIPS 204.
@@ -111,5 +272,5 @@ func PrivateKeyFromExpanded(expanded []byte) (*PrivateKey, error) {
 	if err != nil {
 		return nil, err
 	}
-	return &PrivateKey{*sk}, nil
+	return &PrivateKey{sk}, nil
 }
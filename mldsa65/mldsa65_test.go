@@ -5,8 +5,12 @@
 package mldsa65_test
 
 import (
+	"crypto"
+	"crypto/sha256"
 	"fmt"
+	"io"
 	"log"
+	"strings"
 
 	mldsa65 "github.com/trailofbits/ml-dsa/mldsa65"
 	options "github.com/trailofbits/ml-dsa/options"
@@ -47,3 +51,89 @@ func ExamplePublicKey_VerifyWithOptions() {
 	fmt.Println(ok)
 	// Output: true
 }
+
+func ExamplePrivateKey_Sign_preHash() {
+	pub, priv, err := mldsa65.GenerateKeyPair(nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	digest := sha256.Sum256([]byte("Hello, world!"))
+	opts := &options.Options{Hash: crypto.SHA256, Context: "test"}
+
+	sig, err := priv.Sign(nil, digest[:], opts)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ok := pub.VerifyWithOptions(digest[:], sig, opts)
+	fmt.Println(ok)
+	// Output: true
+}
+
+func ExampleComputeMu() {
+	pub, priv, err := mldsa65.GenerateKeyPair(nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// The holder of the message computes mu...
+	mu, err := mldsa65.ComputeMu(pub, []byte("Hello, world!"), "test")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// ...and only sends mu to the signer.
+	sig, err := priv.SignMu(nil, mu)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ok := pub.VerifyWithOptions([]byte("Hello, world!"), sig, &options.Options{Context: "test"})
+	fmt.Println(ok)
+	// Output: true
+}
+
+func ExamplePrivateKey_NewStreamSigner() {
+	pub, priv, err := mldsa65.GenerateKeyPair(nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	signer, err := priv.NewStreamSigner("test")
+	if err != nil {
+		log.Fatal(err)
+	}
+	// Any io.Reader, such as an *os.File, can be streamed into the signer.
+	if _, err := io.Copy(signer, strings.NewReader("Hello, world!")); err != nil {
+		log.Fatal(err)
+	}
+	sig, err := signer.Sign(nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	ok := pub.VerifyWithOptions([]byte("Hello, world!"), sig, &options.Options{Context: "test"})
+	fmt.Println(ok)
+	// Output: true
+}
+
+func ExamplePrivateKey_SignTo() {
+	pub, priv, err := mldsa65.GenerateKeyPair(nil)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// A single buffer is reused for every signature
+	sig := make([]byte, 0, mldsa65.SignatureSize)
+	for _, msg := range []string{"first", "second"} {
+		sig, err = priv.SignTo(sig[:0], nil, []byte(msg), nil)
+		if err != nil {
+			log.Fatal(err)
+		}
+		fmt.Println(pub.Verify([]byte(msg), sig))
+	}
+	// Output:
+	// true
+	// true
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package openpgp
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ASCII armor block types.
+const (
+	BlockSignature = "PGP SIGNATURE"
+	BlockPublicKey = "PGP PUBLIC KEY BLOCK"
+)
+
+// armorWidth is the line length of the base64 body, the maximum of RFC 9580.
+const armorWidth = 76
+
+// Armor returns data in ASCII armor with the block type blockType, e.g.
+// BlockSignature. It omits the optional CRC-24 checksum, as RFC 9580 recommends.
+func Armor(blockType string, data []byte) []byte {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	var b strings.Builder
+	b.WriteString("-----BEGIN " + blockType + "-----\n\n")
+	for len(encoded) > armorWidth {
+		b.WriteString(encoded[:armorWidth] + "\n")
+		encoded = encoded[armorWidth:]
+	}
+	b.WriteString(encoded + "\n")
+	b.WriteString("-----END " + blockType + "-----\n")
+	return []byte(b.String())
+}
+
+// Unarmor decodes the first ASCII armor block of data, and returns its block
+// type and contents. Armor headers and the checksum are ignored.
+func Unarmor(data []byte) (blockType string, body []byte, err error) {
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	i := 0
+	for i < len(lines) && !strings.HasPrefix(lines[i], "-----BEGIN ") {
+		i++
+	}
+	if i == len(lines) {
+		return "", nil, errors.New("openpgp: missing armor header")
+	}
+	blockType, ok := strings.CutSuffix(strings.TrimRight(lines[i], " \t")[len("-----BEGIN "):], "-----")
+	if !ok {
+		return "", nil, errors.New("openpgp: malformed armor header")
+	}
+	// Armor headers end at an empty line.
+	for i++; i < len(lines) && strings.TrimRight(lines[i], " \t") != ""; i++ {
+		if !strings.Contains(lines[i], ": ") {
+			return "", nil, errors.New("openpgp: malformed armor header")
+		}
+	}
+	var encoded strings.Builder
+	for i++; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		if line == "-----END "+blockType+"-----" {
+			body, err := base64.StdEncoding.DecodeString(encoded.String())
+			if err != nil {
+				return "", nil, fmt.Errorf("openpgp: malformed armor: %w", err)
+			}
+			return blockType, body, nil
+		}
+		if len(line) == 5 && line[0] == '=' {
+			continue // checksum
+		}
+		encoded.WriteString(line)
+	}
+	return "", nil, errors.New("openpgp: missing armor footer")
+}
+
+// dearmor returns the contents of data if it is in ASCII armor with the block
+// type blockType, and data itself if it is binary.
+func dearmor(data []byte, blockType string) ([]byte, error) {
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("-----BEGIN ")) {
+		return data, nil
+	}
+	t, body, err := Unarmor(data)
+	if err != nil {
+		return nil, err
+	}
+	if t != blockType {
+		return nil, fmt.Errorf("openpgp: armor block type %q is not %q", t, blockType)
+	}
+	return body, nil
+}
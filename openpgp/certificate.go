@@ -0,0 +1,81 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package openpgp
+
+import (
+	"errors"
+	"time"
+)
+
+// keyFlagsCertifySign are the key flags of certificates: the key certifies
+// other keys and signs data.
+const keyFlagsCertifySign = 0x03
+
+// MarshalCertificate returns a certificate of priv: its public-key packet, and a
+// direct-key self-signature stating that it certifies and signs. Armor it with
+// BlockPublicKey to export it as an OpenPGP public key block.
+func MarshalCertificate(priv *PrivateKey) ([]byte, error) {
+	pub := priv.public
+	created := time.Now()
+	if created.Before(pub.created) {
+		created = pub.created
+	}
+	flags := appendSubpacket(nil, subpacketKeyFlags, false, []byte{keyFlagsCertifySign})
+	sig, err := sign(priv, SigTypeDirectKey, created, flags, pub.hashedKey(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return append(pub.Marshal(), sig.Marshal()...), nil
+}
+
+// ParseCertificate parses a certificate with a composite primary key, in binary
+// form or in ASCII armor, and returns the primary key. The certificate must
+// have a valid direct-key self-signature, and no valid key revocation
+// signature. Other packets, such as User IDs, subkeys and their signatures, are
+// ignored.
+func ParseCertificate(data []byte) (*PublicKey, error) {
+	data, err := dearmor(data, BlockPublicKey)
+	if err != nil {
+		return nil, err
+	}
+	tag, body, rest, err := readPacket(data)
+	if err != nil {
+		return nil, err
+	}
+	if tag != tagPublicKey {
+		return nil, errors.New("openpgp: certificate does not start with a public key")
+	}
+	pub, err := parsePublicKeyBody(body)
+	if err != nil {
+		return nil, err
+	}
+	selfSigned := false
+	for len(rest) > 0 {
+		if tag, body, rest, err = readPacket(rest); err != nil {
+			return nil, err
+		}
+		if tag == tagPublicKey {
+			return nil, errors.New("openpgp: more than one certificate")
+		}
+		if tag != tagSignature {
+			continue
+		}
+		// Signatures of other types or algorithms, e.g. subkey bindings, are
+		// skipped.
+		sig, err := parseSignatureBody(body)
+		if err != nil || (sig.SigType != SigTypeDirectKey && sig.SigType != SigTypeKeyRevocation) ||
+			sig.verify(pub, pub.hashedKey(), nil) != nil {
+			continue
+		}
+		if sig.SigType == SigTypeKeyRevocation {
+			return nil, errors.New("openpgp: key is revoked")
+		}
+		selfSigned = true
+	}
+	if !selfSigned {
+		return nil, errors.New("openpgp: certificate without a valid direct-key self-signature")
+	}
+	return pub, nil
+}
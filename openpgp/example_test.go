@@ -0,0 +1,40 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package openpgp_test
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/trailofbits/ml-dsa/mldsa"
+	"github.com/trailofbits/ml-dsa/openpgp"
+)
+
+func ExampleSignDetached() {
+	priv, err := openpgp.GenerateKey(nil, mldsa.MLDSA65)
+	if err != nil {
+		panic(err)
+	}
+	cert, err := openpgp.MarshalCertificate(priv)
+	if err != nil {
+		panic(err)
+	}
+	sig, err := openpgp.SignDetached(priv, strings.NewReader("release notes"))
+	if err != nil {
+		panic(err)
+	}
+	armored := sig.Armor() // -----BEGIN PGP SIGNATURE-----
+
+	pub, err := openpgp.ParseCertificate(openpgp.Armor(openpgp.BlockPublicKey, cert))
+	if err != nil {
+		panic(err)
+	}
+	parsed, err := openpgp.ParseSignature(armored)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(parsed.Algorithm, parsed.Hash, parsed.Verify(pub, strings.NewReader("release notes")))
+	// Output: 30 SHA3-256 <nil>
+}
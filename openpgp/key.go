@@ -0,0 +1,336 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package openpgp creates and verifies OpenPGP v6 public-key and signature packets
+// ([RFC 9580]) for the composite algorithms of [draft-ietf-openpgp-pqc], which pair
+// ML-DSA with EdDSA: ML-DSA-65+Ed25519 and ML-DSA-87+Ed448.
+//
+// A composite key is made of an ML-DSA key of this library and an Ed25519 key of
+// crypto/ed25519, or an Ed448 key of github.com/cloudflare/circl/sign/ed448. Its key
+// material is the EdDSA public key followed by the ML-DSA public key. A composite
+// signature holds an EdDSA and an ML-DSA signature of the same data digest, and is
+// only valid if both verify. The digest is signed with pure ML-DSA and the empty
+// context string.
+//
+// As the draft requires, signatures by ML-DSA-65+Ed25519 keys use SHA3-256 and
+// signatures by ML-DSA-87+Ed448 keys use SHA3-512. Signatures with any other hash
+// algorithm are rejected.
+//
+// The package writes and reads public-key packets, detached signatures of binary
+// documents, and certificates made of a public-key packet and a direct-key
+// self-signature, in binary form or in ASCII armor. It does not implement
+// secret-key packets, User IDs, subkeys or encryption.
+//
+// [RFC 9580]: https://www.rfc-editor.org/rfc/rfc9580
+// [draft-ietf-openpgp-pqc]: https://datatracker.ietf.org/doc/draft-ietf-openpgp-pqc/
+package openpgp
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"github.com/cloudflare/circl/sign/ed448"
+	"github.com/trailofbits/ml-dsa/mldsa"
+)
+
+// OpenPGP public-key algorithm identifiers of the composite algorithms.
+const (
+	AlgorithmMLDSA65Ed25519 byte = 30
+	AlgorithmMLDSA87Ed448   byte = 31
+)
+
+const (
+	keyVersion = 6
+	// fingerprintSize is the size of a v6 fingerprint, a SHA-256 digest.
+	fingerprintSize = sha256.Size
+)
+
+// Algorithm returns the composite algorithm of the ML-DSA parameter set s, or 0
+// if s has none.
+func Algorithm(s mldsa.Scheme) byte {
+	switch s {
+	case mldsa.MLDSA65:
+		return AlgorithmMLDSA65Ed25519
+	case mldsa.MLDSA87:
+		return AlgorithmMLDSA87Ed448
+	}
+	return 0
+}
+
+// SchemeByAlgorithm returns the ML-DSA parameter set of the composite algorithm
+// alg, or nil if there is none.
+func SchemeByAlgorithm(alg byte) mldsa.Scheme {
+	for _, s := range mldsa.Schemes() {
+		if alg != 0 && Algorithm(s) == alg {
+			return s
+		}
+	}
+	return nil
+}
+
+// edPublicKeySize returns the size of the EdDSA public key of alg.
+func edPublicKeySize(alg byte) int {
+	if alg == AlgorithmMLDSA87Ed448 {
+		return ed448.PublicKeySize
+	}
+	return ed25519.PublicKeySize
+}
+
+// edSignatureSize returns the size of the EdDSA signature of alg.
+func edSignatureSize(alg byte) int {
+	if alg == AlgorithmMLDSA87Ed448 {
+		return ed448.SignatureSize
+	}
+	return ed25519.SignatureSize
+}
+
+// PublicKey is a v6 composite public key.
+type PublicKey struct {
+	algorithm byte
+	created   time.Time
+	edKey     []byte
+	key       mldsa.PublicKey
+}
+
+// NewPublicKey returns the composite public key of pub and edPub, created at
+// created. edPub is an ed25519.PublicKey for ML-DSA-65, and an ed448.PublicKey for
+// ML-DSA-87. The creation time is part of the fingerprint, and is truncated to
+// seconds.
+func NewPublicKey(pub mldsa.PublicKey, edPub crypto.PublicKey, created time.Time) (*PublicKey, error) {
+	alg := Algorithm(mldsa.SchemeOf(pub))
+	if alg == 0 {
+		return nil, errors.New("openpgp: unsupported public key type")
+	}
+	var edKey []byte
+	var edAlg byte
+	switch k := edPub.(type) {
+	case ed25519.PublicKey:
+		edKey, edAlg = k, AlgorithmMLDSA65Ed25519
+	case ed448.PublicKey:
+		edKey, edAlg = k, AlgorithmMLDSA87Ed448
+	}
+	if edAlg != alg || len(edKey) != edPublicKeySize(alg) {
+		return nil, fmt.Errorf("openpgp: %s must be paired with an %s key", mldsa.SchemeOf(pub).Name(), edName(alg))
+	}
+	created, err := checkTime(created)
+	if err != nil {
+		return nil, err
+	}
+	return &PublicKey{algorithm: alg, created: created, edKey: bytes.Clone(edKey), key: pub}, nil
+}
+
+// edName returns the name of the EdDSA component of alg.
+func edName(alg byte) string {
+	if alg == AlgorithmMLDSA87Ed448 {
+		return "Ed448"
+	}
+	return "Ed25519"
+}
+
+// checkTime truncates t to seconds, and returns an error if it cannot be encoded
+// as an OpenPGP timestamp.
+func checkTime(t time.Time) (time.Time, error) {
+	if t.Unix() < 0 || t.Unix() > math.MaxUint32 {
+		return time.Time{}, fmt.Errorf("openpgp: time %v out of range", t)
+	}
+	return time.Unix(t.Unix(), 0), nil
+}
+
+// Algorithm returns the composite algorithm of pub.
+func (pub *PublicKey) Algorithm() byte {
+	return pub.algorithm
+}
+
+// CreationTime returns the creation time of pub.
+func (pub *PublicKey) CreationTime() time.Time {
+	return pub.created
+}
+
+// MLDSA returns the ML-DSA component of pub.
+func (pub *PublicKey) MLDSA() mldsa.PublicKey {
+	return pub.key
+}
+
+// EdDSA returns the EdDSA component of pub, an ed25519.PublicKey or an
+// ed448.PublicKey.
+func (pub *PublicKey) EdDSA() crypto.PublicKey {
+	if pub.algorithm == AlgorithmMLDSA87Ed448 {
+		return ed448.PublicKey(bytes.Clone(pub.edKey))
+	}
+	return ed25519.PublicKey(bytes.Clone(pub.edKey))
+}
+
+// body returns the body of the public-key packet of pub.
+func (pub *PublicKey) body() []byte {
+	material := append(bytes.Clone(pub.edKey), pub.key.Bytes()...)
+	b := []byte{keyVersion}
+	b = binary.BigEndian.AppendUint32(b, uint32(pub.created.Unix()))
+	b = append(b, pub.algorithm)
+	b = binary.BigEndian.AppendUint32(b, uint32(len(material)))
+	return append(b, material...)
+}
+
+// Marshal returns the public-key packet of pub.
+func (pub *PublicKey) Marshal() []byte {
+	return appendPacket(nil, tagPublicKey, pub.body())
+}
+
+// hashedKey returns the encoding of pub that fingerprints and key signatures
+// hash.
+func (pub *PublicKey) hashedKey() []byte {
+	body := pub.body()
+	b := binary.BigEndian.AppendUint32([]byte{0x9b}, uint32(len(body)))
+	return append(b, body...)
+}
+
+// Fingerprint returns the 32-byte v6 fingerprint of pub.
+func (pub *PublicKey) Fingerprint() []byte {
+	fp := sha256.Sum256(pub.hashedKey())
+	return fp[:]
+}
+
+// KeyID returns the key ID of pub, the first eight bytes of its fingerprint.
+func (pub *PublicKey) KeyID() uint64 {
+	return binary.BigEndian.Uint64(pub.Fingerprint())
+}
+
+// ParsePublicKey parses a v6 public-key packet of a composite key.
+func ParsePublicKey(packet []byte) (*PublicKey, error) {
+	tag, body, rest, err := readPacket(packet)
+	if err != nil {
+		return nil, err
+	}
+	if tag != tagPublicKey {
+		return nil, fmt.Errorf("openpgp: packet type %d is not a public key", tag)
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("openpgp: trailing data after public key")
+	}
+	return parsePublicKeyBody(body)
+}
+
+func parsePublicKeyBody(body []byte) (*PublicKey, error) {
+	if len(body) < 10 {
+		return nil, errors.New("openpgp: malformed public key")
+	}
+	if body[0] != keyVersion {
+		return nil, fmt.Errorf("openpgp: unsupported public key version %d", body[0])
+	}
+	created := time.Unix(int64(binary.BigEndian.Uint32(body[1:])), 0)
+	alg := body[5]
+	scheme := SchemeByAlgorithm(alg)
+	if scheme == nil {
+		return nil, fmt.Errorf("openpgp: unsupported public key algorithm %d", alg)
+	}
+	material := body[10:]
+	edSize := edPublicKeySize(alg)
+	if int(binary.BigEndian.Uint32(body[6:])) != len(material) || len(material) != edSize+scheme.PublicKeySize() {
+		return nil, errors.New("openpgp: malformed public key")
+	}
+	pub, err := scheme.PublicKeyFromBytes(material[edSize:])
+	if err != nil {
+		return nil, fmt.Errorf("openpgp: %w", err)
+	}
+	return &PublicKey{algorithm: alg, created: created, edKey: bytes.Clone(material[:edSize]), key: pub}, nil
+}
+
+// PrivateKey is a v6 composite private key.
+type PrivateKey struct {
+	public *PublicKey
+	key    mldsa.PrivateKey
+	edKey  crypto.PrivateKey
+}
+
+// NewPrivateKey returns the composite private key of priv and edPriv, created at
+// created. edPriv is an ed25519.PrivateKey for ML-DSA-65, and an ed448.PrivateKey
+// for ML-DSA-87.
+func NewPrivateKey(priv mldsa.PrivateKey, edPriv crypto.PrivateKey, created time.Time) (*PrivateKey, error) {
+	var edPub crypto.PublicKey
+	switch k := edPriv.(type) {
+	case ed25519.PrivateKey:
+		edPub = k.Public()
+	case ed448.PrivateKey:
+		edPub = k.Public()
+	default:
+		return nil, errors.New("openpgp: unsupported EdDSA private key type")
+	}
+	pub, err := NewPublicKey(priv.Public().(mldsa.PublicKey), edPub, created)
+	if err != nil {
+		return nil, err
+	}
+	return &PrivateKey{public: pub, key: priv, edKey: edPriv}, nil
+}
+
+// GenerateKey generates a composite key for the ML-DSA parameter set s, created
+// now. If rand is nil, crypto/rand is used.
+func GenerateKey(rand io.Reader, s mldsa.Scheme) (*PrivateKey, error) {
+	var edPriv crypto.PrivateKey
+	var err error
+	switch Algorithm(s) {
+	case AlgorithmMLDSA65Ed25519:
+		_, edPriv, err = ed25519.GenerateKey(rand)
+	case AlgorithmMLDSA87Ed448:
+		_, edPriv, err = ed448.GenerateKey(rand)
+	default:
+		return nil, errors.New("openpgp: unsupported parameter set")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("openpgp: %w", err)
+	}
+	_, priv, err := s.GenerateKey(rand)
+	if err != nil {
+		return nil, fmt.Errorf("openpgp: %w", err)
+	}
+	return NewPrivateKey(priv, edPriv, time.Now())
+}
+
+// Public returns the public key of priv.
+func (priv *PrivateKey) Public() *PublicKey {
+	return priv.public
+}
+
+// Destroy destroys both components of priv. See mldsa.PrivateKey.
+func (priv *PrivateKey) Destroy() {
+	priv.key.Destroy()
+	switch k := priv.edKey.(type) {
+	case ed25519.PrivateKey:
+		clear(k)
+	case ed448.PrivateKey:
+		clear(k)
+	}
+}
+
+// signDigest returns the EdDSA and ML-DSA signatures of digest.
+func (priv *PrivateKey) signDigest(digest []byte) (edSig, sig []byte, err error) {
+	switch k := priv.edKey.(type) {
+	case ed25519.PrivateKey:
+		edSig = ed25519.Sign(k, digest)
+	case ed448.PrivateKey:
+		edSig = ed448.Sign(k, digest, "")
+	}
+	sig, err = priv.key.Sign(nil, digest, crypto.Hash(0))
+	if err != nil {
+		return nil, nil, fmt.Errorf("openpgp: %w", err)
+	}
+	return edSig, sig, nil
+}
+
+// verifyDigest reports whether edSig and sig are valid signatures of digest.
+func (pub *PublicKey) verifyDigest(digest, edSig, sig []byte) bool {
+	var edValid bool
+	if pub.algorithm == AlgorithmMLDSA87Ed448 {
+		edValid = ed448.Verify(pub.edKey, digest, edSig, "")
+	} else {
+		edValid = ed25519.Verify(pub.edKey, digest, edSig)
+	}
+	return pub.key.Verify(digest, sig) && edValid
+}
@@ -0,0 +1,461 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package openpgp
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/cloudflare/circl/sign/ed448"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trailofbits/ml-dsa/mldsa"
+)
+
+var composites = []mldsa.Scheme{mldsa.MLDSA65, mldsa.MLDSA87}
+
+func TestAlgorithm(t *testing.T) {
+	assert.Equal(t, byte(0), Algorithm(mldsa.MLDSA44))
+	assert.Equal(t, AlgorithmMLDSA65Ed25519, Algorithm(mldsa.MLDSA65))
+	assert.Equal(t, AlgorithmMLDSA87Ed448, Algorithm(mldsa.MLDSA87))
+	for _, s := range composites {
+		assert.Equal(t, s, SchemeByAlgorithm(Algorithm(s)))
+	}
+	assert.Nil(t, SchemeByAlgorithm(0))
+	assert.Nil(t, SchemeByAlgorithm(27))
+}
+
+func TestPublicKey(t *testing.T) {
+	for _, s := range composites {
+		t.Run(s.Name(), func(t *testing.T) {
+			priv, err := GenerateKey(nil, s)
+			require.NoError(t, err)
+			pub := priv.Public()
+			packet := pub.Marshal()
+			assert.Equal(t, byte(0xc0|tagPublicKey), packet[0])
+
+			tag, body, rest, err := readPacket(packet)
+			assert.NoError(t, err)
+			assert.Equal(t, byte(tagPublicKey), tag)
+			assert.Empty(t, rest)
+			assert.Equal(t, []byte{keyVersion}, body[:1])
+			assert.Equal(t, uint32(pub.CreationTime().Unix()), binary.BigEndian.Uint32(body[1:]))
+			assert.Equal(t, Algorithm(s), body[5])
+			edSize := edPublicKeySize(Algorithm(s))
+			assert.Equal(t, uint32(edSize+s.PublicKeySize()), binary.BigEndian.Uint32(body[6:]))
+			assert.Equal(t, pub.MLDSA().Bytes(), body[10+edSize:])
+
+			fp := sha256.Sum256(append(binary.BigEndian.AppendUint32([]byte{0x9b}, uint32(len(body))), body...))
+			assert.Equal(t, fp[:], pub.Fingerprint())
+			assert.Equal(t, binary.BigEndian.Uint64(fp[:8]), pub.KeyID())
+
+			parsed, err := ParsePublicKey(packet)
+			assert.NoError(t, err)
+			assert.Equal(t, pub.Fingerprint(), parsed.Fingerprint())
+			assert.True(t, pub.CreationTime().Equal(parsed.CreationTime()))
+			assert.Equal(t, pub.EdDSA(), parsed.EdDSA())
+			assert.Equal(t, pub.MLDSA().Bytes(), parsed.MLDSA().Bytes())
+		})
+	}
+}
+
+func TestNewKeyMismatch(t *testing.T) {
+	now := time.Now()
+	_, priv65, err := mldsa.MLDSA65.GenerateKey(nil)
+	assert.NoError(t, err)
+	_, priv44, err := mldsa.MLDSA44.GenerateKey(nil)
+	assert.NoError(t, err)
+	_, ed25519Key, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+	_, ed448Key, err := ed448.GenerateKey(nil)
+	assert.NoError(t, err)
+
+	_, err = NewPrivateKey(priv65, ed25519Key, now)
+	assert.NoError(t, err)
+	_, err = NewPrivateKey(priv65, ed448Key, now)
+	assert.ErrorContains(t, err, "ML-DSA-65 must be paired with an Ed25519 key")
+	_, err = NewPrivateKey(priv44, ed25519Key, now)
+	assert.Error(t, err)
+	_, err = NewPrivateKey(priv65, priv44, now)
+	assert.Error(t, err)
+	_, err = NewPrivateKey(priv65, ed25519Key, time.Date(2107, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.Error(t, err)
+	_, err = NewPublicKey(priv65.Public().(mldsa.PublicKey), ed25519.PublicKey(make([]byte, 31)), now)
+	assert.Error(t, err)
+	_, err = GenerateKey(nil, mldsa.MLDSA44)
+	assert.Error(t, err)
+}
+
+func TestParsePublicKeyMalformed(t *testing.T) {
+	priv, err := GenerateKey(nil, mldsa.MLDSA65)
+	require.NoError(t, err)
+	pub := priv.Public()
+	body := pub.body()
+	withBody := func(f func(b []byte) []byte) []byte {
+		return appendPacket(nil, tagPublicKey, f(bytes.Clone(body)))
+	}
+	for name, packet := range map[string][]byte{
+		"empty":     nil,
+		"tag":       appendPacket(nil, 14, body),
+		"trailing":  append(pub.Marshal(), 0),
+		"truncated": pub.Marshal()[:100],
+		"short":     withBody(func(b []byte) []byte { return b[:9] }),
+		"version":   withBody(func(b []byte) []byte { b[0] = 4; return b }),
+		"algorithm": withBody(func(b []byte) []byte { b[5] = 27; return b }),
+		"length":    withBody(func(b []byte) []byte { b[9]++; return b }),
+		"material":  withBody(func(b []byte) []byte { return b[:len(b)-1] }),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParsePublicKey(packet)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestSignDetached(t *testing.T) {
+	msg := []byte("release-1.0.tar.gz contents")
+	for _, s := range composites {
+		t.Run(s.Name(), func(t *testing.T) {
+			priv, err := GenerateKey(nil, s)
+			require.NoError(t, err)
+			pub := priv.Public()
+			sig, err := SignDetached(priv, bytes.NewReader(msg))
+			assert.NoError(t, err)
+			assert.Equal(t, SigTypeBinary, sig.SigType)
+			assert.Equal(t, pub.Fingerprint(), sig.IssuerFingerprint)
+			assert.Equal(t, signatureHash(Algorithm(s)).hash, sig.Hash)
+			assert.NoError(t, sig.Verify(pub, bytes.NewReader(msg)))
+
+			armored := sig.Armor()
+			assert.True(t, strings.HasPrefix(string(armored), "-----BEGIN PGP SIGNATURE-----\n\n"))
+			for _, data := range [][]byte{armored, sig.Marshal()} {
+				parsed, err := ParseSignature(data)
+				assert.NoError(t, err)
+				assert.Equal(t, sig.Marshal(), parsed.Marshal())
+				assert.True(t, sig.CreationTime.Equal(parsed.CreationTime))
+				assert.Equal(t, sig.IssuerFingerprint, parsed.IssuerFingerprint)
+				assert.NoError(t, parsed.Verify(pub, bytes.NewReader(msg)))
+			}
+
+			assert.ErrorContains(t, sig.Verify(pub, strings.NewReader("tampered")), "invalid signature")
+			wrongHash := *sig
+			wrongHash.Hash = crypto.SHA256
+			assert.ErrorContains(t, wrongHash.Verify(pub, bytes.NewReader(msg)), "not allowed")
+			otherKey, err := GenerateKey(nil, s)
+			require.NoError(t, err)
+			assert.ErrorContains(t, sig.Verify(otherKey.Public(), bytes.NewReader(msg)), "another key")
+			other := composites[0]
+			if s == other {
+				other = composites[1]
+			}
+			otherKey, err = GenerateKey(nil, other)
+			require.NoError(t, err)
+			assert.Error(t, sig.Verify(otherKey.Public(), bytes.NewReader(msg)))
+		})
+	}
+}
+
+func TestVerifyComponents(t *testing.T) {
+	msg := []byte("msg")
+	priv, err := GenerateKey(nil, mldsa.MLDSA65)
+	require.NoError(t, err)
+	sig, err := SignDetached(priv, bytes.NewReader(msg))
+	assert.NoError(t, err)
+
+	// Both signatures must be valid
+	for name, f := range map[string]func(s *Signature){
+		"EdDSA":  func(s *Signature) { s.edSignature[0] ^= 1 },
+		"ML-DSA": func(s *Signature) { s.signature[0] ^= 1 },
+		"prefix": func(s *Signature) { s.digestPrefix[0] ^= 1 },
+		"salt":   func(s *Signature) { s.salt[0] ^= 1 },
+	} {
+		t.Run(name, func(t *testing.T) {
+			modified, err := ParseSignature(sig.Marshal())
+			assert.NoError(t, err)
+			f(modified)
+			assert.ErrorContains(t, modified.Verify(priv.Public(), bytes.NewReader(msg)), "invalid signature")
+		})
+	}
+
+	// Without an issuer fingerprint, the signature is checked against the key
+	withoutIssuer, err := sign(priv, SigTypeBinary, time.Now(), nil, nil, bytes.NewReader(msg))
+	assert.NoError(t, err)
+	withoutIssuer.hashed = withoutIssuer.hashed[:6]
+	withoutIssuer, err = ParseSignature(withoutIssuer.Marshal())
+	assert.NoError(t, err)
+	assert.Nil(t, withoutIssuer.IssuerFingerprint)
+	assert.ErrorContains(t, withoutIssuer.Verify(priv.Public(), bytes.NewReader(msg)), "invalid signature")
+
+	old, err := sign(priv, SigTypeBinary, priv.Public().CreationTime().Add(-time.Second), nil, nil, bytes.NewReader(msg))
+	assert.NoError(t, err)
+	assert.ErrorContains(t, old.Verify(priv.Public(), bytes.NewReader(msg)), "predates")
+
+	keySig, err := sign(priv, SigTypeDirectKey, time.Now(), nil, nil, bytes.NewReader(msg))
+	assert.NoError(t, err)
+	assert.ErrorContains(t, keySig.Verify(priv.Public(), bytes.NewReader(msg)), "not a binary document signature")
+}
+
+func TestParseSignatureMalformed(t *testing.T) {
+	priv, err := GenerateKey(nil, mldsa.MLDSA65)
+	require.NoError(t, err)
+	sig, err := SignDetached(priv, strings.NewReader("msg"))
+	assert.NoError(t, err)
+	_, body, _, err := readPacket(sig.Marshal())
+	assert.NoError(t, err)
+	withBody := func(f func(b []byte) []byte) []byte {
+		return appendPacket(nil, tagSignature, f(bytes.Clone(body)))
+	}
+	withSig := func(f func(s *Signature)) []byte {
+		s := *sig
+		s.hashed = bytes.Clone(sig.hashed)
+		f(&s)
+		return s.Marshal()
+	}
+	saltOffset := 4 + 4 + len(sig.hashed) + 4 + 2
+
+	for name, data := range map[string][]byte{
+		"empty":          nil,
+		"tag":            appendPacket(nil, tagPublicKey, body),
+		"trailing":       append(sig.Marshal(), 0),
+		"armor type":     Armor(BlockPublicKey, sig.Marshal()),
+		"version":        withBody(func(b []byte) []byte { b[0] = 4; return b }),
+		"algorithm":      withBody(func(b []byte) []byte { b[2] = 27; return b }),
+		"SHA-1":          withBody(func(b []byte) []byte { b[3] = 2; return b }),
+		"SHA-224":        withBody(func(b []byte) []byte { b[3] = 11; return b }),
+		"SHA-256":        withBody(func(b []byte) []byte { b[3] = 8; return b }),
+		"SHA3-512":       withBody(func(b []byte) []byte { b[3] = 14; return b }),
+		"salt length":    withBody(func(b []byte) []byte { b[saltOffset] = 255; return b }),
+		"short":          withBody(func(b []byte) []byte { return b[:len(b)-1] }),
+		"long":           withBody(func(b []byte) []byte { return append(b, 0) }),
+		"hashed length":  withBody(func(b []byte) []byte { b[4] = 1; return b }),
+		"subpacket":      withSig(func(s *Signature) { s.hashed = append(s.hashed, 10) }),
+		"zero subpacket": withSig(func(s *Signature) { s.hashed = append(s.hashed, 0) }),
+		"no time":        withSig(func(s *Signature) { s.hashed = s.hashed[6:] }),
+		"time size":      withSig(func(s *Signature) { s.hashed = appendSubpacket(s.hashed, subpacketCreationTime, false, []byte{1}) }),
+		"critical":       withSig(func(s *Signature) { s.hashed = appendSubpacket(s.hashed, 3, true, []byte{0, 0, 0, 1}) }),
+		"unhashed": withSig(func(s *Signature) {
+			s.unhashed = appendSubpacket(nil, 99, true, nil)
+		}),
+		"issuer size": withSig(func(s *Signature) {
+			s.hashed = appendSubpacket(s.hashed[:6], subpacketIssuerFingerprint, false, []byte{6, 1, 2})
+		}),
+		"issuer version": withSig(func(s *Signature) {
+			s.hashed = appendSubpacket(s.hashed[:6], subpacketIssuerFingerprint, false, append([]byte{4}, make([]byte, 32)...))
+		}),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseSignature(data)
+			assert.Error(t, err)
+		})
+	}
+
+	// Non-critical unknown subpackets are ignored
+	_, err = ParseSignature(withSig(func(s *Signature) {
+		s.hashed = appendSubpacket(s.hashed, 99, false, []byte("notation"))
+		s.unhashed = appendSubpacket(nil, 99, false, nil)
+	}))
+	assert.NoError(t, err)
+}
+
+func TestCertificate(t *testing.T) {
+	for _, s := range composites {
+		t.Run(s.Name(), func(t *testing.T) {
+			priv, err := GenerateKey(nil, s)
+			require.NoError(t, err)
+			pub := priv.Public()
+			cert, err := MarshalCertificate(priv)
+			assert.NoError(t, err)
+			assert.True(t, bytes.HasPrefix(cert, pub.Marshal()))
+
+			armored := Armor(BlockPublicKey, cert)
+			assert.True(t, strings.HasPrefix(string(armored), "-----BEGIN PGP PUBLIC KEY BLOCK-----\n"))
+			for _, data := range [][]byte{armored, cert} {
+				parsed, err := ParseCertificate(data)
+				assert.NoError(t, err)
+				assert.Equal(t, pub.Fingerprint(), parsed.Fingerprint())
+			}
+
+			sig, err := ParseSignature(cert[len(pub.Marshal()):])
+			assert.NoError(t, err)
+			assert.Equal(t, SigTypeDirectKey, sig.SigType)
+			flags, err := parseSubpackets(sig.hashed)
+			assert.NoError(t, err)
+			assert.Contains(t, flags, subpacket{typ: subpacketKeyFlags, data: []byte{keyFlagsCertifySign}})
+
+			// User IDs and unrelated signatures are skipped
+			other, err := SignDetached(priv, strings.NewReader("msg"))
+			assert.NoError(t, err)
+			userID := appendPacket(nil, 13, []byte("Alice <alice@example.com>"))
+			_, err = ParseCertificate(append(append(bytes.Clone(cert), userID...), other.Marshal()...))
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestParseCertificateInvalid(t *testing.T) {
+	priv, err := GenerateKey(nil, mldsa.MLDSA65)
+	require.NoError(t, err)
+	pub := priv.Public()
+	cert, err := MarshalCertificate(priv)
+	assert.NoError(t, err)
+	otherKey, err := GenerateKey(nil, mldsa.MLDSA65)
+	require.NoError(t, err)
+	otherCert, err := MarshalCertificate(otherKey)
+	assert.NoError(t, err)
+	otherSig := otherCert[len(otherCert)-len(cert)+len(pub.Marshal()):]
+
+	revocation, err := sign(priv, SigTypeKeyRevocation, time.Now(), nil, pub.hashedKey(), nil)
+	assert.NoError(t, err)
+	tampered := bytes.Clone(cert)
+	tampered[len(tampered)-1] ^= 1
+
+	for name, data := range map[string][]byte{
+		"empty":          nil,
+		"signature":      cert[len(pub.Marshal()):],
+		"no signature":   pub.Marshal(),
+		"other key":      append(pub.Marshal(), otherSig...),
+		"tampered":       tampered,
+		"revoked":        append(bytes.Clone(cert), revocation.Marshal()...),
+		"two keys":       append(bytes.Clone(cert), otherCert...),
+		"truncated":      cert[:len(cert)-1],
+		"armor type":     Armor(BlockSignature, cert),
+		"malformed key":  appendPacket(nil, tagPublicKey, []byte{6}),
+		"partial length": append(pub.Marshal(), 0xc0|tagSignature, 224),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseCertificate(data)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// TestDraftVectors checks the sample v6 ML-DSA-65+Ed25519 certificate of
+// draft-ietf-openpgp-pqc, see testdata/README.md.
+func TestDraftVectors(t *testing.T) {
+	data, err := os.ReadFile("testdata/v6-mldsa-65-sample-pk.asc")
+	require.NoError(t, err)
+	blockType, packets, err := Unarmor(data)
+	require.NoError(t, err)
+	assert.Equal(t, BlockPublicKey, blockType)
+
+	tag, _, rest, err := readPacket(packets)
+	require.NoError(t, err)
+	assert.EqualValues(t, tagPublicKey, tag)
+	keyPacket := packets[:len(packets)-len(rest)]
+	pub, err := ParsePublicKey(keyPacket)
+	require.NoError(t, err)
+	assert.Equal(t, AlgorithmMLDSA65Ed25519, pub.Algorithm())
+	assert.Equal(t, "a3e2e14b6a493ff930fb27321f125e9a6880338be9fb7da3ae065ea65793242f", hex.EncodeToString(pub.Fingerprint()))
+	assert.True(t, pub.CreationTime().Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)))
+	assert.Equal(t, keyPacket, pub.Marshal())
+
+	parsed, err := ParseCertificate(data)
+	require.NoError(t, err)
+	assert.Equal(t, pub.Fingerprint(), parsed.Fingerprint())
+
+	// The direct-key, User ID certification and subkey binding signatures all
+	// verify. The hashed data after the primary key is that of RFC 9580,
+	// Section 5.2.4.
+	var sigTypes []byte
+	var hashed []byte
+	for len(rest) > 0 {
+		var body []byte
+		tag, body, rest, err = readPacket(rest)
+		require.NoError(t, err)
+		switch tag {
+		case 13: // User ID
+			hashed = binary.BigEndian.AppendUint32([]byte{0xb4}, uint32(len(body)))
+			hashed = append(hashed, body...)
+		case 14: // Public-Subkey
+			hashed = binary.BigEndian.AppendUint32([]byte{0x9b}, uint32(len(body)))
+			hashed = append(hashed, body...)
+		case tagSignature:
+			sig, err := parseSignatureBody(body)
+			require.NoError(t, err)
+			assert.Equal(t, crypto.SHA3_256, sig.Hash)
+			assert.NoError(t, sig.verify(pub, append(pub.hashedKey(), hashed...), nil), "signature type %#02x", sig.SigType)
+			sigTypes = append(sigTypes, sig.SigType)
+		}
+	}
+	assert.Equal(t, []byte{SigTypeDirectKey, 0x13, 0x18}, sigTypes)
+}
+
+func TestArmor(t *testing.T) {
+	data := bytes.Repeat([]byte{0xa5}, 200)
+	armored := Armor(BlockSignature, data)
+	lines := strings.Split(string(armored), "\n")
+	assert.Equal(t, "-----BEGIN PGP SIGNATURE-----", lines[0])
+	assert.Equal(t, "", lines[1])
+	assert.Len(t, lines[2], armorWidth)
+	assert.Equal(t, "-----END PGP SIGNATURE-----", lines[len(lines)-2])
+
+	blockType, body, err := Unarmor(armored)
+	assert.NoError(t, err)
+	assert.Equal(t, BlockSignature, blockType)
+	assert.Equal(t, data, body)
+
+	// Armor headers, checksums, CRLF line endings and surrounding text are
+	// accepted
+	crlf := "Signed release\r\n-----BEGIN PGP SIGNATURE-----\r\nComment: release key\r\n\r\n" +
+		"AQID\r\n=njUN\r\n-----END PGP SIGNATURE-----\r\ntrailer"
+	blockType, body, err = Unarmor([]byte(crlf))
+	assert.NoError(t, err)
+	assert.Equal(t, BlockSignature, blockType)
+	assert.Equal(t, []byte{1, 2, 3}, body)
+
+	for name, s := range map[string]string{
+		"no header":   "AQID\n",
+		"header":      "-----BEGIN PGP SIGNATURE\n\nAQID\n-----END PGP SIGNATURE-----\n",
+		"header line": "-----BEGIN PGP SIGNATURE-----\nAQID\n-----END PGP SIGNATURE-----\n",
+		"footer":      "-----BEGIN PGP SIGNATURE-----\n\nAQID\n-----END PGP MESSAGE-----\n",
+		"base64":      "-----BEGIN PGP SIGNATURE-----\n\nAQI*\n-----END PGP SIGNATURE-----\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := Unarmor([]byte(s))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestPacketLength(t *testing.T) {
+	for _, n := range []int{0, 1, 191, 192, 1000, 8383, 8384, 100000} {
+		encoded := appendLength(nil, n)
+		switch {
+		case n < 192:
+			assert.Len(t, encoded, 1)
+		case n < 8384:
+			assert.Len(t, encoded, 2)
+		default:
+			assert.Len(t, encoded, 5)
+		}
+		body := make([]byte, n)
+		tag, parsed, rest, err := readPacket(appendPacket(nil, tagSignature, body))
+		assert.NoError(t, err, n)
+		assert.Equal(t, byte(tagSignature), tag)
+		assert.Len(t, parsed, n)
+		assert.Empty(t, rest)
+	}
+
+	// Legacy format headers, with one, two and four octet lengths
+	for _, header := range [][]byte{{0x88, 3}, {0x89, 0, 3}, {0x8a, 0, 0, 0, 3}} {
+		tag, body, rest, err := readPacket(append(header, 1, 2, 3, 4))
+		assert.NoError(t, err)
+		assert.Equal(t, byte(tagSignature), tag)
+		assert.Equal(t, []byte{1, 2, 3}, body)
+		assert.Equal(t, []byte{4}, rest)
+	}
+	for _, packet := range [][]byte{{0x08, 0}, {0x8b, 0}, {0x88}, {0xc2, 224}, {0xc2, 3, 1}, {0xc2, 0xff, 0, 0}} {
+		_, _, _, err := readPacket(packet)
+		assert.Error(t, err, packet)
+	}
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package openpgp
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Packet tags.
+const (
+	tagSignature = 2
+	tagPublicKey = 6
+)
+
+// Signature subpacket types.
+const (
+	subpacketCreationTime      = 2
+	subpacketIssuerKeyID       = 16
+	subpacketKeyFlags          = 27
+	subpacketIssuerFingerprint = 33
+
+	// subpacketCritical is set in the type of subpackets that a verifier must
+	// understand.
+	subpacketCritical = 0x80
+)
+
+// appendLength appends a packet or subpacket body length in the one, two or five
+// octet encoding.
+func appendLength(b []byte, n int) []byte {
+	switch {
+	case n < 192:
+		return append(b, byte(n))
+	case n < 8384:
+		n -= 192
+		return append(b, byte(n>>8)+192, byte(n))
+	}
+	return binary.BigEndian.AppendUint32(append(b, 0xff), uint32(n))
+}
+
+// appendPacket appends a packet with an OpenPGP format header.
+func appendPacket(b []byte, tag byte, body []byte) []byte {
+	b = appendLength(append(b, 0xc0|tag), len(body))
+	return append(b, body...)
+}
+
+// readPacket reads a packet in the OpenPGP or legacy format. Partial and
+// indeterminate lengths, which only data packets use, are rejected.
+func readPacket(data []byte) (tag byte, body, rest []byte, err error) {
+	if len(data) == 0 || data[0]&0x80 == 0 {
+		return 0, nil, nil, errors.New("openpgp: malformed packet header")
+	}
+	var n int
+	var ok bool
+	if data[0]&0x40 != 0 {
+		tag = data[0] & 0x3f
+		if len(data) > 1 && data[1] >= 224 && data[1] < 255 {
+			return 0, nil, nil, errors.New("openpgp: unsupported partial body length")
+		}
+		n, data, ok = readLength(data[1:])
+	} else {
+		tag = data[0] >> 2 & 0x0f
+		switch lengthType := data[0] & 0x03; {
+		case lengthType == 0 && len(data) >= 2:
+			n, data, ok = int(data[1]), data[2:], true
+		case lengthType == 1 && len(data) >= 3:
+			n, data, ok = int(binary.BigEndian.Uint16(data[1:])), data[3:], true
+		case lengthType == 2 && len(data) >= 5:
+			n, data, ok = int(binary.BigEndian.Uint32(data[1:])), data[5:], true
+		}
+	}
+	if !ok || n > len(data) {
+		return 0, nil, nil, errors.New("openpgp: malformed packet header")
+	}
+	return tag, data[:n], data[n:], nil
+}
+
+// readLength reads a one, two or five octet length, as used by subpackets and
+// OpenPGP format packet headers.
+func readLength(data []byte) (n int, rest []byte, ok bool) {
+	switch {
+	case len(data) >= 1 && data[0] < 192:
+		return int(data[0]), data[1:], true
+	case len(data) >= 2 && data[0] < 255:
+		return (int(data[0])-192)<<8 + int(data[1]) + 192, data[2:], true
+	case len(data) >= 5 && data[0] == 255:
+		n := binary.BigEndian.Uint32(data[1:])
+		return int(n), data[5:], uint64(n) <= uint64(len(data)-5)
+	}
+	return 0, nil, false
+}
+
+// subpacket is a signature subpacket.
+type subpacket struct {
+	typ      byte
+	critical bool
+	data     []byte
+}
+
+// appendSubpacket appends a subpacket of type typ.
+func appendSubpacket(b []byte, typ byte, critical bool, data []byte) []byte {
+	if critical {
+		typ |= subpacketCritical
+	}
+	b = appendLength(b, 1+len(data))
+	return append(append(b, typ), data...)
+}
+
+// parseSubpackets parses a subpacket area.
+func parseSubpackets(area []byte) ([]subpacket, error) {
+	var subpackets []subpacket
+	for len(area) > 0 {
+		n, rest, ok := readLength(area)
+		if !ok || n == 0 || n > len(rest) {
+			return nil, errors.New("openpgp: malformed signature subpacket")
+		}
+		subpackets = append(subpackets, subpacket{
+			typ:      rest[0] &^ subpacketCritical,
+			critical: rest[0]&subpacketCritical != 0,
+			data:     rest[1:n],
+		})
+		area = rest[n:]
+	}
+	return subpackets, nil
+}
+
+// checkSubpacketSize returns an error unless sp holds size octets.
+func checkSubpacketSize(sp subpacket, size int) error {
+	if len(sp.data) != size {
+		return fmt.Errorf("openpgp: malformed signature subpacket %d", sp.typ)
+	}
+	return nil
+}
@@ -0,0 +1,295 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package openpgp
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	_ "crypto/sha3" // for crypto.SHA3_256 and crypto.SHA3_512
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"golang.org/x/crypto/cryptobyte"
+)
+
+// Signature types.
+const (
+	// SigTypeBinary is the type of signatures of binary documents.
+	SigTypeBinary byte = 0x00
+	// SigTypeDirectKey is the type of the self-signatures of certificates.
+	SigTypeDirectKey byte = 0x1f
+	// SigTypeKeyRevocation is the type of signatures that revoke a key.
+	SigTypeKeyRevocation byte = 0x20
+)
+
+const sigVersion = 6
+
+// hashAlgorithm is an OpenPGP hash algorithm, and the salt size of v6
+// signatures that use it.
+type hashAlgorithm struct {
+	id       byte
+	hash     crypto.Hash
+	saltSize int
+}
+
+// signatureHash returns the hash algorithm bound to the composite algorithm alg:
+// SHA3-256 for ML-DSA-65+Ed25519 and SHA3-512 for ML-DSA-87+Ed448. Signatures
+// with any other hash algorithm are invalid.
+func signatureHash(alg byte) hashAlgorithm {
+	if alg == AlgorithmMLDSA87Ed448 {
+		return hashAlgorithm{14, crypto.SHA3_512, 32}
+	}
+	return hashAlgorithm{12, crypto.SHA3_256, 16}
+}
+
+// Signature is a v6 composite signature packet.
+type Signature struct {
+	// SigType is the signature type, e.g. SigTypeBinary.
+	SigType byte
+	// Algorithm is the composite algorithm of the signing key.
+	Algorithm byte
+	// Hash is the hash algorithm of the data digest.
+	Hash crypto.Hash
+	// CreationTime is the time the signature was made.
+	CreationTime time.Time
+	// IssuerFingerprint is the fingerprint of the signing key, or nil if the
+	// signature does not include it.
+	IssuerFingerprint []byte
+
+	hashID       byte
+	hashed       []byte // hashed subpacket area
+	unhashed     []byte // unhashed subpacket area
+	digestPrefix []byte // first two bytes of the digest
+	salt         []byte
+	edSignature  []byte
+	signature    []byte // ML-DSA signature
+}
+
+// sign returns a signature of type sigType by priv, made at created. The digest
+// covers the salt, prefix, message if it is not nil, and the hashed part of the
+// signature, whose subpacket area holds the creation time, the issuer
+// fingerprint and subpackets.
+func sign(priv *PrivateKey, sigType byte, created time.Time, subpackets, prefix []byte, message io.Reader) (*Signature, error) {
+	created, err := checkTime(created)
+	if err != nil {
+		return nil, err
+	}
+	pub := priv.public
+	h := signatureHash(pub.algorithm)
+	s := &Signature{
+		SigType:           sigType,
+		Algorithm:         pub.algorithm,
+		Hash:              h.hash,
+		CreationTime:      created,
+		IssuerFingerprint: pub.Fingerprint(),
+		hashID:            h.id,
+		salt:              make([]byte, h.saltSize),
+	}
+	if _, err := rand.Read(s.salt); err != nil {
+		return nil, fmt.Errorf("openpgp: %w", err)
+	}
+	s.hashed = appendSubpacket(nil, subpacketCreationTime, true, binary.BigEndian.AppendUint32(nil, uint32(created.Unix())))
+	s.hashed = appendSubpacket(s.hashed, subpacketIssuerFingerprint, false, append([]byte{keyVersion}, s.IssuerFingerprint...))
+	s.hashed = append(s.hashed, subpackets...)
+	digest, err := s.digest(prefix, message)
+	if err != nil {
+		return nil, err
+	}
+	s.digestPrefix = digest[:2]
+	if s.edSignature, s.signature, err = priv.signDigest(digest); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// SignDetached reads message until EOF and returns a detached signature of it
+// as a binary document by priv.
+func SignDetached(priv *PrivateKey, message io.Reader) (*Signature, error) {
+	return sign(priv, SigTypeBinary, time.Now(), nil, nil, message)
+}
+
+// hashedHeader returns the part of the signature packet that the digest covers,
+// from the version to the hashed subpacket area.
+func (s *Signature) hashedHeader() []byte {
+	b := []byte{sigVersion, s.SigType, s.Algorithm, s.hashID}
+	b = binary.BigEndian.AppendUint32(b, uint32(len(s.hashed)))
+	return append(b, s.hashed...)
+}
+
+// digest returns the data digest of s over prefix and message.
+func (s *Signature) digest(prefix []byte, message io.Reader) ([]byte, error) {
+	h := s.Hash.New()
+	h.Write(s.salt)
+	h.Write(prefix)
+	if message != nil {
+		if _, err := io.Copy(h, message); err != nil {
+			return nil, fmt.Errorf("openpgp: %w", err)
+		}
+	}
+	header := s.hashedHeader()
+	h.Write(header)
+	h.Write(binary.BigEndian.AppendUint32([]byte{sigVersion, 0xff}, uint32(len(header))))
+	return h.Sum(nil), nil
+}
+
+// Verify reads message until EOF, and returns an error unless s is a valid
+// signature of it as a binary document by pub.
+func (s *Signature) Verify(pub *PublicKey, message io.Reader) error {
+	if s.SigType != SigTypeBinary {
+		return fmt.Errorf("openpgp: signature type %#02x is not a binary document signature", s.SigType)
+	}
+	return s.verify(pub, nil, message)
+}
+
+func (s *Signature) verify(pub *PublicKey, prefix []byte, message io.Reader) error {
+	if s.Algorithm != pub.algorithm {
+		return fmt.Errorf("openpgp: signature algorithm %d for key algorithm %d", s.Algorithm, pub.algorithm)
+	}
+	if h := signatureHash(pub.algorithm); s.hashID != h.id || s.Hash != h.hash {
+		return fmt.Errorf("openpgp: hash algorithm %d is not allowed for public key algorithm %d", s.hashID, pub.algorithm)
+	}
+	if s.IssuerFingerprint != nil && !bytes.Equal(s.IssuerFingerprint, pub.Fingerprint()) {
+		return errors.New("openpgp: signature issued by another key")
+	}
+	if s.CreationTime.Before(pub.created) {
+		return errors.New("openpgp: signature predates the key")
+	}
+	digest, err := s.digest(prefix, message)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(digest[:2], s.digestPrefix) || !pub.verifyDigest(digest, s.edSignature, s.signature) {
+		return errors.New("openpgp: invalid signature")
+	}
+	return nil
+}
+
+// Marshal returns the signature packet of s.
+func (s *Signature) Marshal() []byte {
+	b := s.hashedHeader()
+	b = binary.BigEndian.AppendUint32(b, uint32(len(s.unhashed)))
+	b = append(b, s.unhashed...)
+	b = append(b, s.digestPrefix...)
+	b = append(b, byte(len(s.salt)))
+	b = append(b, s.salt...)
+	b = append(b, s.edSignature...)
+	b = append(b, s.signature...)
+	return appendPacket(nil, tagSignature, b)
+}
+
+// Armor returns s in ASCII armor, as a detached signature.
+func (s *Signature) Armor() []byte {
+	return Armor(BlockSignature, s.Marshal())
+}
+
+// ParseSignature parses a composite signature packet, in binary form or in
+// ASCII armor. It does not verify it.
+func ParseSignature(data []byte) (*Signature, error) {
+	data, err := dearmor(data, BlockSignature)
+	if err != nil {
+		return nil, err
+	}
+	tag, body, rest, err := readPacket(data)
+	if err != nil {
+		return nil, err
+	}
+	if tag != tagSignature {
+		return nil, fmt.Errorf("openpgp: packet type %d is not a signature", tag)
+	}
+	if len(rest) != 0 {
+		return nil, errors.New("openpgp: trailing data after signature")
+	}
+	return parseSignatureBody(body)
+}
+
+func parseSignatureBody(body []byte) (*Signature, error) {
+	in := cryptobyte.String(body)
+	s := &Signature{}
+	var version, saltSize uint8
+	var hashedLen, unhashedLen uint32
+	if !in.ReadUint8(&version) {
+		return nil, errors.New("openpgp: malformed signature")
+	}
+	if version != sigVersion {
+		return nil, fmt.Errorf("openpgp: unsupported signature version %d", version)
+	}
+	if !in.ReadUint8(&s.SigType) || !in.ReadUint8(&s.Algorithm) || !in.ReadUint8(&s.hashID) ||
+		!in.ReadUint32(&hashedLen) || !in.ReadBytes(&s.hashed, int(hashedLen)) ||
+		!in.ReadUint32(&unhashedLen) || !in.ReadBytes(&s.unhashed, int(unhashedLen)) ||
+		!in.ReadBytes(&s.digestPrefix, 2) || !in.ReadUint8(&saltSize) || !in.ReadBytes(&s.salt, int(saltSize)) {
+		return nil, errors.New("openpgp: malformed signature")
+	}
+	scheme := SchemeByAlgorithm(s.Algorithm)
+	if scheme == nil {
+		return nil, fmt.Errorf("openpgp: unsupported public key algorithm %d", s.Algorithm)
+	}
+	h := signatureHash(s.Algorithm)
+	if s.hashID != h.id {
+		return nil, fmt.Errorf("openpgp: hash algorithm %d is not allowed for public key algorithm %d", s.hashID, s.Algorithm)
+	}
+	s.Hash = h.hash
+	if len(s.salt) != h.saltSize {
+		return nil, fmt.Errorf("openpgp: salt size %d does not match the hash algorithm", len(s.salt))
+	}
+	edSize := edSignatureSize(s.Algorithm)
+	if len(in) != edSize+scheme.SignatureSize() {
+		return nil, errors.New("openpgp: malformed signature")
+	}
+	s.edSignature, s.signature = in[:edSize], in[edSize:]
+	if err := s.parseSubpackets(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// parseSubpackets sets the fields of s from its hashed subpackets. Unhashed
+// subpackets are not authenticated, so only their critical bit is checked.
+func (s *Signature) parseSubpackets() error {
+	hashed, err := parseSubpackets(s.hashed)
+	if err != nil {
+		return err
+	}
+	unhashed, err := parseSubpackets(s.unhashed)
+	if err != nil {
+		return err
+	}
+	hasCreationTime := false
+	for _, sp := range hashed {
+		switch sp.typ {
+		case subpacketCreationTime:
+			if err := checkSubpacketSize(sp, 4); err != nil {
+				return err
+			}
+			s.CreationTime = time.Unix(int64(binary.BigEndian.Uint32(sp.data)), 0)
+			hasCreationTime = true
+		case subpacketIssuerFingerprint:
+			if err := checkSubpacketSize(sp, 1+fingerprintSize); err != nil {
+				return err
+			}
+			if sp.data[0] != keyVersion {
+				return fmt.Errorf("openpgp: issuer fingerprint of a version %d key", sp.data[0])
+			}
+			s.IssuerFingerprint = sp.data[1:]
+		case subpacketIssuerKeyID, subpacketKeyFlags:
+		default:
+			if sp.critical {
+				return fmt.Errorf("openpgp: unsupported critical signature subpacket %d", sp.typ)
+			}
+		}
+	}
+	for _, sp := range unhashed {
+		if sp.critical {
+			return fmt.Errorf("openpgp: unsupported critical signature subpacket %d", sp.typ)
+		}
+	}
+	if !hasCreationTime {
+		return errors.New("openpgp: signature without a creation time")
+	}
+	return nil
+}
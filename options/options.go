@@ -1,16 +1,110 @@
 // Common options for ML-DSA signatures.
 package options
 
-import "crypto"
+import (
+	"crypto"
+	_ "crypto/sha256"
+	"crypto/sha3"
+	_ "crypto/sha512"
+	"errors"
+)
+
+// SHAKE is an extendable-output function that FIPS 204 allows as a pre-hash
+// function for HashML-DSA. The standard library has no crypto.Hash values for
+// them, so they are selected with Options.SHAKE instead of Options.Hash.
+type SHAKE int
+
+const (
+	// SHAKE128 pre-hashes the message to 256 bits of SHAKE128 output.
+	SHAKE128 SHAKE = 1 + iota
+	// SHAKE256 pre-hashes the message to 512 bits of SHAKE256 output.
+	SHAKE256
+)
+
+// PreHash returns the digest PH(M) of msg with the pre-hash function selected by
+// o.Hash or o.SHAKE, to be signed or verified with HashML-DSA and the options o.
+// It returns an error for pure ML-DSA options, and for unsupported hash functions.
+func (o *Options) PreHash(msg []byte) ([]byte, error) {
+	if o == nil || (o.Hash == 0 && o.SHAKE == 0) {
+		return nil, errors.New("mldsa: no pre-hash function selected")
+	}
+	if o.Hash != 0 && o.SHAKE != 0 {
+		return nil, errors.New("mldsa: both Hash and SHAKE are set")
+	}
+	switch o.SHAKE {
+	case 0:
+	case SHAKE128:
+		return sha3.SumSHAKE128(msg, 32), nil
+	case SHAKE256:
+		return sha3.SumSHAKE256(msg, 64), nil
+	default:
+		return nil, errors.New("mldsa: unsupported pre-hash function")
+	}
+	switch o.Hash {
+	case crypto.SHA224, crypto.SHA256, crypto.SHA384, crypto.SHA512, crypto.SHA512_224,
+		crypto.SHA512_256, crypto.SHA3_224, crypto.SHA3_256, crypto.SHA3_384, crypto.SHA3_512:
+		d := o.Hash.New()
+		d.Write(msg) //nolint:errcheck
+		return d.Sum(nil), nil
+	}
+	return nil, errors.New("mldsa: unsupported pre-hash function")
+}
 
 type Options struct {
-	// Hash must currently be zero, for pure ML-DSA.
+	// Hash and SHAKE select between pure ML-DSA (both zero) and HashML-DSA (one
+	// of them non-zero). For HashML-DSA, the message passed to Sign and Verify must
+	// be the digest of the actual message, computed with the given function, for
+	// instance with PreHash.
+	//
+	// Supported values of Hash are crypto.SHA224, crypto.SHA256, crypto.SHA384,
+	// crypto.SHA512, crypto.SHA512_224, crypto.SHA512_256, crypto.SHA3_224,
+	// crypto.SHA3_256, crypto.SHA3_384 and crypto.SHA3_512.
 	Hash crypto.Hash
+	// SHAKE selects SHAKE128 or SHAKE256 as the pre-hash function. Hash must
+	// be zero if it is set.
+	SHAKE SHAKE
 
 	// Optional application-specific context string. At most 255 bytes.
 	Context string
+
+	// FaultProtection selects countermeasures against fault attacks on signing.
+	// It is ignored by Verify. The zero value disables the countermeasures.
+	FaultProtection FaultProtection
 }
 
+// FaultProtection is a level of countermeasures against fault attacks on signing.
+//
+// A single fault injected in the rejection sampling loop or in the sampling of the
+// challenge can make a signature leak enough information to recover the private key.
+// With fault protection enabled, Sign checks the signature before returning it, and
+// returns ErrFaultDetected instead of a signature that failed the check.
+//
+// Fault protection only applies to Sign and SignTo, which take an Options. SignMu and
+// the Sign method of stream signers have no options and do not check their signatures;
+// callers can verify them with VerifyMu.
+type FaultProtection int
+
+const (
+	// FaultProtectionNone disables the countermeasures.
+	FaultProtectionNone FaultProtection = iota
+
+	// FaultProtectionVerify verifies each signature against the public key of the
+	// private key before returning it. This adds the cost of a verification to signing.
+	FaultProtectionVerify
+
+	// FaultProtectionRedundant additionally recomputes the mask y, z and the hint of
+	// the signature from the signing randomness and the unprecomputed secret key,
+	// compares them with the values that were signed, and checks the rejection bounds
+	// again. The commitment w is only checked by the verification. This adds about the
+	// cost of another iteration of the rejection sampling loop.
+	FaultProtectionRedundant
+)
+
+// ErrFaultDetected is returned by Sign when a fault was detected while signing.
+// The signature is discarded. Faults are not expected outside of an attack, so
+// applications should treat this error as a security event rather than retry blindly.
+var ErrFaultDetected = errors.New("mldsa: fault detected during signing")
+
 // Implements crypto.SignerOpts
 func (o *Options) HashFunc() crypto.Hash {
 	if o == nil {
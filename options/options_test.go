@@ -0,0 +1,56 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package options
+
+import (
+	"crypto"
+	"crypto/sha256"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreHash(t *testing.T) {
+	msg := []byte("message")
+	for h, size := range map[crypto.Hash]int{
+		crypto.SHA224:     28,
+		crypto.SHA256:     32,
+		crypto.SHA384:     48,
+		crypto.SHA512:     64,
+		crypto.SHA512_224: 28,
+		crypto.SHA512_256: 32,
+		crypto.SHA3_224:   28,
+		crypto.SHA3_256:   32,
+		crypto.SHA3_384:   48,
+		crypto.SHA3_512:   64,
+	} {
+		digest, err := (&Options{Hash: h}).PreHash(msg)
+		assert.NoError(t, err)
+		assert.Len(t, digest, size)
+	}
+	for x, size := range map[SHAKE]int{SHAKE128: 32, SHAKE256: 64} {
+		digest, err := (&Options{SHAKE: x}).PreHash(msg)
+		assert.NoError(t, err)
+		assert.Len(t, digest, size)
+	}
+
+	digest, err := (&Options{Hash: crypto.SHA256, Context: "ctx"}).PreHash(msg)
+	assert.NoError(t, err)
+	want := sha256.Sum256(msg)
+	assert.Equal(t, want[:], digest)
+
+	for _, opts := range []*Options{
+		nil,
+		{},
+		{Hash: crypto.MD5},
+		{Hash: crypto.SHA1},
+		{Hash: crypto.BLAKE2b_512},
+		{SHAKE: 3},
+		{Hash: crypto.SHA256, SHAKE: SHAKE128},
+	} {
+		_, err := opts.PreHash(msg)
+		assert.Error(t, err)
+	}
+}
@@ -0,0 +1,36 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssh_test
+
+import (
+	"fmt"
+
+	"github.com/trailofbits/ml-dsa/mldsa65"
+	"github.com/trailofbits/ml-dsa/ssh"
+)
+
+func ExampleNewSigner() {
+	_, priv, err := mldsa65.GenerateKeyPair(nil)
+	if err != nil {
+		panic(err)
+	}
+	signer, err := ssh.NewSigner(priv)
+	if err != nil {
+		panic(err)
+	}
+	line := ssh.MarshalAuthorizedKey(signer.PublicKey().(*ssh.PublicKey), "deploy@example.com")
+
+	sig, err := signer.Sign(nil, []byte("session data"))
+	if err != nil {
+		panic(err)
+	}
+
+	pub, comment, _, _, err := ssh.ParseAuthorizedKey(line)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(pub.Type(), comment, pub.Verify([]byte("session data"), sig) == nil)
+	// Output: ssh-mldsa-65 deploy@example.com true
+}
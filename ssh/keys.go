@@ -0,0 +1,270 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package ssh implements ML-DSA public keys and signers for [golang.org/x/crypto/ssh],
+// with the public key algorithms "ssh-mldsa-44", "ssh-mldsa-65" and "ssh-mldsa-87"
+// of [draft-sfluhrer-ssh-mldsa].
+//
+// An encoded public key is the string of the algorithm name, followed by the
+// string of the public key defined in FIPS 204. A signature is a pure ML-DSA
+// signature with the empty context string.
+//
+// Keys can be read from and written to authorized_keys lines, and private keys
+// to unencrypted OpenSSH private key files, which hold the 32-byte seed.
+//
+// The keys of this package sign and verify SSH signatures, and can be offered as
+// host keys by an ssh.ServerConfig. However, golang.org/x/crypto/ssh only parses
+// the public keys of its built-in algorithms, so its clients and servers cannot
+// yet authenticate a connection with ML-DSA host or user keys.
+//
+// [draft-sfluhrer-ssh-mldsa]: https://datatracker.ietf.org/doc/draft-sfluhrer-ssh-mldsa/
+package ssh
+
+import (
+	"bytes"
+	"crypto"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/trailofbits/ml-dsa/mldsa"
+	"golang.org/x/crypto/cryptobyte"
+	"golang.org/x/crypto/ssh"
+)
+
+// Public key algorithm names.
+const (
+	KeyAlgoMLDSA44 = "ssh-mldsa-44"
+	KeyAlgoMLDSA65 = "ssh-mldsa-65"
+	KeyAlgoMLDSA87 = "ssh-mldsa-87"
+)
+
+// Algorithm returns the SSH public key algorithm name of the parameter set s.
+func Algorithm(s mldsa.Scheme) string {
+	switch s {
+	case mldsa.MLDSA44:
+		return KeyAlgoMLDSA44
+	case mldsa.MLDSA65:
+		return KeyAlgoMLDSA65
+	case mldsa.MLDSA87:
+		return KeyAlgoMLDSA87
+	}
+	return ""
+}
+
+// schemeByAlgorithm returns the parameter set of the SSH algorithm name algo, or
+// nil if there is none.
+func schemeByAlgorithm(algo string) mldsa.Scheme {
+	for _, s := range mldsa.Schemes() {
+		if Algorithm(s) == algo {
+			return s
+		}
+	}
+	return nil
+}
+
+// PublicKey is an ML-DSA public key. It implements ssh.PublicKey and
+// ssh.CryptoPublicKey.
+type PublicKey struct {
+	key    mldsa.PublicKey
+	scheme mldsa.Scheme
+}
+
+// NewPublicKey returns the SSH public key of pub.
+func NewPublicKey(pub mldsa.PublicKey) (*PublicKey, error) {
+	scheme := mldsa.SchemeOf(pub)
+	if scheme == nil {
+		return nil, errors.New("ssh: unsupported public key type")
+	}
+	return &PublicKey{key: pub, scheme: scheme}, nil
+}
+
+// Type returns the public key algorithm name, e.g. "ssh-mldsa-65".
+func (k *PublicKey) Type() string {
+	return Algorithm(k.scheme)
+}
+
+// Marshal returns the public key in the SSH wire format.
+func (k *PublicKey) Marshal() []byte {
+	var b cryptobyte.Builder
+	addString(&b, []byte(k.Type()))
+	addString(&b, k.key.Bytes())
+	return b.BytesOrPanic()
+}
+
+// Verify returns an error unless sig is a valid signature of data under k.
+func (k *PublicKey) Verify(data []byte, sig *ssh.Signature) error {
+	if sig.Format != k.Type() {
+		return fmt.Errorf("ssh: signature type %s for key type %s", sig.Format, k.Type())
+	}
+	if len(sig.Rest) != 0 {
+		return errors.New("ssh: trailing data in signature")
+	}
+	if !k.key.Verify(data, sig.Blob) {
+		return errors.New("ssh: signature did not verify")
+	}
+	return nil
+}
+
+// CryptoPublicKey returns the mldsa.PublicKey of k.
+func (k *PublicKey) CryptoPublicKey() crypto.PublicKey {
+	return k.key
+}
+
+// ParsePublicKey parses a public key in the SSH wire format.
+func ParsePublicKey(in []byte) (*PublicKey, error) {
+	s := cryptobyte.String(in)
+	var algo, key cryptobyte.String
+	if !readString(&s, &algo) || !readString(&s, &key) || !s.Empty() {
+		return nil, errors.New("ssh: malformed public key")
+	}
+	scheme := schemeByAlgorithm(string(algo))
+	if scheme == nil {
+		return nil, fmt.Errorf("ssh: unsupported public key algorithm %q", algo)
+	}
+	if len(key) != scheme.PublicKeySize() {
+		return nil, errors.New("ssh: malformed public key")
+	}
+	pub, err := scheme.PublicKeyFromBytes(key)
+	if err != nil {
+		return nil, fmt.Errorf("ssh: %w", err)
+	}
+	return &PublicKey{key: pub, scheme: scheme}, nil
+}
+
+// signer implements ssh.Signer with an ML-DSA private key.
+type signer struct {
+	priv mldsa.PrivateKey
+	pub  *PublicKey
+}
+
+// NewSigner returns an ssh.Signer that signs with priv, e.g. an *mldsa65.PrivateKey.
+func NewSigner(priv mldsa.PrivateKey) (ssh.Signer, error) {
+	pub, err := NewPublicKey(priv.Public().(mldsa.PublicKey))
+	if err != nil {
+		return nil, err
+	}
+	return &signer{priv: priv, pub: pub}, nil
+}
+
+func (s *signer) PublicKey() ssh.PublicKey {
+	return s.pub
+}
+
+// Sign signs data with pure ML-DSA. If rand is nil, crypto/rand is used.
+func (s *signer) Sign(rand io.Reader, data []byte) (*ssh.Signature, error) {
+	sig, err := s.priv.Sign(rand, data, crypto.Hash(0))
+	if err != nil {
+		return nil, fmt.Errorf("ssh: %w", err)
+	}
+	return &ssh.Signature{Format: s.pub.Type(), Blob: sig}, nil
+}
+
+// MarshalAuthorizedKey returns an authorized_keys line for pub, with an optional
+// comment. The line ends with a newline.
+func MarshalAuthorizedKey(pub *PublicKey, comment string) []byte {
+	line := pub.Type() + " " + base64.StdEncoding.EncodeToString(pub.Marshal())
+	if comment != "" {
+		line += " " + comment
+	}
+	return []byte(line + "\n")
+}
+
+// ParseAuthorizedKey parses the first ML-DSA public key in an authorized_keys file,
+// as described in the sshd(8) manual page. It returns the key, its comment and
+// options, and the lines of in that follow it. Empty lines, comments, and keys
+// of other algorithms are skipped.
+func ParseAuthorizedKey(in []byte) (out *PublicKey, comment string, options []string, rest []byte, err error) {
+	for len(in) > 0 {
+		line, next, _ := bytes.Cut(in, []byte("\n"))
+		in = next
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+
+		if out, comment, err = parseAuthorizedKeyLine(line); err == nil {
+			return out, comment, nil, in, nil
+		}
+		// The key may be preceded by options, which end at the first whitespace
+		// outside of double quotes
+		var optionsField []byte
+		optionsField, line = splitOptions(line)
+		if out, comment, err = parseAuthorizedKeyLine(line); err == nil {
+			return out, comment, parseOptions(optionsField), in, nil
+		}
+	}
+	return nil, "", nil, nil, errors.New("ssh: no ML-DSA key found")
+}
+
+// parseAuthorizedKeyLine parses "keytype base64-key [comment]".
+func parseAuthorizedKeyLine(line []byte) (*PublicKey, string, error) {
+	algo, line, ok := bytes.Cut(line, []byte(" "))
+	if !ok || schemeByAlgorithm(string(algo)) == nil {
+		return nil, "", errors.New("ssh: not an ML-DSA key")
+	}
+	encoded, comment, _ := bytes.Cut(bytes.TrimLeft(line, " \t"), []byte(" "))
+	wire, err := base64.StdEncoding.DecodeString(string(encoded))
+	if err != nil {
+		return nil, "", errors.New("ssh: malformed authorized key")
+	}
+	pub, err := ParsePublicKey(wire)
+	if err != nil {
+		return nil, "", err
+	}
+	if pub.Type() != string(algo) {
+		return nil, "", fmt.Errorf("ssh: key type %s does not match the encoded key type %s", algo, pub.Type())
+	}
+	return pub, string(bytes.TrimSpace(comment)), nil
+}
+
+// splitOptions splits line at the first space or tab outside of double quotes,
+// and returns the options field and the rest of the line.
+func splitOptions(line []byte) (options, rest []byte) {
+	inQuote := false
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && inQuote && i+1 < len(line):
+			i++
+		case line[i] == '"':
+			inQuote = !inQuote
+		case (line[i] == ' ' || line[i] == '\t') && !inQuote:
+			return line[:i], bytes.TrimLeft(line[i:], " \t")
+		}
+	}
+	return line, nil
+}
+
+// parseOptions splits an options field at the commas outside of double quotes.
+func parseOptions(field []byte) []string {
+	var options []string
+	inQuote := false
+	start := 0
+	for i := 0; i < len(field); i++ {
+		switch {
+		case field[i] == '\\' && inQuote && i+1 < len(field):
+			i++
+		case field[i] == '"':
+			inQuote = !inQuote
+		case field[i] == ',' && !inQuote:
+			options = append(options, string(field[start:i]))
+			start = i + 1
+		}
+	}
+	return append(options, string(field[start:]))
+}
+
+// addString appends an SSH string, a byte string with a uint32 length prefix.
+func addString(b *cryptobyte.Builder, s []byte) {
+	b.AddUint32LengthPrefixed(func(b *cryptobyte.Builder) {
+		b.AddBytes(s)
+	})
+}
+
+// readString reads an SSH string.
+func readString(s *cryptobyte.String, out *cryptobyte.String) bool {
+	var n uint32
+	return s.ReadUint32(&n) && s.ReadBytes((*[]byte)(out), int(n))
+}
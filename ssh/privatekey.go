@@ -0,0 +1,145 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssh
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/pem"
+	"errors"
+	"fmt"
+
+	"github.com/trailofbits/ml-dsa/mldsa"
+	"golang.org/x/crypto/cryptobyte"
+	"golang.org/x/crypto/ssh"
+)
+
+// The OpenSSH private key format is described in PROTOCOL.key of the OpenSSH
+// sources. Only unencrypted keys are supported, whose private section is padded
+// to a multiple of 8 bytes.
+const (
+	privateKeyMagic     = "openssh-key-v1\x00"
+	privateKeyPEMType   = "OPENSSH PRIVATE KEY"
+	privateKeyBlockSize = 8
+)
+
+// MarshalPrivateKey returns priv in an unencrypted OpenSSH private key PEM block,
+// with an optional comment. The private section of the key holds the public key
+// and the 32-byte seed. It returns an error if the seed of priv is not known.
+func MarshalPrivateKey(priv mldsa.PrivateKey, comment string) (*pem.Block, error) {
+	pub, err := NewPublicKey(priv.Public().(mldsa.PublicKey))
+	if err != nil {
+		return nil, err
+	}
+	seed, err := priv.Seed()
+	if err != nil {
+		return nil, fmt.Errorf("ssh: %w", err)
+	}
+	defer clear(seed)
+
+	var checkInt [4]byte
+	if _, err := rand.Read(checkInt[:]); err != nil {
+		return nil, fmt.Errorf("ssh: %w", err)
+	}
+	var private cryptobyte.Builder
+	private.AddBytes(checkInt[:])
+	private.AddBytes(checkInt[:])
+	addString(&private, []byte(pub.Type()))
+	addString(&private, pub.key.Bytes())
+	addString(&private, seed)
+	addString(&private, []byte(comment))
+	privateSection := private.BytesOrPanic()
+	for i := byte(1); len(privateSection)%privateKeyBlockSize != 0; i++ {
+		privateSection = append(privateSection, i)
+	}
+	defer clear(privateSection)
+
+	var b cryptobyte.Builder
+	b.AddBytes([]byte(privateKeyMagic))
+	addString(&b, []byte("none")) // cipher
+	addString(&b, []byte("none")) // KDF
+	addString(&b, nil)            // KDF options
+	b.AddUint32(1)
+	addString(&b, pub.Marshal())
+	addString(&b, privateSection)
+	return &pem.Block{Type: privateKeyPEMType, Bytes: b.BytesOrPanic()}, nil
+}
+
+// ParseRawPrivateKey parses an ML-DSA private key in the OpenSSH PEM format, and
+// returns it with its comment. The public key in the file must be the one derived
+// from the seed. Encrypted keys are not supported.
+func ParseRawPrivateKey(pemBytes []byte) (mldsa.PrivateKey, string, error) {
+	block, _ := pem.Decode(pemBytes)
+	if block == nil {
+		return nil, "", errors.New("ssh: no key found")
+	}
+	if block.Type != privateKeyPEMType {
+		return nil, "", fmt.Errorf("ssh: unsupported key type %q", block.Type)
+	}
+	defer clear(block.Bytes)
+
+	s := cryptobyte.String(block.Bytes)
+	var cipher, kdf, kdfOptions, publicKey, privateSection cryptobyte.String
+	var n uint32
+	if !bytes.HasPrefix(s, []byte(privateKeyMagic)) || !s.Skip(len(privateKeyMagic)) ||
+		!readString(&s, &cipher) || !readString(&s, &kdf) || !readString(&s, &kdfOptions) ||
+		!s.ReadUint32(&n) || !readString(&s, &publicKey) || !readString(&s, &privateSection) || !s.Empty() {
+		return nil, "", errors.New("ssh: malformed OpenSSH private key")
+	}
+	if string(cipher) != "none" || string(kdf) != "none" || len(kdfOptions) != 0 {
+		return nil, "", errors.New("ssh: encrypted private keys are not supported")
+	}
+	if n != 1 {
+		return nil, "", errors.New("ssh: multi-key files are not supported")
+	}
+	pub, err := ParsePublicKey(publicKey)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(privateSection)%privateKeyBlockSize != 0 {
+		return nil, "", errors.New("ssh: malformed OpenSSH private key")
+	}
+
+	var check1, check2 uint32
+	var keyType, pk, seed, comment cryptobyte.String
+	if !privateSection.ReadUint32(&check1) || !privateSection.ReadUint32(&check2) ||
+		!readString(&privateSection, &keyType) || !readString(&privateSection, &pk) ||
+		!readString(&privateSection, &seed) || !readString(&privateSection, &comment) {
+		return nil, "", errors.New("ssh: malformed OpenSSH private key")
+	}
+	if check1 != check2 {
+		return nil, "", errors.New("ssh: malformed OpenSSH private key")
+	}
+	for i, b := range privateSection {
+		if b != byte(i+1) {
+			return nil, "", errors.New("ssh: malformed OpenSSH private key padding")
+		}
+	}
+	if string(keyType) != pub.Type() || !bytes.Equal(pk, pub.key.Bytes()) {
+		return nil, "", errors.New("ssh: OpenSSH private key does not match its public key")
+	}
+	if len(seed) != pub.scheme.SeedSize() {
+		return nil, "", errors.New("ssh: malformed OpenSSH private key")
+	}
+	priv, err := pub.scheme.PrivateKeyFromSeed(seed)
+	if err != nil {
+		return nil, "", fmt.Errorf("ssh: %w", err)
+	}
+	if !bytes.Equal(priv.Public().(mldsa.PublicKey).Bytes(), pk) {
+		priv.Destroy()
+		return nil, "", errors.New("ssh: OpenSSH private key does not match its public key")
+	}
+	return priv, string(comment), nil
+}
+
+// ParsePrivateKey parses an ML-DSA private key in the OpenSSH PEM format, and
+// returns a Signer for it.
+func ParsePrivateKey(pemBytes []byte) (ssh.Signer, error) {
+	priv, _, err := ParseRawPrivateKey(pemBytes)
+	if err != nil {
+		return nil, err
+	}
+	return NewSigner(priv)
+}
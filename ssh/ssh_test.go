@@ -0,0 +1,377 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package ssh
+
+import (
+	"crypto/ed25519"
+	"encoding/binary"
+	"encoding/pem"
+	"io"
+	"net"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trailofbits/ml-dsa/mldsa"
+	"github.com/trailofbits/ml-dsa/mldsa65"
+	"golang.org/x/crypto/cryptobyte"
+	"golang.org/x/crypto/ssh"
+)
+
+// sshString returns s with its uint32 length prefix.
+func sshString(s []byte) []byte {
+	return append(binary.BigEndian.AppendUint32(nil, uint32(len(s))), s...)
+}
+
+func TestAlgorithm(t *testing.T) {
+	assert.Equal(t, "ssh-mldsa-44", Algorithm(mldsa.MLDSA44))
+	assert.Equal(t, "ssh-mldsa-65", Algorithm(mldsa.MLDSA65))
+	assert.Equal(t, "ssh-mldsa-87", Algorithm(mldsa.MLDSA87))
+	for _, s := range mldsa.Schemes() {
+		assert.Equal(t, s, schemeByAlgorithm(Algorithm(s)))
+	}
+	assert.Nil(t, schemeByAlgorithm("ssh-ed25519"))
+}
+
+func TestPublicKey(t *testing.T) {
+	for _, s := range mldsa.Schemes() {
+		t.Run(s.Name(), func(t *testing.T) {
+			mldsaPub, priv, err := s.GenerateKey(nil)
+			require.NoError(t, err)
+			pub, err := NewPublicKey(mldsaPub)
+			require.NoError(t, err)
+			var _ ssh.PublicKey = pub
+			var _ ssh.CryptoPublicKey = pub
+
+			wire := pub.Marshal()
+			assert.Equal(t, append(sshString([]byte(Algorithm(s))), sshString(pub.key.Bytes())...), wire)
+			parsed, err := ParsePublicKey(wire)
+			assert.NoError(t, err)
+			assert.Equal(t, pub, parsed)
+			assert.Equal(t, pub.key, parsed.CryptoPublicKey())
+
+			signer, err := NewSigner(priv)
+			assert.NoError(t, err)
+			assert.Equal(t, wire, signer.PublicKey().Marshal())
+			sig, err := signer.Sign(nil, []byte("session"))
+			assert.NoError(t, err)
+			assert.Equal(t, Algorithm(s), sig.Format)
+			assert.Len(t, sig.Blob, s.SignatureSize())
+			// The blob is a pure ML-DSA signature with the empty context
+			assert.True(t, pub.key.Verify([]byte("session"), sig.Blob))
+			assert.NoError(t, pub.Verify([]byte("session"), sig))
+
+			assert.Error(t, pub.Verify([]byte("other"), sig))
+			assert.Error(t, pub.Verify([]byte("session"), &ssh.Signature{Format: "ssh-ed25519", Blob: sig.Blob}))
+			assert.Error(t, pub.Verify([]byte("session"), &ssh.Signature{Format: sig.Format, Blob: sig.Blob, Rest: []byte{0}}))
+			otherMLDSA, _, err := s.GenerateKey(nil)
+			require.NoError(t, err)
+			other, err := NewPublicKey(otherMLDSA)
+			require.NoError(t, err)
+			assert.Error(t, other.Verify([]byte("session"), sig))
+		})
+	}
+}
+
+func TestParsePublicKeyMalformed(t *testing.T) {
+	mldsaPub, _, err := mldsa.MLDSA44.GenerateKey(nil)
+	require.NoError(t, err)
+	pub, err := NewPublicKey(mldsaPub)
+	require.NoError(t, err)
+	pk := pub.key.Bytes()
+	for name, wire := range map[string][]byte{
+		"empty":       {},
+		"truncated":   pub.Marshal()[:100],
+		"trailing":    append(pub.Marshal(), 0),
+		"algorithm":   append(sshString([]byte("ssh-ed25519")), sshString(pk)...),
+		"size":        append(sshString([]byte(KeyAlgoMLDSA65)), sshString(pk)...),
+		"missing key": sshString([]byte(KeyAlgoMLDSA44)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParsePublicKey(wire)
+			assert.Error(t, err)
+		})
+	}
+
+	_, err = NewPublicKey(nil)
+	assert.Error(t, err)
+}
+
+func TestAuthorizedKey(t *testing.T) {
+	mldsaPub, _, err := mldsa.MLDSA65.GenerateKey(nil)
+	require.NoError(t, err)
+	pub, err := NewPublicKey(mldsaPub)
+	require.NoError(t, err)
+	line := MarshalAuthorizedKey(pub, "alice@example.com")
+	assert.Regexp(t, `^ssh-mldsa-65 [A-Za-z0-9+/]+=* alice@example.com\n$`, string(line))
+	parsed, comment, options, rest, err := ParseAuthorizedKey(line)
+	assert.NoError(t, err)
+	assert.Equal(t, pub, parsed)
+	assert.Equal(t, "alice@example.com", comment)
+	assert.Nil(t, options)
+	assert.Empty(t, rest)
+
+	line = MarshalAuthorizedKey(pub, "")
+	assert.NotContains(t, string(line[:len(line)-1]), "\n")
+	parsed, comment, _, _, err = ParseAuthorizedKey(line)
+	assert.NoError(t, err)
+	assert.Equal(t, pub, parsed)
+	assert.Empty(t, comment)
+
+	edPub, _, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+	edKey, err := ssh.NewPublicKey(edPub)
+	assert.NoError(t, err)
+	otherMLDSA, _, err := mldsa.MLDSA44.GenerateKey(nil)
+	require.NoError(t, err)
+	other, err := NewPublicKey(otherMLDSA)
+	require.NoError(t, err)
+	file := "# keys\n\n" + string(ssh.MarshalAuthorizedKey(edKey)) +
+		`command="echo \"a, b\"",no-pty,from="10.0.0.0/8" ` + string(MarshalAuthorizedKey(pub, "deploy key")) +
+		"\t" + string(MarshalAuthorizedKey(other, ""))
+
+	parsed, comment, options, rest, err = ParseAuthorizedKey([]byte(file))
+	assert.NoError(t, err)
+	assert.Equal(t, pub, parsed)
+	assert.Equal(t, "deploy key", comment)
+	assert.Equal(t, []string{`command="echo \"a, b\""`, "no-pty", `from="10.0.0.0/8"`}, options)
+	parsed, _, options, rest, err = ParseAuthorizedKey(rest)
+	assert.NoError(t, err)
+	assert.Equal(t, other, parsed)
+	assert.Nil(t, options)
+	assert.Empty(t, rest)
+
+	for name, in := range map[string]string{
+		"empty":         "",
+		"other keys":    "# keys\n" + string(ssh.MarshalAuthorizedKey(edKey)),
+		"base64":        "ssh-mldsa-65 !!!!\n",
+		"type mismatch": "ssh-mldsa-44 " + string(MarshalAuthorizedKey(pub, ""))[len("ssh-mldsa-65 "):],
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, _, _, err := ParseAuthorizedKey([]byte(in))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestPrivateKey(t *testing.T) {
+	for _, s := range mldsa.Schemes() {
+		t.Run(s.Name(), func(t *testing.T) {
+			mldsaPub, priv, err := s.GenerateKey(nil)
+			require.NoError(t, err)
+			pub, err := NewPublicKey(mldsaPub)
+			require.NoError(t, err)
+			block, err := MarshalPrivateKey(priv, "host key")
+			assert.NoError(t, err)
+			assert.Equal(t, "OPENSSH PRIVATE KEY", block.Type)
+			encoded := pem.EncodeToMemory(block)
+
+			parsed, comment, err := ParseRawPrivateKey(encoded)
+			assert.NoError(t, err)
+			assert.Equal(t, priv.EncodeExpanded(), parsed.EncodeExpanded())
+			assert.Equal(t, "host key", comment)
+
+			signer, err := ParsePrivateKey(encoded)
+			assert.NoError(t, err)
+			assert.Equal(t, pub.Marshal(), signer.PublicKey().Marshal())
+
+			// The private section holds the public key and the seed, padded to
+			// a multiple of 8 bytes
+			in := cryptobyte.String(block.Bytes[len(privateKeyMagic):])
+			var cipher, kdf, kdfOptions, publicKey, private cryptobyte.String
+			var n, check1, check2 uint32
+			assert.True(t, readString(&in, &cipher) && readString(&in, &kdf) && readString(&in, &kdfOptions) &&
+				in.ReadUint32(&n) && readString(&in, &publicKey) && readString(&in, &private) && in.Empty())
+			assert.Equal(t, "none", string(cipher))
+			assert.Equal(t, "none", string(kdf))
+			assert.Empty(t, kdfOptions)
+			assert.Equal(t, uint32(1), n)
+			assert.Equal(t, pub.Marshal(), []byte(publicKey))
+			assert.Zero(t, len(private)%8)
+			seed, err := priv.Seed()
+			assert.NoError(t, err)
+			var keyType, pk, privSeed, privComment cryptobyte.String
+			assert.True(t, private.ReadUint32(&check1) && private.ReadUint32(&check2) &&
+				readString(&private, &keyType) && readString(&private, &pk) &&
+				readString(&private, &privSeed) && readString(&private, &privComment))
+			assert.Equal(t, check1, check2)
+			assert.Equal(t, Algorithm(s), string(keyType))
+			assert.Equal(t, pub.key.Bytes(), []byte(pk))
+			assert.Equal(t, seed, []byte(privSeed))
+			assert.Equal(t, "host key", string(privComment))
+			assert.Equal(t, []byte{1, 2, 3, 4, 5, 6, 7}[:len(private)], []byte(private))
+		})
+	}
+}
+
+func TestMarshalPrivateKeyWithoutSeed(t *testing.T) {
+	_, priv, err := mldsa.MLDSA44.GenerateKey(nil)
+	require.NoError(t, err)
+	expanded, err := mldsa.MLDSA44.PrivateKeyFromExpanded(priv.EncodeExpanded())
+	assert.NoError(t, err)
+	_, err = MarshalPrivateKey(expanded, "")
+	assert.Error(t, err)
+}
+
+func TestParsePrivateKeyMalformed(t *testing.T) {
+	mldsaPub, priv, err := mldsa.MLDSA44.GenerateKey(nil)
+	require.NoError(t, err)
+	pub, err := NewPublicKey(mldsaPub)
+	require.NoError(t, err)
+	seed, err := priv.Seed()
+	assert.NoError(t, err)
+	_, other, err := mldsa.MLDSA44.GenerateKey(nil)
+	require.NoError(t, err)
+	otherSeed, err := other.Seed()
+	assert.NoError(t, err)
+
+	// key returns an OpenSSH private key file with the given fields
+	key := func(cipher string, check2 uint32, keyType string, seed []byte, padding []byte) []byte {
+		var private cryptobyte.Builder
+		private.AddUint32(1)
+		private.AddUint32(check2)
+		addString(&private, []byte(keyType))
+		addString(&private, pub.key.Bytes())
+		addString(&private, seed)
+		addString(&private, nil)
+		private.AddBytes(padding)
+		var b cryptobyte.Builder
+		b.AddBytes([]byte(privateKeyMagic))
+		addString(&b, []byte(cipher))
+		addString(&b, []byte(cipher))
+		addString(&b, nil)
+		b.AddUint32(1)
+		addString(&b, pub.Marshal())
+		addString(&b, private.BytesOrPanic())
+		return pem.EncodeToMemory(&pem.Block{Type: "OPENSSH PRIVATE KEY", Bytes: b.BytesOrPanic()})
+	}
+	// 8 + 4+12 + 4+1312 + 4+32 + 4 = 1380 bytes, padded with 4 bytes
+	_, _, err = ParseRawPrivateKey(key("none", 1, KeyAlgoMLDSA44, seed, []byte{1, 2, 3, 4}))
+	assert.NoError(t, err)
+
+	for name, in := range map[string][]byte{
+		"no PEM":       []byte("ssh-mldsa-44"),
+		"PEM type":     pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: []byte{0}}),
+		"magic":        pem.EncodeToMemory(&pem.Block{Type: "OPENSSH PRIVATE KEY", Bytes: []byte("openssh-key-v2\x00")}),
+		"encrypted":    key("aes256-ctr", 1, KeyAlgoMLDSA44, seed, []byte{1, 2, 3, 4}),
+		"check ints":   key("none", 2, KeyAlgoMLDSA44, seed, []byte{1, 2, 3, 4}),
+		"key type":     key("none", 1, KeyAlgoMLDSA65, seed, []byte{1, 2, 3, 4}),
+		"seed size":    key("none", 1, KeyAlgoMLDSA44, seed[:31], []byte{1, 2, 3, 4, 5}),
+		"mismatched":   key("none", 1, KeyAlgoMLDSA44, otherSeed, []byte{1, 2, 3, 4}),
+		"padding":      key("none", 1, KeyAlgoMLDSA44, seed, []byte{0, 0, 0, 0}),
+		"padding size": key("none", 1, KeyAlgoMLDSA44, seed, []byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}[:5]),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := ParseRawPrivateKey(in)
+			assert.Error(t, err)
+			_, err = ParsePrivateKey(in)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// asyncConn buffers the writes to a net.Pipe connection, so that both sides of
+// an SSH connection can send their version and KEXINIT messages at once.
+type asyncConn struct {
+	net.Conn
+	writes    chan []byte
+	closed    chan struct{}
+	closeOnce sync.Once
+}
+
+func (c *asyncConn) Write(b []byte) (int, error) {
+	select {
+	case c.writes <- append([]byte(nil), b...):
+		return len(b), nil
+	case <-c.closed:
+		return 0, net.ErrClosed
+	}
+}
+
+func (c *asyncConn) Close() error {
+	c.closeOnce.Do(func() { close(c.closed) })
+	return c.Conn.Close()
+}
+
+func pipe() (net.Conn, net.Conn) {
+	c1, c2 := net.Pipe()
+	async := func(c net.Conn) net.Conn {
+		a := &asyncConn{Conn: c, writes: make(chan []byte, 64), closed: make(chan struct{})}
+		go func() {
+			for {
+				select {
+				case b := <-a.writes:
+					if _, err := c.Write(b); err != nil {
+						return
+					}
+				case <-a.closed:
+					return
+				}
+			}
+		}()
+		return a
+	}
+	return async(c1), async(c2)
+}
+
+// recordingSigner records the last signature of an ssh.Signer.
+type recordingSigner struct {
+	ssh.Signer
+	data []byte
+	sig  *ssh.Signature
+}
+
+func (s *recordingSigner) Sign(rand io.Reader, data []byte) (*ssh.Signature, error) {
+	sig, err := s.Signer.Sign(rand, data)
+	s.data, s.sig = data, sig
+	return sig, err
+}
+
+// handshake runs an SSH handshake between a client and a server over net.Pipe,
+// and returns the errors of both sides.
+func handshake(server *ssh.ServerConfig, client *ssh.ClientConfig) (serverErr, clientErr error) {
+	c1, c2 := pipe()
+	done := make(chan error)
+	go func() {
+		conn, _, _, err := ssh.NewServerConn(c1, server)
+		if err == nil {
+			conn.Close()
+		}
+		done <- err
+	}()
+	conn, _, _, clientErr := ssh.NewClientConn(c2, "pipe", client)
+	if clientErr == nil {
+		conn.Close()
+	}
+	return <-done, clientErr
+}
+
+// golang.org/x/crypto/ssh only parses the public keys of its built-in
+// algorithms, so its clients cannot complete a handshake with an ML-DSA host
+// key, and its servers cannot authenticate ML-DSA user keys. This only checks
+// that the server signs the exchange hash with an ML-DSA host key, and that the
+// signature verifies.
+func TestHostKeySignature(t *testing.T) {
+	_, mldsaKey, err := mldsa65.GenerateKeyPair(nil)
+	require.NoError(t, err)
+	mldsaSigner, err := NewSigner(mldsaKey)
+	require.NoError(t, err)
+
+	hostKey := &recordingSigner{Signer: mldsaSigner}
+	server := &ssh.ServerConfig{NoClientAuth: true}
+	server.AddHostKey(hostKey)
+	client := &ssh.ClientConfig{
+		HostKeyAlgorithms: []string{KeyAlgoMLDSA65},
+		HostKeyCallback:   ssh.FixedHostKey(mldsaSigner.PublicKey()),
+	}
+	serverErr, clientErr := handshake(server, client)
+	assert.Error(t, serverErr)
+	assert.ErrorContains(t, clientErr, "unknown key algorithm")
+
+	require.NotNil(t, hostKey.sig)
+	assert.Equal(t, KeyAlgoMLDSA65, hostKey.sig.Format)
+	assert.NoError(t, mldsaSigner.PublicKey().Verify(hostKey.data, hostKey.sig))
+}
@@ -0,0 +1,245 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sshsig
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/trailofbits/ml-dsa/ssh"
+)
+
+// AllowedSigner is a line of an allowed_signers file, as described in the
+// ssh-keygen(1) manual page:
+//
+//	principals [options] keytype base64-key [comment]
+type AllowedSigner struct {
+	// Principals are the patterns of the principals, e.g. email addresses, that
+	// the key may sign for. Patterns may use the "*" and "?" wildcards, and be
+	// negated with "!".
+	Principals []string
+	// PublicKey is the trusted key.
+	PublicKey *ssh.PublicKey
+
+	// CertAuthority is set by the cert-authority option, which trusts certificates
+	// signed by the key rather than the key itself. Certificates of ML-DSA keys are
+	// not supported, so such lines never allow a signature.
+	CertAuthority bool
+	// Namespaces are the patterns of the namespaces the key may sign for, from
+	// the namespaces option. If empty, any namespace is allowed.
+	Namespaces []string
+	// ValidAfter and ValidBefore bound the time of the signatures, from the
+	// valid-after and valid-before options. They are zero if not set.
+	ValidAfter, ValidBefore time.Time
+}
+
+// ParseAllowedSigners parses an allowed_signers file. Empty lines and comments are
+// skipped, as are lines with keys of other algorithms, which this package cannot
+// verify. Malformed lines with ML-DSA key types are an error.
+func ParseAllowedSigners(in []byte) ([]*AllowedSigner, error) {
+	var signers []*AllowedSigner
+	for i, line := range bytes.Split(in, []byte("\n")) {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+		principals, rest := splitField(line)
+		// The key type follows the optional options field
+		keyType, keyRest := splitField(rest)
+		if !isMLDSAKeyType(keyType) {
+			keyType, _ = splitField(keyRest)
+		}
+		if !isMLDSAKeyType(keyType) {
+			continue
+		}
+		// The rest of the line has the syntax of an authorized_keys line
+		pub, _, options, _, err := ssh.ParseAuthorizedKey(rest)
+		if err != nil {
+			return nil, fmt.Errorf("sshsig: allowed signers line %d: malformed %s key", i+1, keyType)
+		}
+		signer := &AllowedSigner{Principals: splitList(unquote(principals)), PublicKey: pub}
+		if err := signer.parseOptions(options); err != nil {
+			return nil, fmt.Errorf("sshsig: allowed signers line %d: %w", i+1, err)
+		}
+		signers = append(signers, signer)
+	}
+	return signers, nil
+}
+
+// isMLDSAKeyType reports whether field is an ML-DSA key type, including the ones
+// of unknown parameter sets.
+func isMLDSAKeyType(field []byte) bool {
+	return bytes.HasPrefix(field, []byte("ssh-mldsa-"))
+}
+
+func (a *AllowedSigner) parseOptions(options []string) error {
+	for _, option := range options {
+		name, value, _ := strings.Cut(option, "=")
+		value = unquote([]byte(value))
+		var err error
+		switch strings.ToLower(name) {
+		case "cert-authority":
+			a.CertAuthority = true
+		case "namespaces":
+			a.Namespaces = splitList(value)
+		case "valid-after":
+			a.ValidAfter, err = parseTime(value)
+		case "valid-before":
+			a.ValidBefore, err = parseTime(value)
+		default:
+			return fmt.Errorf("unsupported option %q", name)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MatchPrincipal reports whether principal matches the principals of a.
+func (a *AllowedSigner) MatchPrincipal(principal string) bool {
+	return matchList(principal, a.Principals)
+}
+
+// Allows reports whether a trusts pub to sign for namespace at time t. An empty
+// namespace matches any namespace option.
+func (a *AllowedSigner) Allows(pub *ssh.PublicKey, namespace string, t time.Time) bool {
+	if a.CertAuthority || !bytes.Equal(a.PublicKey.Marshal(), pub.Marshal()) {
+		return false
+	}
+	if namespace != "" && len(a.Namespaces) != 0 && !matchList(namespace, a.Namespaces) {
+		return false
+	}
+	if !a.ValidAfter.IsZero() && t.Before(a.ValidAfter) {
+		return false
+	}
+	if !a.ValidBefore.IsZero() && !t.Before(a.ValidBefore) {
+		return false
+	}
+	return true
+}
+
+// FindPrincipals returns the principals of the signers that trust pub at time t,
+// in any namespace, each joined with commas as in the allowed_signers file.
+func FindPrincipals(signers []*AllowedSigner, pub *ssh.PublicKey, t time.Time) []string {
+	var principals []string
+	for _, s := range signers {
+		if s.Allows(pub, "", t) {
+			principals = append(principals, strings.Join(s.Principals, ","))
+		}
+	}
+	return principals
+}
+
+// splitField splits line at the first space or tab outside of double quotes.
+func splitField(line []byte) (field, rest []byte) {
+	inQuote := false
+	for i := 0; i < len(line); i++ {
+		switch {
+		case line[i] == '\\' && inQuote && i+1 < len(line):
+			i++
+		case line[i] == '"':
+			inQuote = !inQuote
+		case (line[i] == ' ' || line[i] == '\t') && !inQuote:
+			return line[:i], bytes.TrimLeft(line[i:], " \t")
+		}
+	}
+	return line, nil
+}
+
+// unquote removes the double quotes of a field, and the backslashes that escape
+// double quotes in it.
+func unquote(field []byte) string {
+	if len(field) < 2 || field[0] != '"' || field[len(field)-1] != '"' {
+		return string(field)
+	}
+	return strings.ReplaceAll(string(field[1:len(field)-1]), `\"`, `"`)
+}
+
+func splitList(list string) []string {
+	if list == "" {
+		return nil
+	}
+	return strings.Split(list, ",")
+}
+
+// matchList reports whether s matches one of patterns, and none of the negated
+// ones, like match_pattern_list in OpenSSH.
+func matchList(s string, patterns []string) bool {
+	matched := false
+	for _, p := range patterns {
+		if negated, ok := strings.CutPrefix(p, "!"); ok {
+			if matchPattern(s, negated) {
+				return false
+			}
+		} else if matchPattern(s, p) {
+			matched = true
+		}
+	}
+	return matched
+}
+
+// matchPattern reports whether s matches pattern, where "*" matches any sequence
+// of characters and "?" any single character.
+func matchPattern(s, pattern string) bool {
+	for len(pattern) > 0 {
+		switch pattern[0] {
+		case '*':
+			for i := len(s); i >= 0; i-- {
+				if matchPattern(s[i:], pattern[1:]) {
+					return true
+				}
+			}
+			return false
+		case '?':
+			if len(s) == 0 {
+				return false
+			}
+		default:
+			if len(s) == 0 || s[0] != pattern[0] {
+				return false
+			}
+		}
+		s, pattern = s[1:], pattern[1:]
+	}
+	return len(s) == 0
+}
+
+// ParseTime parses a time in the format of the valid-after and valid-before
+// options, and of the verify-time option of ssh-keygen:
+// YYYYMMDD, YYYYMMDDHHMM or YYYYMMDDHHMMSS, in UTC if followed by "Z" and in the
+// local time zone otherwise.
+func ParseTime(value string) (time.Time, error) {
+	t, err := parseTime(value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("sshsig: %w", err)
+	}
+	return t, nil
+}
+
+func parseTime(value string) (time.Time, error) {
+	loc := time.Local
+	if v, ok := strings.CutSuffix(value, "Z"); ok {
+		value, loc = v, time.UTC
+	}
+	var layout string
+	switch len(value) {
+	case 8:
+		layout = "20060102"
+	case 12:
+		layout = "200601021504"
+	case 14:
+		layout = "20060102150405"
+	default:
+		return time.Time{}, fmt.Errorf("invalid time %q", value)
+	}
+	t, err := time.ParseInLocation(layout, value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q", value)
+	}
+	return t, nil
+}
@@ -0,0 +1,149 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sshsig
+
+import (
+	"crypto/ed25519"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trailofbits/ml-dsa/mldsa"
+	"github.com/trailofbits/ml-dsa/ssh"
+	xssh "golang.org/x/crypto/ssh"
+)
+
+// authorizedKey returns the keytype and base64 key of pub.
+func authorizedKey(pub *ssh.PublicKey) string {
+	return strings.TrimSuffix(string(ssh.MarshalAuthorizedKey(pub, "")), "\n")
+}
+
+func TestParseAllowedSigners(t *testing.T) {
+	aliceMLDSA, _, err := mldsa.MLDSA65.GenerateKey(nil)
+	require.NoError(t, err)
+	alice, err := ssh.NewPublicKey(aliceMLDSA)
+	require.NoError(t, err)
+	releaseMLDSA, _, err := mldsa.MLDSA87.GenerateKey(nil)
+	require.NoError(t, err)
+	release, err := ssh.NewPublicKey(releaseMLDSA)
+	require.NoError(t, err)
+	oldMLDSA, _, err := mldsa.MLDSA44.GenerateKey(nil)
+	require.NoError(t, err)
+	old, err := ssh.NewPublicKey(oldMLDSA)
+	require.NoError(t, err)
+	edPub, _, err := ed25519.GenerateKey(nil)
+	assert.NoError(t, err)
+	edKey, err := xssh.NewPublicKey(edPub)
+	assert.NoError(t, err)
+
+	file := "# git signers\n\n" +
+		"alice@example.com " + authorizedKey(alice) + " alice's laptop\n" +
+		"bob@example.com " + string(xssh.MarshalAuthorizedKey(edKey)) +
+		`carol@example.com namespaces="git" ` + string(xssh.MarshalAuthorizedKey(edKey)) +
+		`"release bot,*@ci.example.com,!*@untrusted.ci.example.com" namespaces="file,git" ` + authorizedKey(release) + "\n" +
+		`  old@example.com valid-after="20240101",valid-before=20250101Z ` + authorizedKey(old) + "\n" +
+		"*.example.com cert-authority " + authorizedKey(alice) + "\n"
+	signers, err := ParseAllowedSigners([]byte(file))
+	assert.NoError(t, err)
+	assert.Len(t, signers, 4)
+
+	assert.Equal(t, &AllowedSigner{Principals: []string{"alice@example.com"}, PublicKey: alice}, signers[0])
+	assert.Equal(t, &AllowedSigner{
+		Principals: []string{"release bot", "*@ci.example.com", "!*@untrusted.ci.example.com"},
+		PublicKey:  release,
+		Namespaces: []string{"file", "git"},
+	}, signers[1])
+	assert.Equal(t, []string{"old@example.com"}, signers[2].Principals)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.Local), signers[2].ValidAfter)
+	assert.Equal(t, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), signers[2].ValidBefore)
+	assert.True(t, signers[3].CertAuthority)
+
+	now := time.Now()
+	assert.True(t, signers[0].MatchPrincipal("alice@example.com"))
+	assert.False(t, signers[0].MatchPrincipal("bob@example.com"))
+	assert.True(t, signers[0].Allows(alice, "git", now))
+	assert.True(t, signers[0].Allows(alice, "anything", now))
+	assert.False(t, signers[0].Allows(release, "git", now))
+
+	assert.True(t, signers[1].MatchPrincipal("release bot"))
+	assert.True(t, signers[1].MatchPrincipal("deploy@ci.example.com"))
+	assert.False(t, signers[1].MatchPrincipal("deploy@untrusted.ci.example.com"))
+	assert.False(t, signers[1].MatchPrincipal("release"))
+	assert.True(t, signers[1].Allows(release, "file", now))
+	assert.False(t, signers[1].Allows(release, "email", now))
+	assert.True(t, signers[1].Allows(release, "", now))
+
+	assert.False(t, signers[2].Allows(old, "git", time.Date(2023, 12, 31, 0, 0, 0, 0, time.Local)))
+	assert.True(t, signers[2].Allows(old, "git", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, signers[2].Allows(old, "git", time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)))
+
+	// Certificates are not supported
+	assert.False(t, signers[3].Allows(alice, "git", now))
+
+	assert.Equal(t, []string{"alice@example.com"}, FindPrincipals(signers, alice, now))
+	assert.Equal(t, []string{`release bot,*@ci.example.com,!*@untrusted.ci.example.com`}, FindPrincipals(signers, release, now))
+	assert.Empty(t, FindPrincipals(signers, old, now))
+}
+
+func TestParseAllowedSignersMalformed(t *testing.T) {
+	mldsaPub, _, err := mldsa.MLDSA44.GenerateKey(nil)
+	require.NoError(t, err)
+	pub, err := ssh.NewPublicKey(mldsaPub)
+	require.NoError(t, err)
+	for name, line := range map[string]string{
+		"option":     "a@example.com no-touch-required " + authorizedKey(pub),
+		"time":       "a@example.com valid-after=2024 " + authorizedKey(pub),
+		"time value": "a@example.com valid-before=20241301 " + authorizedKey(pub),
+		"base64":     "a@example.com ssh-mldsa-44 AAAA!",
+		"key":        "a@example.com ssh-mldsa-44 AAAAC3NzaC1lZDI1NTE5",
+		"key type":   "a@example.com namespaces=git ssh-mldsa-65 " + strings.Fields(authorizedKey(pub))[1],
+		"parameters": "a@example.com ssh-mldsa-1 AAAA",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseAllowedSigners([]byte(line))
+			assert.ErrorContains(t, err, "line 1")
+		})
+	}
+}
+
+func TestMatchPattern(t *testing.T) {
+	for _, tc := range []struct {
+		s, pattern string
+		match      bool
+	}{
+		{"", "", true},
+		{"", "*", true},
+		{"a", "", false},
+		{"alice@example.com", "alice@example.com", true},
+		{"alice@example.com", "*@example.com", true},
+		{"alice@example.com", "*@example.org", false},
+		{"alice@example.com", "alic?@example.com", true},
+		{"alice@example.com", "alice?@example.com", false},
+		{"alice@example.com", "a*e*e*", true},
+		{"alice@example.com", "*", true},
+		{"alice@example.com", "ALICE@example.com", false},
+	} {
+		assert.Equal(t, tc.match, matchPattern(tc.s, tc.pattern), "%q %q", tc.s, tc.pattern)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	for value, expected := range map[string]time.Time{
+		"20240229":        time.Date(2024, 2, 29, 0, 0, 0, 0, time.Local),
+		"20240229Z":       time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC),
+		"202402291230":    time.Date(2024, 2, 29, 12, 30, 0, 0, time.Local),
+		"20240229123059Z": time.Date(2024, 2, 29, 12, 30, 59, 0, time.UTC),
+	} {
+		parsed, err := ParseTime(value)
+		assert.NoError(t, err)
+		assert.True(t, expected.Equal(parsed), value)
+	}
+	for _, value := range []string{"", "2024", "20230229", "2024022912", "20240229123060", "20240229ZZ", "+0240229"} {
+		_, err := ParseTime(value)
+		assert.Error(t, err, value)
+	}
+}
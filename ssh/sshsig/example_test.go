@@ -0,0 +1,46 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sshsig_test
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/trailofbits/ml-dsa/mldsa"
+	"github.com/trailofbits/ml-dsa/ssh"
+	"github.com/trailofbits/ml-dsa/ssh/sshsig"
+)
+
+func ExampleSign() {
+	pub, priv, err := mldsa.MLDSA65.GenerateKey(nil)
+	if err != nil {
+		panic(err)
+	}
+	sig, err := sshsig.Sign(priv, strings.NewReader("release v1.2.0"), "file", sshsig.HashSHA512)
+	if err != nil {
+		panic(err)
+	}
+	armored := sig.Armor()
+
+	sshPub, err := ssh.NewPublicKey(pub)
+	if err != nil {
+		panic(err)
+	}
+	allowed, err := sshsig.ParseAllowedSigners(append([]byte("release@example.com "), ssh.MarshalAuthorizedKey(sshPub, "")...))
+	if err != nil {
+		panic(err)
+	}
+
+	parsed, err := sshsig.ParseSignature(armored)
+	if err != nil {
+		panic(err)
+	}
+	if err := parsed.Verify(strings.NewReader("release v1.2.0"), "file"); err != nil {
+		panic(err)
+	}
+	fmt.Println(sshsig.FindPrincipals(allowed, parsed.PublicKey, time.Now()))
+	// Output: [release@example.com]
+}
@@ -0,0 +1,243 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package sshsig creates and verifies detached SSHSIG signatures with ML-DSA keys,
+// the armored "SSH SIGNATURE" format of "ssh-keygen -Y sign" described in
+// [PROTOCOL.sshsig], which git uses with gpg.format=ssh. It also parses the
+// allowed_signers files that map principals to the keys trusted to sign for them.
+//
+// The signed data holds the namespace and a SHA-256 or SHA-512 digest of the
+// message, and is signed with pure ML-DSA. The namespace is also the ML-DSA context
+// string, so it must be at most 255 bytes long, and a signature for one namespace
+// does not verify as an ML-DSA signature for another.
+//
+// [PROTOCOL.sshsig]: https://github.com/openssh/openssh-portable/blob/master/PROTOCOL.sshsig
+package sshsig
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"strings"
+
+	"github.com/trailofbits/ml-dsa/mldsa"
+	"github.com/trailofbits/ml-dsa/options"
+	"github.com/trailofbits/ml-dsa/ssh"
+	"golang.org/x/crypto/cryptobyte"
+)
+
+// Hash algorithms of the message digest.
+const (
+	HashSHA256 = "sha256"
+	HashSHA512 = "sha512"
+)
+
+const (
+	magicPreamble = "SSHSIG"
+	sigVersion    = 1
+
+	armorBegin = "-----BEGIN SSH SIGNATURE-----"
+	armorEnd   = "-----END SSH SIGNATURE-----"
+	// armorWidth is the line length of the base64 body, as written by ssh-keygen.
+	armorWidth = 70
+)
+
+// Signature is a decoded SSHSIG signature.
+type Signature struct {
+	// PublicKey is the key that made the signature. Verify checks the signature
+	// against it, but the caller must decide whether to trust it, for instance
+	// with an allowed_signers file.
+	PublicKey *ssh.PublicKey
+	// Namespace is the domain of the signature, e.g. "git" or "file".
+	Namespace string
+	// HashAlgorithm is the message digest algorithm, HashSHA256 or HashSHA512.
+	HashAlgorithm string
+
+	// signature is the ML-DSA signature of the signed data.
+	signature []byte
+}
+
+// newHash returns a hash of the message digest algorithm name.
+func newHash(name string) (hash.Hash, error) {
+	switch name {
+	case HashSHA256:
+		return sha256.New(), nil
+	case HashSHA512:
+		return sha512.New(), nil
+	}
+	return nil, fmt.Errorf("sshsig: unsupported hash algorithm %q", name)
+}
+
+func checkNamespace(namespace string) error {
+	if namespace == "" {
+		return errors.New("sshsig: empty namespace")
+	}
+	if len(namespace) > 255 {
+		return errors.New("sshsig: namespace longer than 255 bytes")
+	}
+	return nil
+}
+
+// signedData returns the data signed by an SSHSIG signature of message.
+func signedData(message io.Reader, namespace, hashAlgorithm string) ([]byte, error) {
+	h, err := newHash(hashAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(h, message); err != nil {
+		return nil, fmt.Errorf("sshsig: %w", err)
+	}
+	var b cryptobyte.Builder
+	b.AddBytes([]byte(magicPreamble))
+	addString(&b, []byte(namespace))
+	addString(&b, nil) // reserved
+	addString(&b, []byte(hashAlgorithm))
+	addString(&b, h.Sum(nil))
+	return b.BytesOrPanic(), nil
+}
+
+// Sign reads message until EOF and signs it with priv for namespace. hashAlgorithm
+// is the message digest algorithm, HashSHA512 if empty.
+func Sign(priv mldsa.PrivateKey, message io.Reader, namespace, hashAlgorithm string) (*Signature, error) {
+	pub, err := ssh.NewPublicKey(priv.Public().(mldsa.PublicKey))
+	if err != nil {
+		return nil, fmt.Errorf("sshsig: %w", err)
+	}
+	if err := checkNamespace(namespace); err != nil {
+		return nil, err
+	}
+	if hashAlgorithm == "" {
+		hashAlgorithm = HashSHA512
+	}
+	data, err := signedData(message, namespace, hashAlgorithm)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := priv.Sign(nil, data, &options.Options{Context: namespace})
+	if err != nil {
+		return nil, fmt.Errorf("sshsig: %w", err)
+	}
+	return &Signature{PublicKey: pub, Namespace: namespace, HashAlgorithm: hashAlgorithm, signature: sig}, nil
+}
+
+// Verify reads message until EOF, and returns an error unless s is a valid
+// signature of it for namespace under s.PublicKey.
+func (s *Signature) Verify(message io.Reader, namespace string) error {
+	if s.Namespace != namespace {
+		return fmt.Errorf("sshsig: signature namespace %q does not match %q", s.Namespace, namespace)
+	}
+	if err := checkNamespace(namespace); err != nil {
+		return err
+	}
+	data, err := signedData(message, namespace, s.HashAlgorithm)
+	if err != nil {
+		return err
+	}
+	pub := s.PublicKey.CryptoPublicKey().(mldsa.PublicKey)
+	if !pub.VerifyWithOptions(data, s.signature, &options.Options{Context: namespace}) {
+		return errors.New("sshsig: invalid signature")
+	}
+	return nil
+}
+
+// Marshal returns the binary encoding of s.
+func (s *Signature) Marshal() []byte {
+	var b cryptobyte.Builder
+	b.AddBytes([]byte(magicPreamble))
+	b.AddUint32(sigVersion)
+	addString(&b, s.PublicKey.Marshal())
+	addString(&b, []byte(s.Namespace))
+	addString(&b, nil) // reserved
+	addString(&b, []byte(s.HashAlgorithm))
+	b.AddUint32LengthPrefixed(func(b *cryptobyte.Builder) {
+		addString(b, []byte(s.PublicKey.Type()))
+		addString(b, s.signature)
+	})
+	return b.BytesOrPanic()
+}
+
+// Armor returns s in the armored format written by ssh-keygen.
+func (s *Signature) Armor() []byte {
+	encoded := base64.StdEncoding.EncodeToString(s.Marshal())
+	var b strings.Builder
+	b.WriteString(armorBegin + "\n")
+	for len(encoded) > armorWidth {
+		b.WriteString(encoded[:armorWidth] + "\n")
+		encoded = encoded[armorWidth:]
+	}
+	b.WriteString(encoded + "\n")
+	b.WriteString(armorEnd + "\n")
+	return []byte(b.String())
+}
+
+// ParseSignature parses an armored SSHSIG signature made with an ML-DSA key. It
+// does not verify it.
+func ParseSignature(armored []byte) (*Signature, error) {
+	armored = bytes.TrimSpace(armored)
+	body, ok := bytes.CutPrefix(armored, []byte(armorBegin))
+	if !ok {
+		return nil, errors.New("sshsig: missing armor header")
+	}
+	if body, ok = bytes.CutSuffix(body, []byte(armorEnd)); !ok {
+		return nil, errors.New("sshsig: missing armor footer")
+	}
+	blob, err := base64.StdEncoding.DecodeString(string(bytes.Join(bytes.Fields(body), nil)))
+	if err != nil {
+		return nil, fmt.Errorf("sshsig: malformed armor: %w", err)
+	}
+	return parseBlob(blob)
+}
+
+// parseBlob parses the binary encoding of a signature.
+func parseBlob(blob []byte) (*Signature, error) {
+	s := cryptobyte.String(blob)
+	var version uint32
+	var publicKey, namespace, reserved, hashAlgorithm, sigField cryptobyte.String
+	if !bytes.HasPrefix(s, []byte(magicPreamble)) || !s.Skip(len(magicPreamble)) ||
+		!s.ReadUint32(&version) || !readString(&s, &publicKey) || !readString(&s, &namespace) ||
+		!readString(&s, &reserved) || !readString(&s, &hashAlgorithm) || !readString(&s, &sigField) || !s.Empty() {
+		return nil, errors.New("sshsig: malformed signature")
+	}
+	if version != sigVersion {
+		return nil, fmt.Errorf("sshsig: unsupported signature version %d", version)
+	}
+	pub, err := ssh.ParsePublicKey(publicKey)
+	if err != nil {
+		return nil, fmt.Errorf("sshsig: %w", err)
+	}
+	if _, err := newHash(string(hashAlgorithm)); err != nil {
+		return nil, err
+	}
+	var format, sig cryptobyte.String
+	if !readString(&sigField, &format) || !readString(&sigField, &sig) || !sigField.Empty() {
+		return nil, errors.New("sshsig: malformed signature")
+	}
+	if string(format) != pub.Type() {
+		return nil, fmt.Errorf("sshsig: signature type %s for key type %s", format, pub.Type())
+	}
+	return &Signature{
+		PublicKey:     pub,
+		Namespace:     string(namespace),
+		HashAlgorithm: string(hashAlgorithm),
+		signature:     sig,
+	}, nil
+}
+
+// addString appends an SSH string, a byte string with a uint32 length prefix.
+func addString(b *cryptobyte.Builder, s []byte) {
+	b.AddUint32LengthPrefixed(func(b *cryptobyte.Builder) {
+		b.AddBytes(s)
+	})
+}
+
+// readString reads an SSH string.
+func readString(s *cryptobyte.String, out *cryptobyte.String) bool {
+	var n uint32
+	return s.ReadUint32(&n) && s.ReadBytes((*[]byte)(out), int(n))
+}
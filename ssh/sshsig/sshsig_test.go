@@ -0,0 +1,192 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package sshsig
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trailofbits/ml-dsa/mldsa"
+	"github.com/trailofbits/ml-dsa/options"
+	"github.com/trailofbits/ml-dsa/ssh"
+	"golang.org/x/crypto/cryptobyte"
+)
+
+func TestSignVerify(t *testing.T) {
+	msg := "tree 4b825dc642cb6eb9a060e54bf8d69288fbee4904\n"
+	for _, s := range mldsa.Schemes() {
+		for _, hash := range []string{HashSHA256, HashSHA512} {
+			t.Run(s.Name()+"/"+hash, func(t *testing.T) {
+				mldsaPub, priv, err := s.GenerateKey(nil)
+				require.NoError(t, err)
+				pub, err := ssh.NewPublicKey(mldsaPub)
+				require.NoError(t, err)
+				sig, err := Sign(priv, strings.NewReader(msg), "git", hash)
+				assert.NoError(t, err)
+				assert.Equal(t, pub, sig.PublicKey)
+				assert.Equal(t, "git", sig.Namespace)
+				assert.Equal(t, hash, sig.HashAlgorithm)
+				assert.NoError(t, sig.Verify(strings.NewReader(msg), "git"))
+
+				parsed, err := ParseSignature(sig.Armor())
+				assert.NoError(t, err)
+				assert.Equal(t, sig, parsed)
+				assert.NoError(t, parsed.Verify(strings.NewReader(msg), "git"))
+
+				assert.Error(t, sig.Verify(strings.NewReader(msg+"x"), "git"))
+				assert.Error(t, sig.Verify(strings.NewReader(msg), "file"))
+				otherMLDSA, _, err := s.GenerateKey(nil)
+				require.NoError(t, err)
+				other, err := ssh.NewPublicKey(otherMLDSA)
+				require.NoError(t, err)
+				forged := *sig
+				forged.PublicKey = other
+				assert.Error(t, forged.Verify(strings.NewReader(msg), "git"))
+			})
+		}
+	}
+}
+
+func TestSignDefaultHash(t *testing.T) {
+	_, priv, err := mldsa.MLDSA44.GenerateKey(nil)
+	require.NoError(t, err)
+	sig, err := Sign(priv, strings.NewReader("msg"), "file", "")
+	assert.NoError(t, err)
+	assert.Equal(t, HashSHA512, sig.HashAlgorithm)
+
+	_, err = Sign(priv, strings.NewReader("msg"), "file", "sha1")
+	assert.Error(t, err)
+	_, err = Sign(priv, strings.NewReader("msg"), "", "")
+	assert.Error(t, err)
+	_, err = Sign(priv, strings.NewReader("msg"), strings.Repeat("n", 256), "")
+	assert.Error(t, err)
+	_, err = Sign(priv, strings.NewReader("msg"), strings.Repeat("n", 255), "")
+	assert.NoError(t, err)
+}
+
+func TestSignatureFormat(t *testing.T) {
+	mldsaPub, priv, err := mldsa.MLDSA65.GenerateKey(nil)
+	require.NoError(t, err)
+	pub, err := ssh.NewPublicKey(mldsaPub)
+	require.NoError(t, err)
+	sig, err := Sign(priv, strings.NewReader("release.tar.gz"), "file", HashSHA512)
+	assert.NoError(t, err)
+
+	// The armor has the header and footer of ssh-keygen, and 70-column lines
+	lines := strings.Split(strings.TrimSuffix(string(sig.Armor()), "\n"), "\n")
+	assert.Equal(t, "-----BEGIN SSH SIGNATURE-----", lines[0])
+	assert.Equal(t, "-----END SSH SIGNATURE-----", lines[len(lines)-1])
+	for _, line := range lines[1 : len(lines)-2] {
+		assert.Len(t, line, 70)
+	}
+	assert.LessOrEqual(t, len(lines[len(lines)-2]), 70)
+
+	// MAGIC_PREAMBLE || uint32 version || string publickey || string namespace ||
+	// string reserved || string hash_algorithm || string signature
+	blob, err := base64.StdEncoding.DecodeString(strings.Join(lines[1:len(lines)-1], ""))
+	assert.NoError(t, err)
+	assert.Equal(t, sig.Marshal(), blob)
+	s := cryptobyte.String(blob)
+	var version uint32
+	var publicKey, namespace, reserved, hashAlgorithm, sigField, format, mldsaSig cryptobyte.String
+	assert.True(t, s.Skip(6) && s.ReadUint32(&version) && readString(&s, &publicKey) &&
+		readString(&s, &namespace) && readString(&s, &reserved) && readString(&s, &hashAlgorithm) &&
+		readString(&s, &sigField) && s.Empty())
+	assert.Equal(t, "SSHSIG", string(blob[:6]))
+	assert.Equal(t, uint32(1), version)
+	assert.Equal(t, pub.Marshal(), []byte(publicKey))
+	assert.Equal(t, "file", string(namespace))
+	assert.Empty(t, reserved)
+	assert.Equal(t, "sha512", string(hashAlgorithm))
+	assert.True(t, readString(&sigField, &format) && readString(&sigField, &mldsaSig) && sigField.Empty())
+	assert.Equal(t, "ssh-mldsa-65", string(format))
+
+	// The ML-DSA signature covers MAGIC_PREAMBLE || string namespace ||
+	// string reserved || string hash_algorithm || string H(message), with the
+	// namespace as the context string
+	digest := sha512.Sum512([]byte("release.tar.gz"))
+	var b cryptobyte.Builder
+	b.AddBytes([]byte("SSHSIG"))
+	addString(&b, []byte("file"))
+	addString(&b, nil)
+	addString(&b, []byte("sha512"))
+	addString(&b, digest[:])
+	signed := b.BytesOrPanic()
+	key := pub.CryptoPublicKey().(mldsa.PublicKey)
+	assert.True(t, key.VerifyWithOptions(signed, mldsaSig, &options.Options{Context: "file"}))
+	assert.False(t, key.Verify(signed, mldsaSig))
+	assert.False(t, key.VerifyWithOptions(signed, mldsaSig, &options.Options{Context: "git"}))
+}
+
+func TestParseSignatureMalformed(t *testing.T) {
+	mldsaPub, priv, err := mldsa.MLDSA44.GenerateKey(nil)
+	require.NoError(t, err)
+	pub, err := ssh.NewPublicKey(mldsaPub)
+	require.NoError(t, err)
+	sig, err := Sign(priv, strings.NewReader("msg"), "git", "")
+	assert.NoError(t, err)
+	// armor returns the armored blob
+	armor := func(blob []byte) string {
+		return armorBegin + "\n" + base64.StdEncoding.EncodeToString(blob) + "\n" + armorEnd + "\n"
+	}
+	// blob returns a signature blob with the given fields
+	blob := func(version uint32, hashAlgorithm, format string) []byte {
+		var b cryptobyte.Builder
+		b.AddBytes([]byte(magicPreamble))
+		b.AddUint32(version)
+		addString(&b, pub.Marshal())
+		addString(&b, []byte("git"))
+		addString(&b, nil)
+		addString(&b, []byte(hashAlgorithm))
+		b.AddUint32LengthPrefixed(func(b *cryptobyte.Builder) {
+			addString(b, []byte(format))
+			addString(b, sig.signature)
+		})
+		return b.BytesOrPanic()
+	}
+	_, err = ParseSignature([]byte(armor(blob(1, "sha512", "ssh-mldsa-44"))))
+	assert.NoError(t, err)
+
+	edKey := append(sshString([]byte("ssh-ed25519")), sshString(make([]byte, 32))...)
+	for name, in := range map[string]string{
+		"empty":     "",
+		"header":    strings.TrimPrefix(string(sig.Armor()), "-----BEGIN SSH SIGNATURE-----"),
+		"footer":    strings.TrimSuffix(string(sig.Armor()), "-----END SSH SIGNATURE-----\n"),
+		"PEM type":  strings.ReplaceAll(string(sig.Armor()), "SSH SIGNATURE", "PRIVATE KEY"),
+		"base64":    armorBegin + "\n!!!!\n" + armorEnd,
+		"magic":     armor(append([]byte("SSHSIH"), sig.Marshal()[6:]...)),
+		"truncated": armor(sig.Marshal()[:100]),
+		"trailing":  armor(append(sig.Marshal(), 0)),
+		"version":   armor(blob(2, "sha512", "ssh-mldsa-44")),
+		"hash":      armor(blob(1, "sha1", "ssh-mldsa-44")),
+		"format":    armor(blob(1, "sha512", "ssh-mldsa-65")),
+		"key type":  armor(bytes.Replace(blob(1, "sha512", "ssh-mldsa-44"), sshString(pub.Marshal()), sshString(edKey), 1)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ParseSignature([]byte(in))
+			assert.Error(t, err)
+		})
+	}
+}
+
+// sshString returns s with its uint32 length prefix.
+func sshString(s []byte) []byte {
+	var b cryptobyte.Builder
+	addString(&b, s)
+	return b.BytesOrPanic()
+}
+
+func TestSignedDataSHA256(t *testing.T) {
+	data, err := signedData(strings.NewReader("msg"), "git", HashSHA256)
+	assert.NoError(t, err)
+	digest := sha256.Sum256([]byte("msg"))
+	assert.True(t, bytes.HasSuffix(data, sshString(digest[:])))
+}
@@ -0,0 +1,219 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package x509
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"io"
+
+	"github.com/trailofbits/ml-dsa/mldsa"
+	"golang.org/x/crypto/cryptobyte"
+	cbasn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// oidExtensionRequest is the PKCS #9 extensionRequest attribute (RFC 2985, Section 5.4.2).
+var oidExtensionRequest = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 14}
+
+// CertificateRequest represents a PKCS #10 certificate signature request
+// (RFC 2986) with an ML-DSA public key.
+type CertificateRequest struct {
+	Raw                      []byte // Complete ASN.1 DER content (CSR, signature algorithm and signature).
+	RawTBSCertificateRequest []byte // Certificate request info part of raw ASN.1 DER content.
+	RawSubjectPublicKeyInfo  []byte // DER encoded SubjectPublicKeyInfo.
+	RawSubject               []byte // DER encoded Subject.
+
+	Version            int
+	Signature          []byte
+	SignatureAlgorithm asn1.ObjectIdentifier
+
+	PublicKey mldsa.PublicKey
+
+	Subject pkix.Name
+
+	// Extensions contains all requested extensions, in raw form. When parsing
+	// CSRs, this can be used to extract extensions that are not parsed by this
+	// package.
+	Extensions []pkix.Extension
+
+	// ExtraExtensions contains extensions to be copied, raw, into any CSR
+	// marshaled by CreateCertificateRequest. Values override any extensions
+	// that would otherwise be produced based on the other fields.
+	ExtraExtensions []pkix.Extension
+
+	// DNSNames are the DNS names of the requested Subject Alternate Name extension.
+	DNSNames []string
+}
+
+// CreateCertificateRequest creates a new certificate request based on a
+// template, and returns it in DER form. The following members of template
+// are used:
+//
+//   - DNSNames
+//   - ExtraExtensions
+//   - Subject
+//
+// The request contains the public key of priv, and is signed by priv with
+// pure ML-DSA and the empty context. If rand is nil, [crypto/rand] is used.
+//
+// [crypto/rand]: https://pkg.go.dev/crypto/rand
+func CreateCertificateRequest(rand io.Reader, template *CertificateRequest, priv mldsa.PrivateKey) ([]byte, error) {
+	scheme := mldsa.SchemeOf(priv)
+	if scheme == nil {
+		return nil, errors.New("x509: unsupported private key type")
+	}
+
+	spki, err := mldsa.MarshalPKIXPublicKey(priv.Public().(mldsa.PublicKey))
+	if err != nil {
+		return nil, err
+	}
+	subject, err := marshalName(template.RawSubject, template.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	var extensions []pkix.Extension
+	if len(template.DNSNames) > 0 && !oidInExtensions(oidExtensionSubjectAltName, template.ExtraExtensions) {
+		ext, err := marshalSANs(template.DNSNames, string(subject) == string(emptyName))
+		if err != nil {
+			return nil, err
+		}
+		extensions = append(extensions, ext)
+	}
+	extensions = append(extensions, template.ExtraExtensions...)
+
+	var b cryptobyte.Builder
+	b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1Int64(0) // v1
+		b.AddBytes(subject)
+		b.AddBytes(spki)
+		// The attributes are required, even if empty
+		b.AddASN1(cbasn1.Tag(0).Constructed().ContextSpecific(), func(b *cryptobyte.Builder) {
+			if len(extensions) == 0 {
+				return
+			}
+			b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+				b.AddASN1ObjectIdentifier(oidExtensionRequest)
+				b.AddASN1(cbasn1.SET, func(b *cryptobyte.Builder) {
+					addExtensions(b, extensions)
+				})
+			})
+		})
+	})
+	tbs, err := b.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	return signTBS(rand, tbs, scheme, priv)
+}
+
+// ParseCertificateRequest parses a single certificate request from the given
+// ASN.1 DER data. The public key of the request must be an ML-DSA key.
+//
+// The signature is not checked; see [CertificateRequest.CheckSignature].
+func ParseCertificateRequest(der []byte) (*CertificateRequest, error) {
+	csr := new(CertificateRequest)
+
+	outer := cryptobyte.String(der)
+	var input cryptobyte.String
+	if !outer.ReadASN1Element(&input, cbasn1.SEQUENCE) || !outer.Empty() {
+		return nil, errors.New("x509: malformed certificate request")
+	}
+	csr.Raw = input
+	if !input.ReadASN1(&input, cbasn1.SEQUENCE) {
+		return nil, errors.New("x509: malformed certificate request")
+	}
+
+	var tbs cryptobyte.String
+	if !input.ReadASN1Element(&tbs, cbasn1.SEQUENCE) {
+		return nil, errors.New("x509: malformed certificate request info")
+	}
+	csr.RawTBSCertificateRequest = tbs
+	if !tbs.ReadASN1(&tbs, cbasn1.SEQUENCE) || !tbs.ReadASN1Integer(&csr.Version) {
+		return nil, errors.New("x509: malformed certificate request info")
+	}
+	if csr.Version != 0 {
+		return nil, errors.New("x509: unsupported certificate request version")
+	}
+
+	var err error
+	if csr.RawSubject, csr.Subject, err = readName(&tbs); err != nil {
+		return nil, err
+	}
+
+	var spki cryptobyte.String
+	if !tbs.ReadASN1Element(&spki, cbasn1.SEQUENCE) {
+		return nil, errors.New("x509: malformed spki")
+	}
+	csr.RawSubjectPublicKeyInfo = spki
+	if csr.PublicKey, err = mldsa.ParsePKIXPublicKey(spki); err != nil {
+		return nil, err
+	}
+
+	var attributes cryptobyte.String
+	if !tbs.ReadASN1(&attributes, cbasn1.Tag(0).Constructed().ContextSpecific()) || !tbs.Empty() {
+		return nil, errors.New("x509: malformed attributes")
+	}
+	if csr.Extensions, err = readExtensionRequest(attributes); err != nil {
+		return nil, err
+	}
+	for _, e := range csr.Extensions {
+		if e.Id.Equal(oidExtensionSubjectAltName) {
+			if csr.DNSNames, err = parseSANs(e.Value); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	var signatureAlgorithm cryptobyte.String
+	if !input.ReadASN1Element(&signatureAlgorithm, cbasn1.SEQUENCE) {
+		return nil, errors.New("x509: malformed signature algorithm identifier")
+	}
+	if csr.SignatureAlgorithm, err = readSignatureAlgorithm(signatureAlgorithm); err != nil {
+		return nil, err
+	}
+	if csr.Signature, err = readSignature(&input); err != nil {
+		return nil, err
+	}
+	if !input.Empty() {
+		return nil, errors.New("x509: malformed certificate request")
+	}
+	return csr, nil
+}
+
+// readExtensionRequest returns the extensions of the extensionRequest attribute, if any.
+// Other attributes are ignored.
+func readExtensionRequest(attributes cryptobyte.String) ([]pkix.Extension, error) {
+	var extensions []pkix.Extension
+	var found bool
+	for !attributes.Empty() {
+		var attribute, values cryptobyte.String
+		var oid asn1.ObjectIdentifier
+		if !attributes.ReadASN1(&attribute, cbasn1.SEQUENCE) ||
+			!attribute.ReadASN1ObjectIdentifier(&oid) ||
+			!attribute.ReadASN1(&values, cbasn1.SET) || !attribute.Empty() {
+			return nil, errors.New("x509: malformed attribute")
+		}
+		if !oid.Equal(oidExtensionRequest) {
+			continue
+		}
+		if found {
+			return nil, errors.New("x509: duplicate extension request attribute")
+		}
+		found = true
+		var err error
+		if extensions, err = readExtensions(values); err != nil {
+			return nil, err
+		}
+	}
+	return extensions, nil
+}
+
+// CheckSignature reports whether the signature on c is valid.
+func (c *CertificateRequest) CheckSignature() error {
+	return checkSignature(c.SignatureAlgorithm, c.RawTBSCertificateRequest, c.Signature, c.PublicKey)
+}
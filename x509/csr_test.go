@@ -0,0 +1,94 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package x509_test
+
+import (
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trailofbits/ml-dsa/mldsa"
+	"github.com/trailofbits/ml-dsa/x509"
+)
+
+func TestCertificateRequest(t *testing.T) {
+	ext := pkix.Extension{Id: asn1.ObjectIdentifier{1, 2, 3}, Value: []byte{0x05, 0x00}}
+	for _, s := range mldsa.Schemes() {
+		t.Run(s.Name(), func(t *testing.T) {
+			pub, priv, err := s.GenerateKey(rand.Reader)
+			require.NoError(t, err)
+			der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+				Subject:         pkix.Name{CommonName: "example.com"},
+				DNSNames:        []string{"example.com"},
+				ExtraExtensions: []pkix.Extension{ext},
+			}, priv)
+			assert.NoError(t, err)
+
+			csr, err := x509.ParseCertificateRequest(der)
+			assert.NoError(t, err)
+			assert.NoError(t, csr.CheckSignature())
+			assert.Equal(t, der, csr.Raw)
+			assert.Equal(t, 0, csr.Version)
+			assert.Equal(t, pub, csr.PublicKey)
+			assert.Equal(t, s.OID(), csr.SignatureAlgorithm)
+			assert.Equal(t, "example.com", csr.Subject.CommonName)
+			assert.Equal(t, []string{"example.com"}, csr.DNSNames)
+			assert.Len(t, csr.Extensions, 2)
+			assert.Equal(t, ext, csr.Extensions[1])
+
+			csr.Signature[0] ^= 1
+			assert.Error(t, csr.CheckSignature())
+		})
+	}
+}
+
+func TestCertificateRequestWithoutExtensions(t *testing.T) {
+	_, priv, err := mldsa.MLDSA44.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.CreateCertificateRequest(nil, &x509.CertificateRequest{
+		Subject: pkix.Name{CommonName: "test"},
+	}, priv)
+	assert.NoError(t, err)
+	csr, err := x509.ParseCertificateRequest(der)
+	assert.NoError(t, err)
+	assert.NoError(t, csr.CheckSignature())
+	assert.Empty(t, csr.Extensions)
+
+	_, err = x509.ParseCertificateRequest(der[:len(der)-1])
+	assert.Error(t, err)
+	_, err = x509.ParseCertificateRequest(append(der, 0))
+	assert.Error(t, err)
+}
+
+// A CA issues a certificate for the key and subject of a CSR.
+func TestCertificateRequestIssuance(t *testing.T) {
+	root := newCA(t, mldsa.MLDSA87, "Root CA", nil, -1)
+	_, priv, err := mldsa.MLDSA65.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	der, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: "service"},
+		DNSNames: []string{"service.internal"},
+	}, priv)
+	assert.NoError(t, err)
+
+	csr, err := x509.ParseCertificateRequest(der)
+	assert.NoError(t, err)
+	assert.NoError(t, csr.CheckSignature())
+	leaf := issue(t, &x509.Certificate{
+		RawSubject: csr.RawSubject,
+		DNSNames:   csr.DNSNames,
+		KeyUsage:   x509.KeyUsageDigitalSignature,
+	}, root, csr.PublicKey, nil)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root.cert)
+	_, err = leaf.Verify(x509.VerifyOptions{Roots: roots, CurrentTime: now})
+	assert.NoError(t, err)
+	assert.Equal(t, "service", leaf.Subject.CommonName)
+	assert.Equal(t, priv.Public(), leaf.PublicKey)
+}
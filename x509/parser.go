@@ -0,0 +1,316 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package x509
+
+import (
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"math/big"
+	"time"
+
+	"github.com/trailofbits/ml-dsa/mldsa"
+	"golang.org/x/crypto/cryptobyte"
+	cbasn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// ParseCertificate parses a single certificate from the given ASN.1 DER data.
+// The subject public key of the certificate must be an ML-DSA key.
+func ParseCertificate(der []byte) (*Certificate, error) {
+	cert := new(Certificate)
+
+	outer := cryptobyte.String(der)
+	// Read the certificate with its tag and length, to populate Raw
+	var input cryptobyte.String
+	if !outer.ReadASN1Element(&input, cbasn1.SEQUENCE) || !outer.Empty() {
+		return nil, errors.New("x509: malformed certificate")
+	}
+	cert.Raw = input
+	if !input.ReadASN1(&input, cbasn1.SEQUENCE) {
+		return nil, errors.New("x509: malformed certificate")
+	}
+
+	var tbs cryptobyte.String
+	if !input.ReadASN1Element(&tbs, cbasn1.SEQUENCE) {
+		return nil, errors.New("x509: malformed tbs certificate")
+	}
+	cert.RawTBSCertificate = tbs
+	if !tbs.ReadASN1(&tbs, cbasn1.SEQUENCE) {
+		return nil, errors.New("x509: malformed tbs certificate")
+	}
+
+	if !tbs.ReadOptionalASN1Integer(&cert.Version, cbasn1.Tag(0).Constructed().ContextSpecific(), 0) {
+		return nil, errors.New("x509: malformed version")
+	}
+	if cert.Version < 0 || cert.Version > 2 {
+		return nil, errors.New("x509: invalid version")
+	}
+	// For backwards compatibility with crypto/x509, Version is 1-indexed
+	cert.Version++
+
+	serial := new(big.Int)
+	if !tbs.ReadASN1Integer(serial) {
+		return nil, errors.New("x509: malformed serial number")
+	}
+	cert.SerialNumber = serial
+
+	var tbsSignatureAlgorithm cryptobyte.String
+	if !tbs.ReadASN1Element(&tbsSignatureAlgorithm, cbasn1.SEQUENCE) {
+		return nil, errors.New("x509: malformed signature algorithm identifier")
+	}
+
+	var err error
+	if cert.RawIssuer, cert.Issuer, err = readName(&tbs); err != nil {
+		return nil, err
+	}
+
+	var validity cryptobyte.String
+	if !tbs.ReadASN1(&validity, cbasn1.SEQUENCE) {
+		return nil, errors.New("x509: malformed validity")
+	}
+	if cert.NotBefore, err = readTime(&validity); err != nil {
+		return nil, err
+	}
+	if cert.NotAfter, err = readTime(&validity); err != nil {
+		return nil, err
+	}
+	if !validity.Empty() {
+		return nil, errors.New("x509: malformed validity")
+	}
+
+	if cert.RawSubject, cert.Subject, err = readName(&tbs); err != nil {
+		return nil, err
+	}
+
+	var spki cryptobyte.String
+	if !tbs.ReadASN1Element(&spki, cbasn1.SEQUENCE) {
+		return nil, errors.New("x509: malformed spki")
+	}
+	cert.RawSubjectPublicKeyInfo = spki
+	if cert.PublicKey, err = mldsa.ParsePKIXPublicKey(spki); err != nil {
+		return nil, err
+	}
+
+	if cert.Version > 1 {
+		if !tbs.SkipOptionalASN1(cbasn1.Tag(1).ContextSpecific()) ||
+			!tbs.SkipOptionalASN1(cbasn1.Tag(2).ContextSpecific()) {
+			return nil, errors.New("x509: malformed unique identifier")
+		}
+	}
+	if cert.Version == 3 {
+		var extensions cryptobyte.String
+		var present bool
+		if !tbs.ReadOptionalASN1(&extensions, &present, cbasn1.Tag(3).Constructed().ContextSpecific()) {
+			return nil, errors.New("x509: malformed extensions")
+		}
+		if present {
+			if cert.Extensions, err = readExtensions(extensions); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if !tbs.Empty() {
+		return nil, errors.New("x509: malformed tbs certificate")
+	}
+
+	var signatureAlgorithm cryptobyte.String
+	if !input.ReadASN1Element(&signatureAlgorithm, cbasn1.SEQUENCE) {
+		return nil, errors.New("x509: malformed signature algorithm identifier")
+	}
+	// The signature algorithm must be the same in the TBSCertificate (RFC 5280, Section 4.1.1.2)
+	if string(signatureAlgorithm) != string(tbsSignatureAlgorithm) {
+		return nil, errors.New("x509: inner and outer signature algorithm identifiers don't match")
+	}
+	if cert.SignatureAlgorithm, err = readSignatureAlgorithm(signatureAlgorithm); err != nil {
+		return nil, err
+	}
+
+	if cert.Signature, err = readSignature(&input); err != nil {
+		return nil, err
+	}
+	if !input.Empty() {
+		return nil, errors.New("x509: malformed certificate")
+	}
+
+	if err := processExtensions(cert); err != nil {
+		return nil, err
+	}
+	return cert, nil
+}
+
+// readSignatureAlgorithm reads the OID of an AlgorithmIdentifier. Parameters are allowed,
+// so that certificates signed with other algorithms can be parsed, but not verified.
+func readSignatureAlgorithm(der cryptobyte.String) (asn1.ObjectIdentifier, error) {
+	var oid asn1.ObjectIdentifier
+	if !der.ReadASN1(&der, cbasn1.SEQUENCE) || !der.ReadASN1ObjectIdentifier(&oid) {
+		return nil, errors.New("x509: malformed signature algorithm identifier")
+	}
+	if mldsa.SchemeByOID(oid) != nil && !der.Empty() {
+		return nil, errors.New("x509: ML-DSA signature algorithm identifier must have absent parameters")
+	}
+	return oid, nil
+}
+
+func readSignature(input *cryptobyte.String) ([]byte, error) {
+	var signature asn1.BitString
+	if !input.ReadASN1BitString(&signature) || signature.BitLength%8 != 0 {
+		return nil, errors.New("x509: malformed signature")
+	}
+	return signature.Bytes, nil
+}
+
+func readName(input *cryptobyte.String) ([]byte, pkix.Name, error) {
+	var raw cryptobyte.String
+	if !input.ReadASN1Element(&raw, cbasn1.SEQUENCE) {
+		return nil, pkix.Name{}, errors.New("x509: malformed name")
+	}
+	var rdns pkix.RDNSequence
+	if rest, err := asn1.Unmarshal(raw, &rdns); err != nil || len(rest) != 0 {
+		return nil, pkix.Name{}, errors.New("x509: malformed name")
+	}
+	var name pkix.Name
+	name.FillFromRDNSequence(&rdns)
+	return raw, name, nil
+}
+
+func readTime(input *cryptobyte.String) (time.Time, error) {
+	var t time.Time
+	switch {
+	case input.PeekASN1Tag(cbasn1.UTCTime):
+		if !input.ReadASN1UTCTime(&t) {
+			return t, errors.New("x509: malformed UTCTime")
+		}
+	case input.PeekASN1Tag(cbasn1.GeneralizedTime):
+		if !input.ReadASN1GeneralizedTime(&t) {
+			return t, errors.New("x509: malformed GeneralizedTime")
+		}
+	default:
+		return t, errors.New("x509: unsupported time format")
+	}
+	return t, nil
+}
+
+func readExtensions(der cryptobyte.String) ([]pkix.Extension, error) {
+	var exts cryptobyte.String
+	if !der.ReadASN1(&exts, cbasn1.SEQUENCE) || !der.Empty() || exts.Empty() {
+		return nil, errors.New("x509: malformed extensions")
+	}
+	var extensions []pkix.Extension
+	seen := make(map[string]bool)
+	for !exts.Empty() {
+		var extension cryptobyte.String
+		var ext pkix.Extension
+		var value cryptobyte.String
+		if !exts.ReadASN1(&extension, cbasn1.SEQUENCE) ||
+			!extension.ReadASN1ObjectIdentifier(&ext.Id) {
+			return nil, errors.New("x509: malformed extension")
+		}
+		if extension.PeekASN1Tag(cbasn1.BOOLEAN) && !extension.ReadASN1Boolean(&ext.Critical) {
+			return nil, errors.New("x509: malformed extension critical field")
+		}
+		if !extension.ReadASN1(&value, cbasn1.OCTET_STRING) || !extension.Empty() {
+			return nil, errors.New("x509: malformed extension")
+		}
+		ext.Value = value
+		// Each extension may appear at most once (RFC 5280, Section 4.2)
+		if seen[ext.Id.String()] {
+			return nil, errors.New("x509: duplicate extension " + ext.Id.String())
+		}
+		seen[ext.Id.String()] = true
+		extensions = append(extensions, ext)
+	}
+	return extensions, nil
+}
+
+func processExtensions(cert *Certificate) error {
+	for _, e := range cert.Extensions {
+		var err error
+		switch {
+		case e.Id.Equal(oidExtensionKeyUsage):
+			cert.KeyUsage, err = parseKeyUsage(e.Value)
+		case e.Id.Equal(oidExtensionBasicConstraints):
+			cert.IsCA, cert.MaxPathLen, err = parseBasicConstraints(e.Value)
+			cert.BasicConstraintsValid = true
+			cert.MaxPathLenZero = cert.MaxPathLen == 0
+		case e.Id.Equal(oidExtensionExtendedKeyUsage):
+			err = unmarshalExtension(e.Value, &cert.ExtKeyUsage)
+		case e.Id.Equal(oidExtensionSubjectKeyId):
+			err = unmarshalExtension(e.Value, &cert.SubjectKeyId)
+		case e.Id.Equal(oidExtensionAuthorityKeyId):
+			cert.AuthorityKeyId, err = parseAuthorityKeyId(e.Value)
+		case e.Id.Equal(oidExtensionSubjectAltName):
+			cert.DNSNames, err = parseSANs(e.Value)
+		default:
+			if e.Critical {
+				cert.UnhandledCriticalExtensions = append(cert.UnhandledCriticalExtensions, e.Id)
+			}
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func unmarshalExtension(der []byte, out any) error {
+	if rest, err := asn1.Unmarshal(der, out); err != nil || len(rest) != 0 {
+		return errors.New("x509: malformed extension")
+	}
+	return nil
+}
+
+func parseKeyUsage(der []byte) (KeyUsage, error) {
+	var bs asn1.BitString
+	if err := unmarshalExtension(der, &bs); err != nil {
+		return 0, err
+	}
+	var usage KeyUsage
+	for i := 0; i < 9; i++ {
+		if bs.At(i) != 0 {
+			usage |= 1 << uint(i)
+		}
+	}
+	return usage, nil
+}
+
+func parseBasicConstraints(der []byte) (bool, int, error) {
+	bc := basicConstraints{MaxPathLen: -1}
+	if err := unmarshalExtension(der, &bc); err != nil {
+		return false, 0, err
+	}
+	return bc.IsCA, bc.MaxPathLen, nil
+}
+
+func parseAuthorityKeyId(der cryptobyte.String) ([]byte, error) {
+	var akid cryptobyte.String
+	var keyId cryptobyte.String
+	var present bool
+	if !der.ReadASN1(&akid, cbasn1.SEQUENCE) || !der.Empty() ||
+		!akid.ReadOptionalASN1(&keyId, &present, cbasn1.Tag(0).ContextSpecific()) {
+		return nil, errors.New("x509: malformed authority key identifier")
+	}
+	// The optional authorityCertIssuer and authorityCertSerialNumber are ignored
+	return keyId, nil
+}
+
+func parseSANs(der cryptobyte.String) ([]string, error) {
+	var names cryptobyte.String
+	if !der.ReadASN1(&names, cbasn1.SEQUENCE) || !der.Empty() {
+		return nil, errors.New("x509: malformed subject alternative name")
+	}
+	var dnsNames []string
+	for !names.Empty() {
+		var name cryptobyte.String
+		var tag cbasn1.Tag
+		if !names.ReadAnyASN1(&name, &tag) {
+			return nil, errors.New("x509: malformed subject alternative name")
+		}
+		// Other name types are ignored
+		if tag == cbasn1.Tag(nameTypeDNS).ContextSpecific() {
+			dnsNames = append(dnsNames, string(name))
+		}
+	}
+	return dnsNames, nil
+}
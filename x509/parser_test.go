@@ -0,0 +1,25 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package x509
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadExtensions(t *testing.T) {
+	// SEQUENCE { SEQUENCE { OID 1.2.3, OCTET STRING { NULL } } }
+	exts := []byte{0x30, 0x0a, 0x30, 0x08, 0x06, 0x02, 0x2a, 0x03, 0x04, 0x02, 0x05, 0x00}
+	extensions, err := readExtensions(exts)
+	assert.NoError(t, err)
+	assert.Len(t, extensions, 1)
+
+	// Data after the SEQUENCE used to be ignored
+	_, err = readExtensions(append(exts, 0x05, 0x00))
+	assert.Error(t, err)
+	_, err = readExtensions([]byte{0x30, 0x00})
+	assert.Error(t, err)
+}
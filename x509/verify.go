@@ -0,0 +1,202 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package x509
+
+import (
+	"bytes"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// maxChainLength bounds the number of certificates in a chain, including the leaf and the root.
+const maxChainLength = 10
+
+// CertPool is a set of certificates.
+type CertPool struct {
+	certs []*Certificate
+}
+
+// NewCertPool returns a new, empty CertPool.
+func NewCertPool() *CertPool {
+	return &CertPool{}
+}
+
+// AddCert adds a certificate to a pool.
+func (s *CertPool) AddCert(cert *Certificate) {
+	if cert == nil {
+		panic("adding nil Certificate to CertPool")
+	}
+	if s.contains(cert) {
+		return
+	}
+	s.certs = append(s.certs, cert)
+}
+
+// AppendCertsFromPEM attempts to parse a series of PEM encoded certificates.
+// It appends any certificates found to s and reports whether any certificates
+// were successfully parsed. Certificates without an ML-DSA public key are skipped.
+func (s *CertPool) AppendCertsFromPEM(pemCerts []byte) (ok bool) {
+	for len(pemCerts) > 0 {
+		var block *pem.Block
+		block, pemCerts = pem.Decode(pemCerts)
+		if block == nil {
+			break
+		}
+		if block.Type != "CERTIFICATE" || len(block.Headers) != 0 {
+			continue
+		}
+		cert, err := ParseCertificate(block.Bytes)
+		if err != nil {
+			continue
+		}
+		s.AddCert(cert)
+		ok = true
+	}
+	return ok
+}
+
+func (s *CertPool) contains(cert *Certificate) bool {
+	for _, c := range s.certs {
+		if c.Equal(cert) {
+			return true
+		}
+	}
+	return false
+}
+
+// findPotentialParents returns the certificates of s that may have issued cert,
+// based on their subject and key identifier.
+func (s *CertPool) findPotentialParents(cert *Certificate) []*Certificate {
+	if s == nil {
+		return nil
+	}
+	var candidates []*Certificate
+	for _, c := range s.certs {
+		if !bytes.Equal(c.RawSubject, cert.RawIssuer) {
+			continue
+		}
+		if len(c.SubjectKeyId) > 0 && len(cert.AuthorityKeyId) > 0 &&
+			!bytes.Equal(c.SubjectKeyId, cert.AuthorityKeyId) {
+			continue
+		}
+		candidates = append(candidates, c)
+	}
+	return candidates
+}
+
+// VerifyOptions contains parameters for Certificate.Verify.
+type VerifyOptions struct {
+	// Intermediates is an optional pool of certificates that are not trust
+	// anchors, but can be used to form a chain from the leaf certificate to a
+	// root certificate.
+	Intermediates *CertPool
+	// Roots is the set of trusted root certificates the leaf certificate needs
+	// to chain up to. It must not be nil.
+	Roots *CertPool
+
+	// CurrentTime is used to check the validity of all certificates in the
+	// chain. If zero, the current time is used.
+	CurrentTime time.Time
+}
+
+// Verify attempts to verify c by building one or more chains from c to a
+// certificate in opts.Roots, using certificates in opts.Intermediates if needed.
+// If successful, it returns one or more chains where the first element of the
+// chain is c and the last element is from opts.Roots.
+//
+// Every signature in a chain must be an ML-DSA signature with the empty context,
+// and every certificate must be valid at opts.CurrentTime. Issuers must be CAs,
+// and their path length constraints are enforced. Certificates with unhandled
+// critical extensions are rejected.
+//
+// Unlike crypto/x509, name constraints, extended key usages and host names are
+// not checked. The signatures of root certificates are not checked either.
+func (c *Certificate) Verify(opts VerifyOptions) ([][]*Certificate, error) {
+	if opts.Roots == nil {
+		return nil, errors.New("x509: no root certificates")
+	}
+	if opts.CurrentTime.IsZero() {
+		opts.CurrentTime = time.Now()
+	}
+	if err := c.isValid(opts.CurrentTime); err != nil {
+		return nil, err
+	}
+
+	// A trusted certificate is a chain on its own
+	if opts.Roots.contains(c) {
+		return [][]*Certificate{{c}}, nil
+	}
+
+	var hint error
+	chains := buildChains(opts, []*Certificate{c}, &hint)
+	if len(chains) == 0 {
+		if hint != nil {
+			return nil, fmt.Errorf("x509: certificate signed by unknown authority (possibly because of %q while trying to verify candidate authority certificate)", hint)
+		}
+		return nil, errors.New("x509: certificate signed by unknown authority")
+	}
+	return chains, nil
+}
+
+func buildChains(opts VerifyOptions, chain []*Certificate, hint *error) [][]*Certificate {
+	cert := chain[len(chain)-1]
+	var chains [][]*Certificate
+
+	consider := func(candidate *Certificate, isRoot bool) {
+		for _, c := range chain {
+			if c.Equal(candidate) {
+				return
+			}
+		}
+		if err := checkParent(opts, chain, candidate); err != nil {
+			if *hint == nil {
+				*hint = err
+			}
+			return
+		}
+		next := append(chain[:len(chain):len(chain)], candidate)
+		if isRoot {
+			chains = append(chains, next)
+		} else if len(next) < maxChainLength {
+			chains = append(chains, buildChains(opts, next, hint)...)
+		}
+	}
+
+	for _, root := range opts.Roots.findPotentialParents(cert) {
+		consider(root, true)
+	}
+	for _, intermediate := range opts.Intermediates.findPotentialParents(cert) {
+		consider(intermediate, false)
+	}
+	return chains
+}
+
+// checkParent reports whether parent is a valid issuer of the last certificate of chain.
+func checkParent(opts VerifyOptions, chain []*Certificate, parent *Certificate) error {
+	if err := parent.isValid(opts.CurrentTime); err != nil {
+		return err
+	}
+	// The number of intermediate certificates below parent, excluding the leaf
+	if parent.BasicConstraintsValid && (parent.MaxPathLen > 0 || parent.MaxPathLenZero) &&
+		len(chain)-1 > parent.MaxPathLen {
+		return errors.New("x509: too many intermediates for path length constraint")
+	}
+	return chain[len(chain)-1].CheckSignatureFrom(parent)
+}
+
+func (c *Certificate) isValid(now time.Time) error {
+	if len(c.UnhandledCriticalExtensions) > 0 {
+		return errors.New("x509: unhandled critical extension")
+	}
+	if now.Before(c.NotBefore) {
+		return fmt.Errorf("x509: certificate is not valid before %s", c.NotBefore.Format(time.RFC3339))
+	}
+	if now.After(c.NotAfter) {
+		return fmt.Errorf("x509: certificate has expired at %s", c.NotAfter.Format(time.RFC3339))
+	}
+	return nil
+}
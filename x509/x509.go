@@ -0,0 +1,461 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// Package x509 creates, parses and verifies X.509 certificates with ML-DSA keys and
+// signatures, as specified in [RFC 9881]. It implements the subset of [RFC 5280]
+// needed for a PKI where every certificate is signed with ML-DSA, with an API
+// modeled after [crypto/x509].
+//
+// Certificates are signed with pure ML-DSA and the empty context string.
+//
+// [RFC 9881]: https://www.rfc-editor.org/rfc/rfc9881
+// [RFC 5280]: https://www.rfc-editor.org/rfc/rfc5280
+// [crypto/x509]: https://pkg.go.dev/crypto/x509
+package x509
+
+import (
+	"bytes"
+	cryptorand "crypto/rand"
+	"crypto/sha256"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"io"
+	"math/big"
+	"time"
+
+	"github.com/trailofbits/ml-dsa/mldsa"
+	"golang.org/x/crypto/cryptobyte"
+	cbasn1 "golang.org/x/crypto/cryptobyte/asn1"
+)
+
+// KeyUsage represents the set of actions that are valid for a given key.
+// It's a bitmap of the KeyUsage* constants, with the same values as in crypto/x509.
+type KeyUsage int
+
+const (
+	KeyUsageDigitalSignature KeyUsage = 1 << iota
+	KeyUsageContentCommitment
+	KeyUsageKeyEncipherment
+	KeyUsageDataEncipherment
+	KeyUsageKeyAgreement
+	KeyUsageCertSign
+	KeyUsageCRLSign
+	KeyUsageEncipherOnly
+	KeyUsageDecipherOnly
+)
+
+var (
+	oidExtensionSubjectKeyId     = asn1.ObjectIdentifier{2, 5, 29, 14}
+	oidExtensionKeyUsage         = asn1.ObjectIdentifier{2, 5, 29, 15}
+	oidExtensionSubjectAltName   = asn1.ObjectIdentifier{2, 5, 29, 17}
+	oidExtensionBasicConstraints = asn1.ObjectIdentifier{2, 5, 29, 19}
+	oidExtensionAuthorityKeyId   = asn1.ObjectIdentifier{2, 5, 29, 35}
+	oidExtensionExtendedKeyUsage = asn1.ObjectIdentifier{2, 5, 29, 37}
+)
+
+// nameTypeDNS is the GeneralName tag of a dNSName.
+const nameTypeDNS = 2
+
+// Certificate represents an X.509 certificate with an ML-DSA subject public key.
+type Certificate struct {
+	Raw                     []byte // Complete ASN.1 DER content (certificate, signature algorithm and signature).
+	RawTBSCertificate       []byte // Certificate part of raw ASN.1 DER content.
+	RawSubjectPublicKeyInfo []byte // DER encoded SubjectPublicKeyInfo.
+	RawSubject              []byte // DER encoded Subject
+	RawIssuer               []byte // DER encoded Issuer
+
+	Signature []byte
+	// SignatureAlgorithm is the object identifier of the signature algorithm.
+	// Only the ML-DSA algorithms returned by [mldsa.Scheme.OID] can be verified.
+	SignatureAlgorithm asn1.ObjectIdentifier
+
+	PublicKey mldsa.PublicKey
+
+	Version             int
+	SerialNumber        *big.Int
+	Issuer              pkix.Name
+	Subject             pkix.Name
+	NotBefore, NotAfter time.Time // Validity bounds.
+	KeyUsage            KeyUsage
+
+	// Extensions contains raw X.509 extensions. When parsing certificates,
+	// this can be used to extract non-critical extensions that are not
+	// parsed by this package. When marshaling certificates, the Extensions
+	// field is ignored, see ExtraExtensions.
+	Extensions []pkix.Extension
+
+	// ExtraExtensions contains extensions to be copied, raw, into any
+	// marshaled certificates. Values override any extensions that would
+	// otherwise be produced based on the other fields. The ExtraExtensions
+	// field is not populated when parsing certificates, see Extensions.
+	ExtraExtensions []pkix.Extension
+
+	// UnhandledCriticalExtensions contains a list of extension IDs that
+	// were not (fully) processed when parsing. Verify will fail if this
+	// slice is non-empty.
+	UnhandledCriticalExtensions []asn1.ObjectIdentifier
+
+	// ExtKeyUsage is the sequence of extended key usage object identifiers.
+	ExtKeyUsage []asn1.ObjectIdentifier
+
+	// BasicConstraintsValid indicates whether IsCA, MaxPathLen,
+	// and MaxPathLenZero are valid.
+	BasicConstraintsValid bool
+	IsCA                  bool
+
+	// MaxPathLen and MaxPathLenZero indicate the presence and
+	// value of the BasicConstraints' "pathLenConstraint", with the
+	// same semantics as in crypto/x509: a MaxPathLen of -1, or of 0 with
+	// MaxPathLenZero false, means that the constraint is unset.
+	MaxPathLen     int
+	MaxPathLenZero bool
+
+	SubjectKeyId   []byte
+	AuthorityKeyId []byte
+
+	// DNSNames are the DNS names of the Subject Alternate Name extension.
+	DNSNames []string
+}
+
+// Equal reports whether c and other are the same certificate.
+func (c *Certificate) Equal(other *Certificate) bool {
+	if c == nil || other == nil {
+		return c == other
+	}
+	return bytes.Equal(c.Raw, other.Raw)
+}
+
+// CreateCertificate creates a new X.509 v3 certificate based on a template,
+// and returns it in DER form. The following members of template are currently used:
+//
+//   - AuthorityKeyId
+//   - BasicConstraintsValid
+//   - DNSNames
+//   - ExtKeyUsage
+//   - ExtraExtensions
+//   - IsCA
+//   - KeyUsage
+//   - MaxPathLen
+//   - MaxPathLenZero
+//   - NotAfter
+//   - NotBefore
+//   - SerialNumber
+//   - Subject
+//   - SubjectKeyId
+//
+// The certificate is signed by priv with pure ML-DSA and the empty context, and
+// the signature algorithm is the one of the parameter set of priv. parent is the
+// issuer of the certificate; if parent is equal to template, the certificate is
+// self-signed. If parent.PublicKey is set, it must match priv.
+//
+// pub is the public key of the subject, which may be of any ML-DSA parameter set.
+//
+// If SerialNumber is nil, a random 20-byte serial number is generated from rand.
+// If SubjectKeyId is empty and IsCA is true, it is set to the leftmost 160 bits of
+// the SHA-256 hash of the public key, as specified in RFC 7093, Section 2.
+// If rand is nil, [crypto/rand] is used.
+//
+// [crypto/rand]: https://pkg.go.dev/crypto/rand
+func CreateCertificate(rand io.Reader, template, parent *Certificate, pub mldsa.PublicKey, priv mldsa.PrivateKey) ([]byte, error) {
+	if rand == nil {
+		rand = cryptorand.Reader
+	}
+	scheme := mldsa.SchemeOf(priv)
+	if scheme == nil {
+		return nil, errors.New("x509: unsupported private key type")
+	}
+	if parent.PublicKey != nil && !bytes.Equal(parent.PublicKey.Bytes(), priv.Public().(mldsa.PublicKey).Bytes()) {
+		return nil, errors.New("x509: provided PrivateKey doesn't match parent's PublicKey")
+	}
+
+	spki, err := mldsa.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+
+	serialNumber := template.SerialNumber
+	if serialNumber == nil {
+		serialBytes := make([]byte, 20)
+		if _, err := io.ReadFull(rand, serialBytes); err != nil {
+			return nil, err
+		}
+		// Clear the top bit, so that the serial number is positive
+		serialBytes[0] &= 0x7f
+		serialNumber = new(big.Int).SetBytes(serialBytes)
+	}
+	if serialNumber.Sign() < 0 {
+		return nil, errors.New("x509: serial number must be positive")
+	}
+
+	subject, err := marshalName(template.RawSubject, template.Subject)
+	if err != nil {
+		return nil, err
+	}
+	issuer, err := marshalName(parent.RawSubject, parent.Subject)
+	if err != nil {
+		return nil, err
+	}
+
+	subjectKeyId := template.SubjectKeyId
+	if len(subjectKeyId) == 0 && template.IsCA {
+		h := sha256.Sum256(pub.Bytes())
+		subjectKeyId = h[:20]
+	}
+	authorityKeyId := template.AuthorityKeyId
+	if !bytes.Equal(issuer, subject) && len(parent.SubjectKeyId) > 0 {
+		authorityKeyId = parent.SubjectKeyId
+	}
+
+	extensions, err := buildCertExtensions(template, bytes.Equal(subject, emptyName), subjectKeyId, authorityKeyId)
+	if err != nil {
+		return nil, err
+	}
+
+	var b cryptobyte.Builder
+	b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1(cbasn1.Tag(0).Constructed().ContextSpecific(), func(b *cryptobyte.Builder) {
+			b.AddASN1Int64(2) // v3
+		})
+		b.AddASN1BigInt(serialNumber)
+		addAlgorithmIdentifier(b, scheme.OID())
+		b.AddBytes(issuer)
+		b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			addTime(b, template.NotBefore)
+			addTime(b, template.NotAfter)
+		})
+		b.AddBytes(subject)
+		b.AddBytes(spki)
+		if len(extensions) > 0 {
+			b.AddASN1(cbasn1.Tag(3).Constructed().ContextSpecific(), func(b *cryptobyte.Builder) {
+				addExtensions(b, extensions)
+			})
+		}
+	})
+	tbs, err := b.Bytes()
+	if err != nil {
+		return nil, err
+	}
+
+	return signTBS(rand, tbs, scheme, priv)
+}
+
+// signTBS signs the to-be-signed part of a certificate or certificate request, and
+// returns the complete structure, which has the same shape for both.
+func signTBS(rand io.Reader, tbs []byte, scheme mldsa.Scheme, priv mldsa.PrivateKey) ([]byte, error) {
+	signature, err := priv.Sign(rand, tbs, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var b cryptobyte.Builder
+	b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddBytes(tbs)
+		addAlgorithmIdentifier(b, scheme.OID())
+		b.AddASN1BitString(signature)
+	})
+	return b.Bytes()
+}
+
+// CheckSignatureFrom verifies that the signature on c is a valid signature from parent.
+//
+// Unless parent is a version 1 certificate, it must be a CA with the
+// KeyUsageCertSign key usage, if any key usage is set.
+func (c *Certificate) CheckSignatureFrom(parent *Certificate) error {
+	if parent.Version == 3 && !parent.BasicConstraintsValid ||
+		parent.BasicConstraintsValid && !parent.IsCA {
+		return errors.New("x509: parent certificate cannot sign this kind of certificate")
+	}
+	if parent.KeyUsage != 0 && parent.KeyUsage&KeyUsageCertSign == 0 {
+		return errors.New("x509: parent certificate cannot sign this kind of certificate")
+	}
+	return checkSignature(c.SignatureAlgorithm, c.RawTBSCertificate, c.Signature, parent.PublicKey)
+}
+
+// CheckSignature verifies that signature is a valid signature over signed from
+// c's public key, with the signature algorithm algo.
+func (c *Certificate) CheckSignature(algo asn1.ObjectIdentifier, signed, signature []byte) error {
+	return checkSignature(algo, signed, signature, c.PublicKey)
+}
+
+func checkSignature(algo asn1.ObjectIdentifier, signed, signature []byte, pub mldsa.PublicKey) error {
+	scheme := mldsa.SchemeByOID(algo)
+	if scheme == nil {
+		return errors.New("x509: cannot verify signature: algorithm unimplemented")
+	}
+	if mldsa.SchemeOf(pub) != scheme {
+		return errors.New("x509: signature algorithm specifies an " + scheme.Name() + " public key")
+	}
+	// Certificates are signed with the empty context string
+	if !pub.Verify(signed, signature) {
+		return errors.New("x509: ML-DSA verification failure")
+	}
+	return nil
+}
+
+// emptyName is the DER encoding of an empty Name.
+var emptyName = []byte{0x30, 0x00}
+
+func marshalName(raw []byte, name pkix.Name) ([]byte, error) {
+	if len(raw) > 0 {
+		return raw, nil
+	}
+	return asn1.Marshal(name.ToRDNSequence())
+}
+
+// addAlgorithmIdentifier adds an AlgorithmIdentifier with absent parameters,
+// as required for ML-DSA by RFC 9881.
+func addAlgorithmIdentifier(b *cryptobyte.Builder, oid asn1.ObjectIdentifier) {
+	b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		b.AddASN1ObjectIdentifier(oid)
+	})
+}
+
+// addTime adds a UTCTime for dates from 1950 through 2049, and a GeneralizedTime
+// otherwise, as required by RFC 5280, Section 4.1.2.5.
+func addTime(b *cryptobyte.Builder, t time.Time) {
+	t = t.UTC().Truncate(time.Second)
+	if t.Year() >= 1950 && t.Year() < 2050 {
+		b.AddASN1UTCTime(t)
+	} else {
+		b.AddASN1GeneralizedTime(t)
+	}
+}
+
+func addExtensions(b *cryptobyte.Builder, extensions []pkix.Extension) {
+	b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		for _, ext := range extensions {
+			b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+				b.AddASN1ObjectIdentifier(ext.Id)
+				if ext.Critical {
+					b.AddASN1Boolean(true)
+				}
+				b.AddASN1OctetString(ext.Value)
+			})
+		}
+	})
+}
+
+func buildCertExtensions(template *Certificate, subjectIsEmpty bool, subjectKeyId, authorityKeyId []byte) ([]pkix.Extension, error) {
+	var exts []pkix.Extension
+
+	if template.KeyUsage != 0 && !oidInExtensions(oidExtensionKeyUsage, template.ExtraExtensions) {
+		ext, err := marshalKeyUsage(template.KeyUsage)
+		if err != nil {
+			return nil, err
+		}
+		exts = append(exts, ext)
+	}
+
+	if len(template.ExtKeyUsage) > 0 && !oidInExtensions(oidExtensionExtendedKeyUsage, template.ExtraExtensions) {
+		value, err := asn1.Marshal(template.ExtKeyUsage)
+		if err != nil {
+			return nil, err
+		}
+		exts = append(exts, pkix.Extension{Id: oidExtensionExtendedKeyUsage, Value: value})
+	}
+
+	if template.BasicConstraintsValid && !oidInExtensions(oidExtensionBasicConstraints, template.ExtraExtensions) {
+		ext, err := marshalBasicConstraints(template.IsCA, template.MaxPathLen, template.MaxPathLenZero)
+		if err != nil {
+			return nil, err
+		}
+		exts = append(exts, ext)
+	}
+
+	if len(subjectKeyId) > 0 && !oidInExtensions(oidExtensionSubjectKeyId, template.ExtraExtensions) {
+		value, err := asn1.Marshal(subjectKeyId)
+		if err != nil {
+			return nil, err
+		}
+		exts = append(exts, pkix.Extension{Id: oidExtensionSubjectKeyId, Value: value})
+	}
+
+	if len(authorityKeyId) > 0 && !oidInExtensions(oidExtensionAuthorityKeyId, template.ExtraExtensions) {
+		var b cryptobyte.Builder
+		b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+			b.AddASN1(cbasn1.Tag(0).ContextSpecific(), func(b *cryptobyte.Builder) {
+				b.AddBytes(authorityKeyId)
+			})
+		})
+		value, err := b.Bytes()
+		if err != nil {
+			return nil, err
+		}
+		exts = append(exts, pkix.Extension{Id: oidExtensionAuthorityKeyId, Value: value})
+	}
+
+	if len(template.DNSNames) > 0 && !oidInExtensions(oidExtensionSubjectAltName, template.ExtraExtensions) {
+		ext, err := marshalSANs(template.DNSNames, subjectIsEmpty)
+		if err != nil {
+			return nil, err
+		}
+		exts = append(exts, ext)
+	}
+
+	return append(exts, template.ExtraExtensions...), nil
+}
+
+func marshalKeyUsage(ku KeyUsage) (pkix.Extension, error) {
+	// Bit i of the BIT STRING is the key usage 1 << i. DER requires
+	// trailing zero bits to be removed.
+	bs := asn1.BitString{Bytes: []byte{reverseBits(byte(ku)), reverseBits(byte(ku >> 8))}}
+	if bs.Bytes[1] == 0 {
+		bs.Bytes = bs.Bytes[:1]
+	}
+	bs.BitLength = len(bs.Bytes) * 8
+	for bs.BitLength > 0 && bs.At(bs.BitLength-1) == 0 {
+		bs.BitLength--
+	}
+	value, err := asn1.Marshal(bs)
+	return pkix.Extension{Id: oidExtensionKeyUsage, Critical: true, Value: value}, err
+}
+
+func reverseBits(b byte) byte {
+	var r byte
+	for i := 0; i < 8; i++ {
+		r = r<<1 | b&1
+		b >>= 1
+	}
+	return r
+}
+
+// basicConstraints is the BasicConstraints extension of RFC 5280, Section 4.2.1.9.
+type basicConstraints struct {
+	IsCA       bool `asn1:"optional"`
+	MaxPathLen int  `asn1:"optional,default:-1"`
+}
+
+func marshalBasicConstraints(isCA bool, maxPathLen int, maxPathLenZero bool) (pkix.Extension, error) {
+	// Leaving MaxPathLen as zero indicates that no maximum path
+	// length is desired, unless MaxPathLenZero is set.
+	if maxPathLen == 0 && !maxPathLenZero || maxPathLen < 0 {
+		maxPathLen = -1
+	}
+	value, err := asn1.Marshal(basicConstraints{isCA, maxPathLen})
+	return pkix.Extension{Id: oidExtensionBasicConstraints, Critical: true, Value: value}, err
+}
+
+func marshalSANs(dnsNames []string, subjectIsEmpty bool) (pkix.Extension, error) {
+	var b cryptobyte.Builder
+	b.AddASN1(cbasn1.SEQUENCE, func(b *cryptobyte.Builder) {
+		for _, name := range dnsNames {
+			b.AddASN1(cbasn1.Tag(nameTypeDNS).ContextSpecific(), func(b *cryptobyte.Builder) {
+				b.AddBytes([]byte(name))
+			})
+		}
+	})
+	value, err := b.Bytes()
+	// The extension must be critical if the subject is empty (RFC 5280, Section 4.2.1.6)
+	return pkix.Extension{Id: oidExtensionSubjectAltName, Critical: subjectIsEmpty, Value: value}, err
+}
+
+func oidInExtensions(oid asn1.ObjectIdentifier, extensions []pkix.Extension) bool {
+	for _, e := range extensions {
+		if e.Id.Equal(oid) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,265 @@
+// Copyright 2025 Trail of Bits. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package x509_test
+
+import (
+	"crypto/rand"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"encoding/hex"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/trailofbits/ml-dsa/mldsa"
+	options "github.com/trailofbits/ml-dsa/options"
+	"github.com/trailofbits/ml-dsa/x509"
+)
+
+var (
+	now       = time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	notBefore = now.Add(-time.Hour)
+	notAfter  = now.Add(365 * 24 * time.Hour)
+)
+
+// The certificates in testdata are the self-signed examples from RFC 9881, Appendix C,
+// whose private keys are derived from the seed 000102...1f.
+func TestParseCertificateRFC9881(t *testing.T) {
+	seed, err := hex.DecodeString("000102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f")
+	assert.NoError(t, err)
+
+	for _, s := range mldsa.Schemes() {
+		t.Run(s.Name(), func(t *testing.T) {
+			data, err := os.ReadFile("testdata/ml-dsa-" + s.Name()[len("ML-DSA-"):] + ".crt.pem")
+			assert.NoError(t, err)
+			block, _ := pem.Decode(data)
+			if block == nil {
+				t.Fatal("no PEM block found")
+			}
+			cert, err := x509.ParseCertificate(block.Bytes)
+			assert.NoError(t, err)
+
+			priv, err := s.PrivateKeyFromSeed(seed)
+			assert.NoError(t, err)
+			assert.Equal(t, priv.Public(), cert.PublicKey)
+			assert.Equal(t, s.OID(), cert.SignatureAlgorithm)
+			assert.Equal(t, 3, cert.Version)
+			assert.Equal(t, "LAMPS WG", cert.Subject.CommonName)
+			assert.Equal(t, []string{"IETF"}, cert.Issuer.Organization)
+			assert.True(t, cert.BasicConstraintsValid)
+			assert.True(t, cert.IsCA)
+			assert.Equal(t, x509.KeyUsageDigitalSignature|x509.KeyUsageCertSign|x509.KeyUsageCRLSign, cert.KeyUsage)
+			assert.NotEmpty(t, cert.SubjectKeyId)
+
+			assert.NoError(t, cert.CheckSignatureFrom(cert))
+			roots := x509.NewCertPool()
+			assert.True(t, roots.AppendCertsFromPEM(data))
+			chains, err := cert.Verify(x509.VerifyOptions{Roots: roots, CurrentTime: now})
+			assert.NoError(t, err)
+			assert.Equal(t, [][]*x509.Certificate{{cert}}, chains)
+
+			_, err = cert.Verify(x509.VerifyOptions{Roots: roots, CurrentTime: cert.NotAfter.Add(time.Second)})
+			assert.ErrorContains(t, err, "expired")
+		})
+	}
+}
+
+type testCA struct {
+	cert *x509.Certificate
+	priv mldsa.PrivateKey
+}
+
+func issue(t *testing.T, template *x509.Certificate, parent *testCA, pub mldsa.PublicKey, priv mldsa.PrivateKey) *x509.Certificate {
+	parentCert, signer := template, priv
+	if parent != nil {
+		parentCert, signer = parent.cert, parent.priv
+	}
+	if template.NotBefore.IsZero() {
+		template.NotBefore, template.NotAfter = notBefore, notAfter
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parentCert, pub, signer)
+	assert.NoError(t, err)
+	cert, err := x509.ParseCertificate(der)
+	assert.NoError(t, err)
+	return cert
+}
+
+func newCA(t *testing.T, s mldsa.Scheme, name string, parent *testCA, maxPathLen int) *testCA {
+	pub, priv, err := s.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		Subject:               pkix.Name{CommonName: name},
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageCRLSign,
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+		MaxPathLen:            maxPathLen,
+		MaxPathLenZero:        maxPathLen == 0,
+	}
+	return &testCA{issue(t, template, parent, pub, priv), priv}
+}
+
+func TestCreateCertificateChain(t *testing.T) {
+	root := newCA(t, mldsa.MLDSA87, "Root CA", nil, -1)
+	intermediate := newCA(t, mldsa.MLDSA65, "Intermediate CA", root, 0)
+
+	leafPub, _, err := mldsa.MLDSA44.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	leaf := issue(t, &x509.Certificate{
+		SerialNumber: big.NewInt(42),
+		Subject:      pkix.Name{CommonName: "leaf", Organization: []string{"Trail of Bits"}},
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []asn1.ObjectIdentifier{{1, 3, 6, 1, 5, 5, 7, 3, 1}},
+		DNSNames:     []string{"example.com", "www.example.com"},
+	}, intermediate, leafPub, nil)
+
+	assert.Equal(t, 3, leaf.Version)
+	assert.Equal(t, big.NewInt(42), leaf.SerialNumber)
+	assert.Equal(t, "leaf", leaf.Subject.CommonName)
+	assert.Equal(t, "Intermediate CA", leaf.Issuer.CommonName)
+	assert.Equal(t, notBefore, leaf.NotBefore)
+	assert.Equal(t, notAfter, leaf.NotAfter)
+	assert.Equal(t, leafPub, leaf.PublicKey)
+	assert.Equal(t, mldsa.MLDSA65.OID(), leaf.SignatureAlgorithm)
+	assert.Equal(t, x509.KeyUsageDigitalSignature, leaf.KeyUsage)
+	assert.Equal(t, []asn1.ObjectIdentifier{{1, 3, 6, 1, 5, 5, 7, 3, 1}}, leaf.ExtKeyUsage)
+	assert.Equal(t, []string{"example.com", "www.example.com"}, leaf.DNSNames)
+	assert.False(t, leaf.BasicConstraintsValid)
+	assert.Equal(t, intermediate.cert.SubjectKeyId, leaf.AuthorityKeyId)
+	assert.Empty(t, leaf.SubjectKeyId)
+
+	assert.True(t, intermediate.cert.IsCA)
+	assert.True(t, intermediate.cert.MaxPathLenZero)
+	assert.Equal(t, -1, root.cert.MaxPathLen)
+	assert.Equal(t, root.cert.SubjectKeyId, intermediate.cert.AuthorityKeyId)
+	assert.Len(t, root.cert.SubjectKeyId, 20)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root.cert)
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(intermediate.cert)
+	chains, err := leaf.Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates, CurrentTime: now})
+	assert.NoError(t, err)
+	assert.Equal(t, [][]*x509.Certificate{{leaf, intermediate.cert, root.cert}}, chains)
+
+	// Without the intermediate, there is no chain
+	_, err = leaf.Verify(x509.VerifyOptions{Roots: roots, CurrentTime: now})
+	assert.ErrorContains(t, err, "unknown authority")
+	// The leaf is not yet valid
+	_, err = leaf.Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates, CurrentTime: notBefore.Add(-time.Second)})
+	assert.ErrorContains(t, err, "not valid before")
+}
+
+func TestVerifyPathLenConstraint(t *testing.T) {
+	root := newCA(t, mldsa.MLDSA65, "Root CA", nil, 0)
+	intermediate := newCA(t, mldsa.MLDSA65, "Intermediate CA", root, -1)
+	leafPub, _, err := mldsa.MLDSA65.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	leaf := issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "leaf"}}, intermediate, leafPub, nil)
+
+	roots := x509.NewCertPool()
+	roots.AddCert(root.cert)
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(intermediate.cert)
+
+	// The root can directly issue the intermediate, but not a chain through it
+	_, err = intermediate.cert.Verify(x509.VerifyOptions{Roots: roots, CurrentTime: now})
+	assert.NoError(t, err)
+	_, err = leaf.Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates, CurrentTime: now})
+	assert.ErrorContains(t, err, "path length constraint")
+}
+
+func TestVerifyRejectsInvalidIssuers(t *testing.T) {
+	root := newCA(t, mldsa.MLDSA44, "Root CA", nil, -1)
+	roots := x509.NewCertPool()
+	roots.AddCert(root.cert)
+
+	// A certificate that is not a CA cannot issue certificates
+	notCAPub, notCAPriv, err := mldsa.MLDSA44.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	notCA := &testCA{issue(t, &x509.Certificate{
+		Subject:               pkix.Name{CommonName: "Not a CA"},
+		BasicConstraintsValid: true,
+	}, root, notCAPub, nil), notCAPriv}
+	leafPub, _, err := mldsa.MLDSA44.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	leaf := issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "leaf"}}, notCA, leafPub, nil)
+	intermediates := x509.NewCertPool()
+	intermediates.AddCert(notCA.cert)
+	_, err = leaf.Verify(x509.VerifyOptions{Roots: roots, Intermediates: intermediates, CurrentTime: now})
+	assert.ErrorContains(t, err, "cannot sign")
+
+	// A certificate signed by another key with the same name
+	otherRoot := newCA(t, mldsa.MLDSA44, "Root CA", nil, -1)
+	leaf = issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "leaf"}}, otherRoot, leafPub, nil)
+	leaf.AuthorityKeyId = nil
+	_, err = leaf.Verify(x509.VerifyOptions{Roots: roots, CurrentTime: now})
+	assert.ErrorContains(t, err, "verification failure")
+
+	// A certificate with an unhandled critical extension
+	leaf = issue(t, &x509.Certificate{
+		Subject:         pkix.Name{CommonName: "leaf"},
+		ExtraExtensions: []pkix.Extension{{Id: asn1.ObjectIdentifier{1, 2, 3}, Critical: true, Value: []byte{0x05, 0x00}}},
+	}, root, leafPub, nil)
+	assert.Equal(t, []asn1.ObjectIdentifier{{1, 2, 3}}, leaf.UnhandledCriticalExtensions)
+	_, err = leaf.Verify(x509.VerifyOptions{Roots: roots, CurrentTime: now})
+	assert.ErrorContains(t, err, "unhandled critical extension")
+}
+
+func TestCheckSignature(t *testing.T) {
+	root := newCA(t, mldsa.MLDSA44, "Root CA", nil, -1)
+	leafPub, _, err := mldsa.MLDSA44.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	leaf := issue(t, &x509.Certificate{Subject: pkix.Name{CommonName: "leaf"}}, root, leafPub, nil)
+	assert.NoError(t, leaf.CheckSignatureFrom(root.cert))
+
+	leaf.Signature[0] ^= 1
+	assert.Error(t, leaf.CheckSignatureFrom(root.cert))
+	leaf.Signature[0] ^= 1
+
+	// Signatures with a context string are not certificate signatures
+	sig, err := root.priv.Sign(rand.Reader, leaf.RawTBSCertificate, &options.Options{Context: "context"})
+	assert.NoError(t, err)
+	assert.Error(t, root.cert.CheckSignature(leaf.SignatureAlgorithm, leaf.RawTBSCertificate, sig))
+	assert.Error(t, root.cert.CheckSignature(mldsa.MLDSA65.OID(), leaf.RawTBSCertificate, leaf.Signature))
+	assert.NoError(t, root.cert.CheckSignature(leaf.SignatureAlgorithm, leaf.RawTBSCertificate, leaf.Signature))
+}
+
+func TestCreateCertificateErrors(t *testing.T) {
+	root := newCA(t, mldsa.MLDSA44, "Root CA", nil, -1)
+	pub, priv, err := mldsa.MLDSA44.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+
+	// The private key does not match the parent certificate
+	_, err = x509.CreateCertificate(rand.Reader, &x509.Certificate{}, root.cert, pub, priv)
+	assert.Error(t, err)
+	_, err = x509.CreateCertificate(rand.Reader, &x509.Certificate{SerialNumber: big.NewInt(-1)}, root.cert, pub, root.priv)
+	assert.Error(t, err)
+	_, err = x509.CreateCertificate(rand.Reader, &x509.Certificate{}, root.cert, nil, root.priv)
+	assert.Error(t, err)
+}
+
+func TestParseCertificateMalformed(t *testing.T) {
+	root := newCA(t, mldsa.MLDSA44, "Root CA", nil, -1)
+	_, err := x509.ParseCertificate(root.cert.Raw[:len(root.cert.Raw)-1])
+	assert.Error(t, err)
+	_, err = x509.ParseCertificate(append(root.cert.Raw, 0))
+	assert.Error(t, err)
+
+	// Duplicate extension
+	pub, _, err := mldsa.MLDSA44.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	ext := pkix.Extension{Id: asn1.ObjectIdentifier{1, 2, 3}, Value: []byte{0x05, 0x00}}
+	der, err := x509.CreateCertificate(rand.Reader, &x509.Certificate{
+		ExtraExtensions: []pkix.Extension{ext, ext},
+	}, root.cert, pub, root.priv)
+	assert.NoError(t, err)
+	_, err = x509.ParseCertificate(der)
+	assert.ErrorContains(t, err, "duplicate extension")
+}